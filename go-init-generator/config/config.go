@@ -1,6 +1,9 @@
 package config
 
 import (
+	"go-init-observability/health"
+	"go-init-observability/tracing"
+
 	"gitlab.com/go-init/go-init-common/default/grpcpkg"
 	"gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/logger"
@@ -20,6 +23,8 @@ type AppConfig struct {
 	GrpcServ    grpcpkg.ServerConfig `yaml:"grpc_server"`
 	Kafka       kafka.Config         `yaml:"kafka"`
 	GrpcClients grpcpkg.ClientConfig `yaml:"grpc_client"`
+	Tracing     tracing.Config       `yaml:"tracing"`
+	Health      health.Config        `yaml:"health"`
 }
 
 func GetConfig() *AppConfig {
@@ -27,5 +32,7 @@ func GetConfig() *AppConfig {
 	c.OpenConfig(&config)
 	defaults.SetDefaults(&config.Logger)
 	defaults.SetDefaults(&config.GrpcClients)
+	defaults.SetDefaults(&config.Tracing)
+	defaults.SetDefaults(&config.Health)
 	return config
 }
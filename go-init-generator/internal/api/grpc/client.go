@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	externalgrpc "go-init-gen/pkg/api/grpc/external"
+	"go-init-observability/tracing"
 
 	"github.com/google/uuid"
 	"gitlab.com/go-init/go-init-common/default/grpcpkg"
@@ -40,8 +41,8 @@ func (c *PublisherClient) StreamArchive(ctx context.Context, archiveID string, d
 	hashString := hex.EncodeToString(hash[:])
 	c.logger.Info(fmt.Sprintf("Calculated hash for archive %s: %s", archiveID, hashString))
 
-	// Создаем клиентский стрим
-	stream, err := c.archivePublisher.StreamArchive(ctx)
+	// Создаем клиентский стрим; контекст трассировки передается в метаданных
+	stream, err := c.archivePublisher.StreamArchive(tracing.InjectMetadata(ctx))
 	if err != nil {
 		return fmt.Errorf("ошибка при создании стрима: %w", err)
 	}
@@ -2,19 +2,28 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
 	"go-init-gen/config"
 	"go-init-gen/internal/api/grpc"
+	"go-init-gen/internal/eventdata"
 	"go-init-gen/internal/work"
+	"go-init-observability/health"
+	"go-init-observability/tracing"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/twmb/franz-go/pkg/kgo"
 	database "gitlab.com/go-init/go-init-common/default/db/pg/orm"
 	"gitlab.com/go-init/go-init-common/default/grpcpkg"
 	"gitlab.com/go-init/go-init-common/default/kafka"
 
 	"gitlab.com/go-init/go-init-common/default/closer"
+	myhttp "gitlab.com/go-init/go-init-common/default/http"
+	myserver "gitlab.com/go-init/go-init-common/default/http/server"
 	"gitlab.com/go-init/go-init-common/default/logger"
 )
 
@@ -23,14 +32,18 @@ type App struct {
 	log             *logger.Logger
 	db              *database.AgentImpl
 	KafkaConsumer   *kafka.ClientConfig
+	KafkaProducer   *kafka.ClientConfig
 	worker          *work.Worker
 	publisherClient *grpc.PublisherClient
+	srv             *http.Server
+	health          *health.Checker
 	cancelFunc      context.CancelFunc
 }
 
 const (
 	serviceName     = "go-init-generator"
 	shutDownTimeOut = time.Second * 5
+	metricNamespace = "go_init_generator" // префикс метрик сервиса
 )
 
 func New(ctx context.Context) (*App, error) {
@@ -51,10 +64,13 @@ func (a *App) initDeps(ctx context.Context) error {
 		a.initConfig,
 		a.initLogger,
 		a.initCloser,
+		a.initTracing,
 		a.initDb,
 		a.initKafka,
 		a.initPublisherClient,
 		a.initServices,
+		a.initHealth,
+		a.initHttpServer,
 	}
 	for _, f := range inits {
 		err := f(ctx)
@@ -75,9 +91,15 @@ func (a *App) initKafka(_ context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to initialize kafka client: %w", err)
 	}
+	if k == nil {
+		return nil
+	}
 	if k.ConsumerIsEnabled() {
 		a.KafkaConsumer = k
 	}
+	if k.ProducerIsEnabled() {
+		a.KafkaProducer = k
+	}
 
 	return nil
 }
@@ -92,6 +114,21 @@ func (a *App) initCloser(_ context.Context) error {
 	return nil
 }
 
+// initTracing настраивает экспорт спанов; оставшиеся спаны отправляются при остановке
+func (a *App) initTracing(ctx context.Context) error {
+	shutdown, err := tracing.Init(ctx, &a.cfg.Tracing, serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	closer.Add(func() error {
+		cancelCtx, cancel := context.WithTimeout(context.Background(), shutDownTimeOut)
+		defer cancel()
+		return shutdown(cancelCtx)
+	})
+	a.log.Info(fmt.Sprintf("Tracing exporter: %s", a.cfg.Tracing.Exporter))
+	return nil
+}
+
 func (a *App) initPublisherClient(_ context.Context) error {
 	if a.log == nil || a.cfg == nil {
 		return fmt.Errorf("logger or config not initialized")
@@ -131,8 +168,8 @@ func (a *App) initServices(ctx context.Context) error {
 		return fmt.Errorf("logger or config not initialized")
 	}
 
-	// Create the worker with publisher client
-	a.worker = work.NewWorker(ctx, a.log, a.KafkaConsumer, a.publisherClient)
+	// Create the worker with publisher client and producer for failure reports
+	a.worker = work.NewWorker(ctx, a.log, a.KafkaConsumer, a.KafkaProducer, a.doneTopic(), a.publisherClient)
 
 	// Then register it with Kafka if consumer is enabled
 	if a.KafkaConsumer != nil && a.KafkaConsumer.ConsumerIsEnabled() {
@@ -155,6 +192,71 @@ func (a *App) initServices(ctx context.Context) error {
 	return nil
 }
 
+// doneTopic возвращает имя топика результатов генерации или пустую строку, если он не настроен
+func (a *App) doneTopic() string {
+	for _, topic := range a.cfg.Kafka.ProducerConfig.Topic {
+		if topic.Id == eventdata.DoneTopicID && topic.IsEnabled {
+			return topic.Name
+		}
+	}
+	return ""
+}
+
+// initHealth регистрирует проверки для /readyz: доступность брокеров Kafka и работа пула воркеров
+func (a *App) initHealth(_ context.Context) error {
+	a.health = health.NewChecker(&a.cfg.Health, a.log, metricNamespace)
+	if kafkaClient := a.kafkaClient(); kafkaClient != nil {
+		a.health.Add("kafka", kafkaClient.Ping)
+	}
+	a.health.Add("worker_pool", func(context.Context) error {
+		if !a.worker.Running() {
+			return errors.New("worker pool is not running")
+		}
+		return nil
+	})
+	return nil
+}
+
+// kafkaClient возвращает клиент Kafka, если потребитель или продюсер включен
+func (a *App) kafkaClient() *kgo.Client {
+	if a.KafkaConsumer != nil {
+		return a.KafkaConsumer.Client
+	}
+	if a.KafkaProducer != nil {
+		return a.KafkaProducer.Client
+	}
+	return nil
+}
+
+// initHttpServer поднимает HTTP сервер с Prometheus метриками (/metrics) и проверками /health, /healthz и /readyz
+func (a *App) initHttpServer(ctx context.Context) error {
+	s := myserver.NewServer(&a.cfg.HttpServ, nil, promhttp.Handler(), myhttp.CollectHandlers())
+	s.Handler = a.health.Routes(s.Handler)
+	closer.Add(func() error {
+		a.health.Shutdown()
+		cancelCtx, cancel := context.WithTimeout(ctx, shutDownTimeOut)
+		defer cancel()
+		if err := s.Shutdown(cancelCtx); err != nil {
+			return fmt.Errorf("failed to stop http server: %w", err)
+		}
+		a.log.Info("Http server stopped")
+		return nil
+	})
+
+	a.srv = &s.Server
+	return nil
+}
+
+func (a *App) runHttpServer() error {
+	a.log.Info(fmt.Sprintf("Starting HTTP server on %s", a.srv.Addr))
+	err := a.srv.ListenAndServe()
+	if err != nil && err != http.ErrServerClosed {
+		a.log.Error(fmt.Sprintf("HTTP server error: %v", err))
+		return err
+	}
+	return nil
+}
+
 func (a *App) initDb(ctx context.Context) error {
 	return nil
 }
@@ -180,6 +282,12 @@ func (a *App) Run() error {
 		}()
 	}
 
+	go func() {
+		if err := a.runHttpServer(); err != nil {
+			errChan <- err
+		}
+	}()
+
 	go func() {
 		a.log.Info("Starting worker...")
 		if err := a.worker.Start(); err != nil {
@@ -0,0 +1,13 @@
+package eventdata
+
+// CancelTemplateEventType тип события отмены генерации шаблона
+const CancelTemplateEventType = "cancel-template"
+
+// CancelTemplate просит прервать генерацию шаблона
+type CancelTemplate struct {
+	// ID идентификатор шаблона (совпадает с ProcessTemplate.ID)
+	ID string `json:"id"`
+
+	// CancelledAt время отмены в формате RFC3339Nano
+	CancelledAt string `json:"cancelledAt"`
+}
@@ -0,0 +1,33 @@
+package eventdata
+
+const (
+	// GenerationFailedEventType тип события о неудачной генерации шаблона
+	GenerationFailedEventType = "generation-failed"
+
+	// GenerationFailedSchema схема события о неудачной генерации
+	GenerationFailedSchema = "go-init-generation-failed-schema"
+
+	// DoneTopicID ID топика, в который публикуются результаты генерации
+	DoneTopicID = "go-init-done"
+)
+
+// Этапы генерации, на которых может произойти ошибка
+const (
+	StageGenerate = "generate"
+	StageStream   = "stream"
+)
+
+// GenerationFailed описывает ошибку генерации шаблона для менеджера
+type GenerationFailed struct {
+	// ID идентификатор шаблона (совпадает с ProcessTemplate.ID)
+	ID string `json:"id"`
+
+	// Stage этап, на котором произошла ошибка
+	Stage string `json:"stage"`
+
+	// Error текст ошибки
+	Error string `json:"error"`
+
+	// FailedAt время ошибки в формате RFC3339
+	FailedAt string `json:"failedAt"`
+}
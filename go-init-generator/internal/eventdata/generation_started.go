@@ -0,0 +1,18 @@
+package eventdata
+
+const (
+	// GenerationStartedEventType тип события о начале генерации шаблона
+	GenerationStartedEventType = "generation-started"
+
+	// GenerationStartedSchema схема события о начале генерации
+	GenerationStartedSchema = "go-init-generation-started-schema"
+)
+
+// GenerationStarted сообщает менеджеру, что шаблон взят в работу (статус PROCESSING)
+type GenerationStarted struct {
+	// ID идентификатор шаблона (совпадает с ProcessTemplate.ID)
+	ID string `json:"id"`
+
+	// StartedAt время начала генерации в формате RFC3339
+	StartedAt string `json:"startedAt"`
+}
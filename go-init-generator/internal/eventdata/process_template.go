@@ -41,6 +41,7 @@ type ProcessTemplate struct {
 	Data   TemplateEventData `json:"data"`
 }
 
+// JsonSchema describes the process-template event; it must match the schema published by go-init-manager/internal/eventdata
 const JsonSchema = `
 {
 	"type": "object",
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"go/ast"
 	"go/format"
@@ -34,11 +35,15 @@ func NewContentGenerator(templateDir string) *ContentGenerator {
 	}
 }
 
-// GenerateFiles generates the content for each file
-func (cg *ContentGenerator) GenerateFiles(files []TemplateFile, data *eventdata.TemplateEventData, variables map[string]interface{}) (map[string][]byte, error) {
+// GenerateFiles generates the content for each file, stopping as soon as ctx is cancelled
+func (cg *ContentGenerator) GenerateFiles(ctx context.Context, files []TemplateFile, data *eventdata.TemplateEventData, variables map[string]interface{}) (map[string][]byte, error) {
 	generatedFiles := make(map[string][]byte)
 
 	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		content, err := cg.generateFileContent(file, data, variables)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate content for %s: %w", file.Name, err)
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -98,6 +99,28 @@ func TestGenerator(t *testing.T) {
 	t.Log("Generator test completed successfully")
 }
 
+func TestGeneratorCancelled(t *testing.T) {
+	templatesPath, err := filepath.Abs(filepath.Join("..", "templates", "microservices"))
+	if err != nil {
+		t.Fatalf("Failed to get absolute path for templates: %v", err)
+	}
+	t.Setenv("TEMPLATE_DIR", templatesPath)
+	t.Setenv("GENERATOR_SAVE_ARCHIVE_LOCALLY", "false")
+
+	// A cancelled context must abort the pipeline instead of producing an archive
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	template := createTestTemplate()
+	archive, err := New().Generate(ctx, &template)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if archive != nil {
+		t.Error("Expected no archive for a cancelled generation")
+	}
+}
+
 // createTestTemplate creates a test template with sample data
 func createTestTemplate() eventdata.ProcessTemplate {
 	return eventdata.ProcessTemplate{
@@ -0,0 +1,21 @@
+package engine
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Этапы GenerationPipeline, значения метки stage
+const (
+	stagePrepareVariables = "prepare_variables"
+	stageLoadTemplates    = "load_templates"
+	stageFilterFiles      = "filter_files"
+	stageGenerateFiles    = "generate_files"
+	stageCreateArchive    = "create_archive"
+)
+
+var stageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "go_init_generator_pipeline_stage_duration_seconds",
+	Help:    "Duration of generation pipeline stages, by stage; failed stages are included.",
+	Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+}, []string{"stage"})
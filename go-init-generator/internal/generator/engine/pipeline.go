@@ -3,8 +3,10 @@ package engine
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go-init-gen/internal/eventdata"
+	"go-init-observability/tracing"
 )
 
 // GenerationPipeline orchestrates the code generation process
@@ -25,31 +27,51 @@ func NewGenerationPipeline(templateDir string, debugArchives bool, debugDir stri
 	}
 }
 
-// Execute runs the complete generation pipeline
+// Execute runs the complete generation pipeline.
+// Cancelling ctx aborts the generation between steps and between generated files.
+// Every step is recorded as a child span of the span in ctx.
 func (p *GenerationPipeline) Execute(ctx context.Context, template *eventdata.ProcessTemplate) ([]byte, error) {
 	// Step 1: Prepare template variables
+	endStage := startStage(ctx, stagePrepareVariables)
 	variables, err := p.prepareTemplateVariables(&template.Data)
+	endStage(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare template variables: %w", err)
 	}
 
 	// Step 2: Load template files
+	endStage = startStage(ctx, stageLoadTemplates)
 	files, err := p.templateLoader.LoadTemplateFiles()
+	endStage(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load template files: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Step 3: Filter files based on features
+	endStage = startStage(ctx, stageFilterFiles)
 	filesToGenerate := p.fileFilter.FilterFiles(files, &template.Data)
+	endStage(nil)
 
 	// Step 4: Generate file content
-	generatedFiles, err := p.contentGenerator.GenerateFiles(filesToGenerate, &template.Data, variables)
+	endStage = startStage(ctx, stageGenerateFiles)
+	generatedFiles, err := p.contentGenerator.GenerateFiles(ctx, filesToGenerate, &template.Data, variables)
+	endStage(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate files: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Step 5: Create archive
+	endStage = startStage(ctx, stageCreateArchive)
 	archiveBytes, err := p.archiver.CreateArchive(generatedFiles, template.ID)
+	endStage(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create archive: %w", err)
 	}
@@ -70,3 +92,14 @@ func (p *GenerationPipeline) prepareTemplateVariables(data *eventdata.TemplateEv
 
 	return variables, nil
 }
+
+// startStage starts the span of a pipeline stage. The returned func records the stage duration
+// and ends the span with the error of the stage, if any.
+func startStage(ctx context.Context, stage string) func(error) {
+	start := time.Now()
+	_, span := tracing.Start(ctx, "pipeline."+stage)
+	return func(err error) {
+		stageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+		tracing.End(span, err)
+	}
+}
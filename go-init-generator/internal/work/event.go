@@ -0,0 +1,30 @@
+package work
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-init-observability/tracing"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+)
+
+// newCloudEvent serializes data into the CloudEvent that kafka.ClientConfig.Produce would send,
+// with the trace context of ctx in the traceparent and tracestate extensions
+func newCloudEvent(ctx context.Context, eventType, schema string, data any) ([]byte, error) {
+	event := ce.NewEvent()
+	event.SetType(eventType)
+	event.SetExtension("correlation-id", uuid.New().String())
+	event.SetSource(serviceName)
+	event.SetDataSchema(schema)
+	event.SetTime(time.Now())
+	event.SetID(uuid.New().String())
+	tracing.InjectEvent(ctx, &event)
+
+	if err := event.SetData(ce.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("failed to set event data: %w", err)
+	}
+	return event.MarshalJSON()
+}
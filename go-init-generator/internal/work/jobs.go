@@ -0,0 +1,101 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// errGenerationCancelled is the cancellation cause of a job aborted by a cancel-template event
+	errGenerationCancelled = errors.New("generation cancelled")
+	// errGenerationRunning is returned by start for a redelivered event of a generation that is still running
+	errGenerationRunning = errors.New("generation is already running")
+)
+
+// pendingCancelTTL limits how long a cancellation waits for its process-template event
+const pendingCancelTTL = time.Hour
+
+// job is a running generation of one template
+type job struct {
+	cancel context.CancelCauseFunc
+}
+
+// jobRegistry tracks running generations by template ID so that they can be cancelled.
+// A cancellation that arrives before its process-template event is picked up by a worker
+// is kept as pending and applied when the generation is about to start.
+type jobRegistry struct {
+	mu      sync.Mutex
+	running map[string]*job
+	// pending holds the cancellation time of templates that are not running yet
+	pending map[string]time.Time
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{
+		running: make(map[string]*job),
+		pending: make(map[string]time.Time),
+	}
+}
+
+// start registers a generation requested at requestedAt. It returns errGenerationCancelled if the template
+// was cancelled after the request was made; a cancellation older than the request
+// (the template was retried after being cancelled) is discarded. A second generation of a template
+// that is still running is refused with errGenerationRunning: the running job reports the outcome.
+// The returned release func must be called when the generation is over.
+func (r *jobRegistry) start(parent context.Context, templateID string, requestedAt time.Time) (context.Context, func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cancelledAt, ok := r.pending[templateID]; ok {
+		delete(r.pending, templateID)
+		if requestedAt.IsZero() || !requestedAt.After(cancelledAt) {
+			return nil, nil, errGenerationCancelled
+		}
+	}
+	if _, ok := r.running[templateID]; ok {
+		return nil, nil, errGenerationRunning
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	j := &job{cancel: cancel}
+	r.running[templateID] = j
+
+	release := func() {
+		r.mu.Lock()
+		if r.running[templateID] == j {
+			delete(r.running, templateID)
+		}
+		r.mu.Unlock()
+		cancel(nil)
+	}
+	return ctx, release, nil
+}
+
+// cancel aborts a running generation of the template or remembers the cancellation
+// for a generation that has not started yet. It reports whether a running job was aborted.
+func (r *jobRegistry) cancel(templateID string, cancelledAt time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if j, ok := r.running[templateID]; ok {
+		j.cancel(errGenerationCancelled)
+		delete(r.running, templateID)
+		return true
+	}
+
+	// Cancellations of generations that already finished are never claimed, drop them eventually
+	for id, at := range r.pending {
+		if time.Since(at) > pendingCancelTTL {
+			delete(r.pending, id)
+		}
+	}
+	r.pending[templateID] = cancelledAt
+	return false
+}
+
+// isCancelled reports whether ctx of a job was cancelled by a cancel-template event
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errGenerationCancelled)
+}
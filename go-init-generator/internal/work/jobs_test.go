@@ -0,0 +1,154 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobRegistryStartAfterCancel(t *testing.T) {
+	cancelledAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		requestedAt time.Time
+		wantStarted bool
+	}{
+		{name: "requested before cancel", requestedAt: cancelledAt.Add(-time.Second), wantStarted: false},
+		{name: "requested at cancel time", requestedAt: cancelledAt, wantStarted: false},
+		{name: "request time unknown", requestedAt: time.Time{}, wantStarted: false},
+		{name: "retried after cancel", requestedAt: cancelledAt.Add(time.Second), wantStarted: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := newJobRegistry()
+			if registry.cancel("template", cancelledAt) {
+				t.Fatal("cancel reported a running job for a template that was not started")
+			}
+
+			ctx, release, err := registry.start(context.Background(), "template", tc.requestedAt)
+			if started := err == nil; started != tc.wantStarted {
+				t.Fatalf("start error = %v, want started %v", err, tc.wantStarted)
+			}
+			if err == nil {
+				if ctx.Err() != nil {
+					t.Fatalf("Started job context is done: %v", ctx.Err())
+				}
+				release()
+			}
+
+			// The pending cancellation is consumed by the first start either way
+			if _, ok := registry.pending["template"]; ok {
+				t.Fatal("Pending cancellation was not consumed by start")
+			}
+			_, release, err = registry.start(context.Background(), "template", cancelledAt.Add(-time.Hour))
+			if err != nil {
+				t.Fatalf("Second start was rejected by an already consumed cancellation: %v", err)
+			}
+			release()
+		})
+	}
+}
+
+func TestJobRegistryCancelRunning(t *testing.T) {
+	registry := newJobRegistry()
+	ctx, release, err := registry.start(context.Background(), "template", time.Now())
+	if err != nil {
+		t.Fatalf("start rejected a template without cancellations: %v", err)
+	}
+	defer release()
+
+	if !registry.cancel("template", time.Now()) {
+		t.Fatal("cancel did not report the running job")
+	}
+	if !isCancelled(ctx) {
+		t.Fatalf("Job context cause = %v, want %v", context.Cause(ctx), errGenerationCancelled)
+	}
+	if _, ok := registry.pending["template"]; ok {
+		t.Fatal("Cancellation of a running job was also kept as pending")
+	}
+
+	// A retry started after the cancellation runs normally
+	retryCtx, retryRelease, err := registry.start(context.Background(), "template", time.Now())
+	if err != nil {
+		t.Fatalf("start rejected a retry after the running job was cancelled: %v", err)
+	}
+	defer retryRelease()
+	if retryCtx.Err() != nil {
+		t.Fatalf("Retry context is done: %v", retryCtx.Err())
+	}
+}
+
+func TestJobRegistryReleaseKeepsNewerJob(t *testing.T) {
+	registry := newJobRegistry()
+	_, releaseFirst, _ := registry.start(context.Background(), "template", time.Now())
+	registry.cancel("template", time.Now())
+	second, releaseSecond, _ := registry.start(context.Background(), "template", time.Now())
+	defer releaseSecond()
+
+	// Release of the cancelled job must not unregister the job that replaced it
+	releaseFirst()
+	if !registry.cancel("template", time.Now()) {
+		t.Fatal("cancel did not find the job started after the cancelled one")
+	}
+	if !isCancelled(second) {
+		t.Fatal("Newer job was not cancelled")
+	}
+}
+
+func TestJobRegistryRefusesDuplicate(t *testing.T) {
+	registry := newJobRegistry()
+	ctx, release, err := registry.start(context.Background(), "template", time.Now())
+	if err != nil {
+		t.Fatalf("start rejected a template without cancellations: %v", err)
+	}
+	defer release()
+
+	// A redelivered event must neither replace the running job nor abort it
+	if _, _, err := registry.start(context.Background(), "template", time.Now()); !errors.Is(err, errGenerationRunning) {
+		t.Fatalf("Duplicate start error = %v, want %v", err, errGenerationRunning)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("Running job context is done: %v", ctx.Err())
+	}
+	if !registry.cancel("template", time.Now()) || !isCancelled(ctx) {
+		t.Fatal("cancel did not abort the job that was running before the duplicate")
+	}
+}
+
+func TestJobRegistryFinishedJobIsNotCancelled(t *testing.T) {
+	registry := newJobRegistry()
+	ctx, release, _ := registry.start(context.Background(), "template", time.Now())
+	release()
+
+	if registry.cancel("template", time.Now()) {
+		t.Fatal("cancel reported a job that already finished")
+	}
+	if isCancelled(ctx) {
+		t.Fatal("Finished job was marked as cancelled")
+	}
+}
+
+func TestJobRegistryPendingTTL(t *testing.T) {
+	registry := newJobRegistry()
+	registry.cancel("expired", time.Now().Add(-pendingCancelTTL-time.Minute))
+	registry.cancel("recent", time.Now().Add(-pendingCancelTTL+time.Minute))
+
+	// Every cancellation sweeps the pending ones older than the TTL
+	registry.cancel("other", time.Now())
+	if _, ok := registry.pending["expired"]; ok {
+		t.Fatal("Pending cancellation older than the TTL was not dropped")
+	}
+	if _, ok := registry.pending["recent"]; !ok {
+		t.Fatal("Pending cancellation within the TTL was dropped")
+	}
+
+	// A dropped cancellation no longer blocks its generation
+	_, release, err := registry.start(context.Background(), "expired", time.Now().Add(-2*pendingCancelTTL))
+	if err != nil {
+		t.Fatalf("start was rejected by an expired cancellation: %v", err)
+	}
+	release()
+}
@@ -0,0 +1,37 @@
+package work
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Результаты генерации, значения метки result
+const (
+	resultCompleted = "completed"
+	resultFailed    = "failed"
+	resultCancelled = "cancelled"
+)
+
+var (
+	queueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "go_init_generator_queue_depth",
+		Help: "Messages waiting in the worker pool queue.",
+	})
+	generations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "go_init_generator_generations_total",
+		Help: "Finished generations, by result (completed, failed, cancelled).",
+	}, []string{"result"})
+	streamDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "go_init_generator_archive_stream_duration_seconds",
+		Help:    "Time to stream a generated archive to the publisher, including failed streams.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	})
+	consumeErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_generator_kafka_consume_errors_total",
+		Help: "Consumed messages that are empty or not valid CloudEvents.",
+	})
+	produceErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_generator_kafka_produce_errors_total",
+		Help: "Status events that could not be published to the results topic.",
+	})
+)
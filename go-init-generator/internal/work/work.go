@@ -3,28 +3,38 @@ package work
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"go-init-gen/internal/api/grpc"
 	"go-init-gen/internal/eventdata"
 	"go-init-gen/internal/generator/engine"
+	"go-init-observability/tracing"
 
+	"github.com/twmb/franz-go/pkg/kgo"
 	"gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/logger"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const serviceName = "go-init-generator"
+
 type Worker struct {
 	ctx             context.Context
 	cancel          context.CancelFunc
 	log             *logger.Logger
 	kafkaConsumer   *kafka.ClientConfig
+	kafkaProducer   *kafka.ClientConfig
 	publisherClient *grpc.PublisherClient
+	doneTopic       string
 	messageChan     chan []byte
 	wg              sync.WaitGroup
 	workerCount     int
 	isRunning       bool
 	mu              sync.Mutex
+	jobs            *jobRegistry
 }
 
 // Add a new struct for CloudEvent format
@@ -36,18 +46,23 @@ type CloudEvent struct {
 	DataContentType string          `json:"datacontenttype"`
 	Time            string          `json:"time"`
 	Data            json.RawMessage `json:"data"`
+	// Контекст трассировки менеджера из расширений traceparent и tracestate
+	tracing.EventContext
 }
 
-func NewWorker(ctx context.Context, log *logger.Logger, kafkaConsumer *kafka.ClientConfig, publisherClient *grpc.PublisherClient) *Worker {
+func NewWorker(ctx context.Context, log *logger.Logger, kafkaConsumer *kafka.ClientConfig, kafkaProducer *kafka.ClientConfig, doneTopic string, publisherClient *grpc.PublisherClient) *Worker {
 	workerCtx, cancel := context.WithCancel(ctx)
 	return &Worker{
 		ctx:             workerCtx,
 		cancel:          cancel,
 		log:             log,
 		kafkaConsumer:   kafkaConsumer,
+		kafkaProducer:   kafkaProducer,
 		publisherClient: publisherClient,
+		doneTopic:       doneTopic,
 		messageChan:     make(chan []byte, 100),
 		workerCount:     5, // Configurable worker count
+		jobs:            newJobRegistry(),
 	}
 }
 
@@ -77,6 +92,13 @@ func (w *Worker) Start() error {
 	return nil
 }
 
+// Running reports whether the worker pool is started and not stopped
+func (w *Worker) Running() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.isRunning && w.ctx.Err() == nil
+}
+
 func (w *Worker) Stop() error {
 	w.log.Info("Stopping worker...")
 	w.cancel()
@@ -95,6 +117,7 @@ func (w *Worker) worker(id int) {
 	w.log.Info(fmt.Sprintf("Starting worker %d", id))
 
 	for message := range w.messageChan {
+		queueDepth.Set(float64(len(w.messageChan)))
 		// Check if context is canceled before processing
 		select {
 		case <-w.ctx.Done():
@@ -104,46 +127,178 @@ func (w *Worker) worker(id int) {
 			// Debug log the raw message
 			w.log.Debug(fmt.Sprintf("Worker %d received message: %s", id, string(message)))
 
-			// First unmarshal the CloudEvent structure
-			var cloudEvent CloudEvent
-			if err := json.Unmarshal(message, &cloudEvent); err != nil {
-				w.log.Error(fmt.Sprintf("Worker %d failed to unmarshal CloudEvent: %v", id, err))
-				continue
+			if err := w.handleMessage(message); err != nil {
+				w.log.Error(fmt.Sprintf("Worker %d failed to handle message: %v", id, err))
 			}
+		}
+	}
 
-			// Debug log the extracted data
-			w.log.Debug(fmt.Sprintf("Worker %d extracted CloudEvent data: %s", id, string(cloudEvent.Data)))
+	w.log.Info(fmt.Sprintf("Worker %d stopped", id))
+}
 
-			// Then unmarshal the data field into ProcessTemplate
-			var template eventdata.ProcessTemplate
-			if err := json.Unmarshal(cloudEvent.Data, &template); err != nil {
-				w.log.Error(fmt.Sprintf("Worker %d failed to unmarshal ProcessTemplate from CloudEvent data: %v", id, err))
-				continue
-			}
+// handleMessage разбирает CloudEvent из топика обработки и передает его обработчику по типу события
+func (w *Worker) handleMessage(message []byte) error {
+	var cloudEvent CloudEvent
+	if err := json.Unmarshal(message, &cloudEvent); err != nil {
+		consumeErrors.Inc()
+		return fmt.Errorf("failed to unmarshal CloudEvent: %w", err)
+	}
 
-			// Debug log the parsed template
-			w.log.Debug(fmt.Sprintf("Worker %d parsed template - ID: %s, Status: %s, Name: %s",
-				id, template.ID, template.Status, template.Data.Name))
+	// Debug log the extracted data
+	w.log.Debug(fmt.Sprintf("Extracted CloudEvent data: %s", string(cloudEvent.Data)))
 
-			// Process the message
-			w.log.Info(fmt.Sprintf("Worker %d processing message with template ID: %s", id, template.ID))
-			archive, err := w.generateArchive(template)
-			if err != nil {
-				w.log.Error(fmt.Sprintf("Worker %d failed to generate archive: %v", id, err))
-				continue
-			}
+	if cloudEvent.Type == eventdata.CancelTemplateEventType {
+		return w.handleCancel(cloudEvent)
+	}
 
-			err = w.streamArchive(archive, template.ID)
-			if err != nil {
-				w.log.Error(fmt.Sprintf("Worker %d failed to stream archive: %v", id, err))
-			}
+	// Then unmarshal the data field into ProcessTemplate
+	var template eventdata.ProcessTemplate
+	if err := json.Unmarshal(cloudEvent.Data, &template); err != nil {
+		consumeErrors.Inc()
+		return fmt.Errorf("failed to unmarshal ProcessTemplate from CloudEvent data: %w", err)
+	}
+
+	// Debug log the parsed template
+	w.log.Debug(fmt.Sprintf("Parsed template - ID: %s, Status: %s, Name: %s",
+		template.ID, template.Status, template.Data.Name))
+
+	// Время события нужно, чтобы отличить повторный запуск от отмененного
+	requestedAt, _ := time.Parse(time.RFC3339Nano, cloudEvent.Time)
+
+	w.log.Info(fmt.Sprintf("Processing message with template ID: %s", template.ID))
+	if err := w.processTemplate(cloudEvent.Extract(w.ctx), template, requestedAt); err != nil {
+		return fmt.Errorf("failed to process template: %w", err)
+	}
+	return nil
+}
+
+// handleCancel прерывает генерацию шаблона по событию cancel-template
+func (w *Worker) handleCancel(cloudEvent CloudEvent) error {
+	var cancel eventdata.CancelTemplate
+	if err := json.Unmarshal(cloudEvent.Data, &cancel); err != nil {
+		return fmt.Errorf("failed to unmarshal CancelTemplate from CloudEvent data: %w", err)
+	}
+
+	cancelledAt, err := time.Parse(time.RFC3339Nano, cancel.CancelledAt)
+	if err != nil {
+		cancelledAt = time.Now()
+	}
+
+	if w.jobs.cancel(cancel.ID, cancelledAt) {
+		w.log.Info(fmt.Sprintf("Aborting generation of template %s", cancel.ID))
+	} else {
+		w.log.Info(fmt.Sprintf("Template %s is not being generated, cancellation kept for a pending request", cancel.ID))
+	}
+	return nil
+}
+
+// processTemplate генерирует архив и отправляет его в publisher.
+// Менеджер уведомляется о начале генерации событием generation-started,
+// а при ошибке на любом этапе - событием generation-failed.
+// Отмененная генерация прерывается без уведомлений: шаблон уже в статусе CANCELLED.
+// Спан генерации продолжает трассировку из события, а ctx передает ее дальше в publisher.
+func (w *Worker) processTemplate(ctx context.Context, template eventdata.ProcessTemplate, requestedAt time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "generator.process_template",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(tracing.TemplateID(template.ID)))
+	defer func() { tracing.End(span, err) }()
+
+	// Незавершенная генерация не прерывается при остановке сервиса, только отменой шаблона
+	ctx, release, err := w.jobs.start(context.WithoutCancel(ctx), template.ID, requestedAt)
+	switch {
+	case errors.Is(err, errGenerationCancelled):
+		w.log.Info(fmt.Sprintf("Template %s was cancelled, skipping generation", template.ID))
+		return nil
+	case errors.Is(err, errGenerationRunning):
+		w.log.Warn(fmt.Sprintf("Template %s is already being generated, skipping the redelivered event", template.ID))
+		return nil
+	}
+	defer release()
+
+	w.reportStarted(ctx, template.ID)
+
+	archive, err := w.generateArchive(ctx, template)
+	if err != nil {
+		if isCancelled(ctx) {
+			generations.WithLabelValues(resultCancelled).Inc()
+			w.log.Info(fmt.Sprintf("Generation of template %s cancelled", template.ID))
+			return nil
 		}
+		generations.WithLabelValues(resultFailed).Inc()
+		w.reportFailure(ctx, template.ID, eventdata.StageGenerate, err)
+		return err
 	}
 
-	w.log.Info(fmt.Sprintf("Worker %d stopped", id))
+	// Передаем ID шаблона (который соответствует RequestUUID в Manager)
+	if err := w.streamArchive(ctx, archive, template.ID); err != nil {
+		if isCancelled(ctx) {
+			generations.WithLabelValues(resultCancelled).Inc()
+			w.log.Info(fmt.Sprintf("Streaming of template %s cancelled", template.ID))
+			return nil
+		}
+		generations.WithLabelValues(resultFailed).Inc()
+		w.reportFailure(ctx, template.ID, eventdata.StageStream, err)
+		return err
+	}
+
+	generations.WithLabelValues(resultCompleted).Inc()
+	return nil
+}
+
+// reportStarted публикует событие generation-started, чтобы менеджер перевел шаблон в PROCESSING
+func (w *Worker) reportStarted(ctx context.Context, templateID string) {
+	w.produceStatusEvent(ctx, eventdata.GenerationStartedEventType, eventdata.GenerationStartedSchema, &eventdata.GenerationStarted{
+		ID:        templateID,
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// reportFailure публикует событие generation-failed, чтобы менеджер перевел шаблон в FAILED
+func (w *Worker) reportFailure(ctx context.Context, templateID, stage string, cause error) {
+	w.produceStatusEvent(ctx, eventdata.GenerationFailedEventType, eventdata.GenerationFailedSchema, &eventdata.GenerationFailed{
+		ID:       templateID,
+		Stage:    stage,
+		Error:    cause.Error(),
+		FailedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	w.log.Info(fmt.Sprintf("Reported generation failure for template %s at stage %s", templateID, stage))
 }
 
-func (w *Worker) generateArchive(template eventdata.ProcessTemplate) ([]byte, error) {
+// produceStatusEvent публикует событие о ходе генерации в топик результатов.
+// Событие сериализуется так же, как в kafka.ClientConfig.Produce, но отправляется напрямую,
+// чтобы ошибки доставки попадали в метрики, а не только в лог.
+func (w *Worker) produceStatusEvent(ctx context.Context, eventType, schema string, data any) {
+	if w.kafkaProducer == nil || !w.kafkaProducer.ProducerIsEnabled() {
+		w.log.Warn(fmt.Sprintf("Kafka producer is disabled, %s event will not be published", eventType))
+		return
+	}
+	if w.doneTopic == "" {
+		produceErrors.Inc()
+		w.log.Error(fmt.Sprintf("Topic %s is not configured or disabled, %s event will not be published", eventdata.DoneTopicID, eventType))
+		return
+	}
+
+	payload, err := newCloudEvent(ctx, eventType, schema, data)
+	if err != nil {
+		produceErrors.Inc()
+		w.log.Error(fmt.Sprintf("Failed to serialize %s event: %v", eventType, err))
+		return
+	}
+
+	record := &kgo.Record{Topic: w.doneTopic, Value: payload}
+	// Отправка асинхронная и не прерывается остановкой воркера, как в kafka.ClientConfig.Produce
+	w.kafkaProducer.Client.Produce(context.Background(), record, func(_ *kgo.Record, err error) {
+		if err != nil {
+			produceErrors.Inc()
+			w.log.Error(fmt.Sprintf("Error producing %s event: %v", eventType, err))
+		}
+	})
+}
+
+func (w *Worker) generateArchive(ctx context.Context, template eventdata.ProcessTemplate) (archive []byte, err error) {
+	ctx, span := tracing.Start(ctx, "generator.generate")
+	defer func() { tracing.End(span, err) }()
+
 	// Log template data for debugging
 	w.log.Info(fmt.Sprintf("Generating archive for template ID: %s", template.ID))
 	w.log.Debug(fmt.Sprintf("Template details: Status=%s, Name=%s", template.Status, template.Data.Name))
@@ -172,7 +327,7 @@ func (w *Worker) generateArchive(template eventdata.ProcessTemplate) ([]byte, er
 
 	// Create generator and generate template
 	gen := engine.New()
-	archive, err := gen.Generate(w.ctx, &template)
+	archive, err = gen.Generate(ctx, &template)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate template: %w", err)
 	}
@@ -182,11 +337,16 @@ func (w *Worker) generateArchive(template eventdata.ProcessTemplate) ([]byte, er
 }
 
 // streamArchive отправляет сгенерированный архив через gRPC в сервис publisher.
-func (w *Worker) streamArchive(archive []byte, templateID string) error {
+func (w *Worker) streamArchive(ctx context.Context, archive []byte, templateID string) (err error) {
+	ctx, span := tracing.Start(ctx, "generator.stream_archive", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { tracing.End(span, err) }()
+
 	w.log.Info("Отправка архива в publisher сервис", "templateID", templateID)
 
 	// Используем метод потоковой передачи для отправки архива
-	err := w.publisherClient.StreamArchive(context.Background(), templateID, archive)
+	start := time.Now()
+	err = w.publisherClient.StreamArchive(ctx, templateID, archive)
+	streamDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
 		return fmt.Errorf("ошибка при отправке архива: %w", err)
 	}
@@ -200,6 +360,7 @@ func (w *Worker) streamArchive(archive []byte, templateID string) error {
 func (w *Worker) Work(ctx context.Context, value []byte) error {
 	// Quick validation of the message
 	if len(value) == 0 {
+		consumeErrors.Inc()
 		return fmt.Errorf("received empty message")
 	}
 
@@ -217,10 +378,19 @@ func (w *Worker) Work(ctx context.Context, value []byte) error {
 		}()
 	}
 
+	// Отмена обрабатывается сразу: все воркеры могут быть заняты той самой генерацией
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(value, &header); err == nil && header.Type == eventdata.CancelTemplateEventType {
+		return w.handleMessage(value)
+	}
+
 	// Try to send the message to the worker pool
 	select {
 	case w.messageChan <- value:
 		// Message successfully sent to the worker pool
+		queueDepth.Set(float64(len(w.messageChan)))
 		return nil
 	case <-ctx.Done():
 		// Context was canceled while trying to send the message
@@ -229,31 +399,8 @@ func (w *Worker) Work(ctx context.Context, value []byte) error {
 		// Channel is full, process the message directly
 		w.log.Warn("Worker pool queue is full, processing message directly")
 
-		// First unmarshal the CloudEvent structure
-		var cloudEvent CloudEvent
-		if err := json.Unmarshal(value, &cloudEvent); err != nil {
-			w.log.Error(fmt.Sprintf("Failed to unmarshal CloudEvent: %v", err))
-			return err
-		}
-
-		// Then unmarshal the data field into ProcessTemplate
-		var template eventdata.ProcessTemplate
-		if err := json.Unmarshal(cloudEvent.Data, &template); err != nil {
-			w.log.Error(fmt.Sprintf("Failed to unmarshal ProcessTemplate from CloudEvent data: %v", err))
-			return err
-		}
-
-		w.log.Info(fmt.Sprintf("Processing template with ID: %s", template.ID))
-
-		archive, err := w.generateArchive(template)
-		if err != nil {
-			w.log.Error(fmt.Sprintf("Failed to generate archive: %v", err))
-			return err
-		}
-
-		// Передаем ID шаблона (который соответствует RequestUUID в Manager)
-		if err := w.streamArchive(archive, template.ID); err != nil {
-			w.log.Error(fmt.Sprintf("Failed to stream archive: %v", err))
+		if err := w.handleMessage(value); err != nil {
+			w.log.Error(fmt.Sprintf("Failed to handle message: %v", err))
 			return err
 		}
 
@@ -24,6 +24,10 @@ const _ = grpc.SupportPackageIsVersion7
 type ArchivePublisherClient interface {
 	// Метод для стриминга архива чанками
 	StreamArchive(ctx context.Context, opts ...grpc.CallOption) (ArchivePublisher_StreamArchiveClient, error)
+	// Удаление сохраненного архива. Идемпотентно: отсутствующий архив считается удаленным
+	DeleteArchive(ctx context.Context, in *DeleteArchiveRequest, opts ...grpc.CallOption) (*DeleteArchiveResponse, error)
+	// Выдача пресигнированной ссылки на скачивание архива
+	GetDownloadURL(ctx context.Context, in *GetDownloadURLRequest, opts ...grpc.CallOption) (*GetDownloadURLResponse, error)
 }
 
 type archivePublisherClient struct {
@@ -68,12 +72,34 @@ func (x *archivePublisherStreamArchiveClient) CloseAndRecv() (*StreamResponse, e
 	return m, nil
 }
 
+func (c *archivePublisherClient) DeleteArchive(ctx context.Context, in *DeleteArchiveRequest, opts ...grpc.CallOption) (*DeleteArchiveResponse, error) {
+	out := new(DeleteArchiveResponse)
+	err := c.cc.Invoke(ctx, "/archive.ArchivePublisher/DeleteArchive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *archivePublisherClient) GetDownloadURL(ctx context.Context, in *GetDownloadURLRequest, opts ...grpc.CallOption) (*GetDownloadURLResponse, error) {
+	out := new(GetDownloadURLResponse)
+	err := c.cc.Invoke(ctx, "/archive.ArchivePublisher/GetDownloadURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ArchivePublisherServer is the server API for ArchivePublisher service.
 // All implementations must embed UnimplementedArchivePublisherServer
 // for forward compatibility
 type ArchivePublisherServer interface {
 	// Метод для стриминга архива чанками
 	StreamArchive(ArchivePublisher_StreamArchiveServer) error
+	// Удаление сохраненного архива. Идемпотентно: отсутствующий архив считается удаленным
+	DeleteArchive(context.Context, *DeleteArchiveRequest) (*DeleteArchiveResponse, error)
+	// Выдача пресигнированной ссылки на скачивание архива
+	GetDownloadURL(context.Context, *GetDownloadURLRequest) (*GetDownloadURLResponse, error)
 	mustEmbedUnimplementedArchivePublisherServer()
 }
 
@@ -84,6 +110,12 @@ type UnimplementedArchivePublisherServer struct {
 func (UnimplementedArchivePublisherServer) StreamArchive(ArchivePublisher_StreamArchiveServer) error {
 	return status.Errorf(codes.Unimplemented, "method StreamArchive not implemented")
 }
+func (UnimplementedArchivePublisherServer) DeleteArchive(context.Context, *DeleteArchiveRequest) (*DeleteArchiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteArchive not implemented")
+}
+func (UnimplementedArchivePublisherServer) GetDownloadURL(context.Context, *GetDownloadURLRequest) (*GetDownloadURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadURL not implemented")
+}
 func (UnimplementedArchivePublisherServer) mustEmbedUnimplementedArchivePublisherServer() {}
 
 // UnsafeArchivePublisherServer may be embedded to opt out of forward compatibility for this service.
@@ -123,13 +155,58 @@ func (x *archivePublisherStreamArchiveServer) Recv() (*ArchiveChunk, error) {
 	return m, nil
 }
 
+func _ArchivePublisher_DeleteArchive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteArchiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArchivePublisherServer).DeleteArchive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/archive.ArchivePublisher/DeleteArchive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArchivePublisherServer).DeleteArchive(ctx, req.(*DeleteArchiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArchivePublisher_GetDownloadURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDownloadURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArchivePublisherServer).GetDownloadURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/archive.ArchivePublisher/GetDownloadURL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArchivePublisherServer).GetDownloadURL(ctx, req.(*GetDownloadURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ArchivePublisher_ServiceDesc is the grpc.ServiceDesc for ArchivePublisher service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var ArchivePublisher_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "archive.ArchivePublisher",
 	HandlerType: (*ArchivePublisherServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DeleteArchive",
+			Handler:    _ArchivePublisher_DeleteArchive_Handler,
+		},
+		{
+			MethodName: "GetDownloadURL",
+			Handler:    _ArchivePublisher_GetDownloadURL_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "StreamArchive",
@@ -2,12 +2,36 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"go-init/config"
 	"go-init/internal/app"
 )
 
 func main() {
 	ctx := context.Background()
-	a, err := app.New(ctx)
+	cfg := config.GetConfig()
+
+	switch flag.Arg(0) {
+	// go-init-manager -config config.yml migrate up|down [n]|status
+	case "migrate":
+		if err := app.Migrate(ctx, cfg, flag.Args()[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	// go-init-manager -config config.yml dlq list|show <partition:offset>|replay <partition:offset>...|replay all
+	case "dlq":
+		if err := app.DeadLetters(ctx, cfg, flag.Args()[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	a, err := app.New(ctx, cfg)
 	if err != nil {
 		return
 	}
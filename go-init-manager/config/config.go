@@ -1,6 +1,17 @@
 package config
 
 import (
+	"go-init-observability/health"
+	"go-init-observability/tracing"
+	"go-init/internal/auth"
+	"go-init/internal/cleanup"
+	"go-init/internal/deadletter"
+	"go-init/internal/graphql"
+	"go-init/internal/idempotency"
+	"go-init/internal/outbox"
+	"go-init/internal/ratelimit"
+	"go-init/internal/reaper"
+
 	"gitlab.com/go-init/go-init-common/default/grpcpkg"
 	"gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/logger"
@@ -14,16 +25,38 @@ import (
 )
 
 type AppConfig struct {
-	Database db.Config            `yaml:"postgres_db"`
-	Logger   logger.Config        `yaml:"logger"`
-	HttpServ myserver.Config      `yaml:"http_server"`
-	GrpcServ grpcpkg.ServerConfig `yaml:"grpc_server"`
-	Kafka    kafka.Config         `yaml:"kafka"`
+	Database        db.Config            `yaml:"postgres_db"`
+	Logger          logger.Config        `yaml:"logger"`
+	HttpServ        myserver.Config      `yaml:"http_server"`
+	GrpcServ        grpcpkg.ServerConfig `yaml:"grpc_server"`
+	Kafka           kafka.Config         `yaml:"kafka"`
+	Auth            auth.Config          `yaml:"auth"`
+	Outbox          outbox.Config        `yaml:"outbox"`
+	Templates       graphql.Config       `yaml:"templates"`
+	PublisherClient grpcpkg.ClientConfig `yaml:"publisher_client"`
+	ArchiveCleanup  cleanup.Config       `yaml:"archive_cleanup"`
+	StaleTemplates  reaper.Config        `yaml:"stale_templates"`
+	DeadLetter      deadletter.Config    `yaml:"dead_letter"`
+	ProcessedEvents idempotency.Config   `yaml:"processed_events"`
+	RateLimits      ratelimit.Config     `yaml:"rate_limits"`
+	Tracing         tracing.Config       `yaml:"tracing"`
+	Health          health.Config        `yaml:"health"`
 }
 
 func GetConfig() *AppConfig {
 	config := &AppConfig{}
 	c.OpenConfig(&config)
 	defaults.SetDefaults(&config.Logger)
+	defaults.SetDefaults(&config.Auth)
+	defaults.SetDefaults(&config.Outbox)
+	defaults.SetDefaults(&config.Templates)
+	defaults.SetDefaults(&config.PublisherClient)
+	defaults.SetDefaults(&config.ArchiveCleanup)
+	defaults.SetDefaults(&config.StaleTemplates)
+	defaults.SetDefaults(&config.DeadLetter)
+	defaults.SetDefaults(&config.ProcessedEvents)
+	defaults.SetDefaults(&config.RateLimits)
+	defaults.SetDefaults(&config.Tracing)
+	defaults.SetDefaults(&config.Health)
 	return config
 }
@@ -7,19 +7,32 @@ import (
 	"sync"
 	"time"
 
+	"go-init-observability/health"
+	"go-init-observability/tracing"
 	"go-init/config"
-	"go-init/internal/database/request_repo/models"
+	"go-init/internal/auth"
+	"go-init/internal/cleanup"
+	"go-init/internal/database/migrations"
+	"go-init/internal/deadletter"
 	"go-init/internal/graphql"
+	managerGrpc "go-init/internal/grpc"
+	"go-init/internal/idempotency"
 	"go-init/internal/kafka"
+	"go-init/internal/outbox"
+	"go-init/internal/publisher"
+	"go-init/internal/ratelimit"
+	"go-init/internal/reaper"
+	"go-init/internal/rest"
 	generatedGQL "go-init/pkg/api/graphql"
+	pb "go-init/pkg/api/grpc"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	database "gitlab.com/go-init/go-init-common/default/db/pg/orm"
 	commonKafka "gitlab.com/go-init/go-init-common/default/kafka"
 
 	dbRepo "go-init/internal/database"
 
 	"gitlab.com/go-init/go-init-common/default/closer"
-	grpcpkg "gitlab.com/go-init/go-init-common/default/grpcpkg"
 	myhttp "gitlab.com/go-init/go-init-common/default/http"
 	myserver "gitlab.com/go-init/go-init-common/default/http/server"
 	"gitlab.com/go-init/go-init-common/default/logger"
@@ -35,16 +48,22 @@ type App struct {
 	KafkaProducer  *commonKafka.ClientConfig
 	graphqlService *graphql.Service
 	srv            *http.Server
-	grpcServer     *grpcpkg.GRPCServer
+	grpcServer     *pb.Server
+	statusListener *dbRepo.StatusListener
+	authenticator  *auth.Authenticator
+	publisher      *publisher.Client
+	limiter        *ratelimit.Limiter
+	health         *health.Checker
 }
 
 const (
 	serviceName     = "go-init-manager"
 	shutDownTimeOut = time.Second * 5
+	metricNamespace = "go_init_manager" // префикс метрик сервиса
 )
 
-func New(ctx context.Context) (*App, error) {
-	a := &App{}
+func New(ctx context.Context, cfg *config.AppConfig) (*App, error) {
+	a := &App{cfg: cfg}
 	err := a.initDeps(ctx)
 	if err != nil {
 		return nil, err
@@ -54,13 +73,17 @@ func New(ctx context.Context) (*App, error) {
 
 func (a *App) initDeps(ctx context.Context) error {
 	inits := []func(context.Context) error{
-		a.initConfig,
 		a.initLogger,
 		a.initCloser,
+		a.initTracing,
+		a.initAuth,
 		a.initDb,
+		a.initStatusListener,
 		a.initKafka,
 		a.initGrpcServer,
+		a.initPublisherClient,
 		a.initServices,
+		a.initHealth,
 		a.initHttpServer,
 	}
 	for _, f := range inits {
@@ -72,13 +95,12 @@ func (a *App) initDeps(ctx context.Context) error {
 	return nil
 }
 
-func (a *App) initConfig(_ context.Context) error {
-	a.cfg = config.GetConfig()
-	return nil
-}
-
 func (a *App) initKafka(_ context.Context) error {
-	k, err := commonKafka.NewClientConfig(&a.cfg.Kafka, a.log)
+	// Сообщения потребляет deadletter.Consumer на собственном клиенте с ручным коммитом,
+	// поэтому общий клиент в группу не входит и только отправляет события
+	kafkaCfg := a.cfg.Kafka
+	kafkaCfg.ConsumerConfig.Enabled = false
+	k, err := commonKafka.NewClientConfig(&kafkaCfg, a.log)
 	if err != nil {
 		return fmt.Errorf("failed to initialize kafka client: %w", err)
 	}
@@ -96,6 +118,30 @@ func (a *App) initCloser(_ context.Context) error {
 	return nil
 }
 
+// initTracing настраивает экспорт спанов; оставшиеся спаны отправляются при остановке
+func (a *App) initTracing(ctx context.Context) error {
+	shutdown, err := tracing.Init(ctx, &a.cfg.Tracing, serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	closer.Add(func() error {
+		cancelCtx, cancel := context.WithTimeout(context.Background(), shutDownTimeOut)
+		defer cancel()
+		return shutdown(cancelCtx)
+	})
+	a.log.Info(fmt.Sprintf("Tracing exporter: %s", a.cfg.Tracing.Exporter))
+	return nil
+}
+
+func (a *App) initAuth(_ context.Context) error {
+	authenticator, err := auth.NewAuthenticator(&a.cfg.Auth, a.log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize authentication: %w", err)
+	}
+	a.authenticator = authenticator
+	return nil
+}
+
 func (a *App) initHttpServer(ctx context.Context) error {
 	// 1. Собираем ExecutableSchema из вашего проекта,
 	//    предполагая, что у вас есть graph.NewExecutableSchema() и свой Resolver
@@ -107,16 +153,21 @@ func (a *App) initHttpServer(ctx context.Context) error {
 		},
 	)
 
-	// 2. Создаём кастомный GraphQL-хендлер через пакет mygraphql
-	gqlHandler := myserver.NewGraphQLServer(schema)
+	// 2. Создаём GraphQL-хендлер с аутентификацией websocket-подписок, лимитом запросов и трассировкой
+	gqlHandler := tracing.Middleware(a.limiter.Middleware(writeGraphQLRateLimited)(newGraphQLHandler(schema, a.authenticator)))
 
-	// 3. Подготовим ( handler для метрик.
-	//    Когда захотите Prometheus / OTEL - тут подключаете
-	metricsHandler := http.NotFoundHandler()
+	// 3. Prometheus метрики (в т.ч. отставание outbox relay)
+	metricsHandler := promhttp.Handler()
 
-	// 4. Собираем middleware (логирование, CORS и т.д.) через пакет myhttp
+	// 4. REST API для клиентов без GraphQL (CI-пайплайны) поверх тех же методов сервиса
+	restHandler, err := rest.NewHandler(a.graphqlService, a.log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize REST API: %w", err)
+	}
+
+	// 5. Собираем middleware (логирование, CORS и т.д.) через пакет myhttp
 	middlewares := myhttp.CollectHandlers(
-	// например, myAuthMiddleware, myLoggerMiddleware...
+		a.authenticator.Middleware,
 	)
 
 	s := myserver.NewServer(
@@ -125,7 +176,12 @@ func (a *App) initHttpServer(ctx context.Context) error {
 		metricsHandler,
 		middlewares,
 	)
+	// myserver не дает добавить маршруты, поэтому REST API обслуживается перед его роутером
+	s.Handler = withRESTAPI(s.Handler, tracing.Middleware(a.authenticator.Middleware(a.limiter.Middleware(rest.WriteRateLimited)(restHandler))))
+	// /healthz и /readyz обслуживаются до аутентификации
+	s.Handler = a.health.Routes(s.Handler)
 	closer.Add(func() error {
+		a.health.Shutdown()
 		cancelCtx, cancel := context.WithTimeout(ctx, shutDownTimeOut)
 		defer cancel()
 		if err := s.Shutdown(cancelCtx); err != nil {
@@ -150,23 +206,53 @@ func (a *App) runHttpServer() error {
 }
 
 func (a *App) initGrpcServer(_ context.Context) error {
-	grpcServer, err := grpcpkg.NewGRPCServer(a.cfg.GrpcServ)
+	grpcServer, err := pb.NewServer(pb.ServerConfig{
+		Port: a.cfg.GrpcServ.Port,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize gRPC server: %w", err)
 	}
+	closer.Add(func() error {
+		a.health.Shutdown()
+		grpcServer.Stop()
+		a.log.Info("gRPC server stopped")
+		return nil
+	})
+
 	a.grpcServer = grpcServer
 	return nil
 }
 
 func (a *App) runGrpcServer() error {
 	a.log.Info(fmt.Sprintf("Запуск gRPC сервера на %s", a.cfg.GrpcServ.Port))
-	if err := a.grpcServer.Start(a.cfg.GrpcServ.Port); err != nil {
+	if err := a.grpcServer.Start(); err != nil {
 		a.log.Error(fmt.Sprintf("Ошибка gRPC сервера: %v", err))
 		return err
 	}
 	return nil
 }
 
+func (a *App) initPublisherClient(_ context.Context) error {
+	if a.cfg.PublisherClient.Address == "" {
+		a.log.Warn("Publisher client address is not configured, archives of deleted templates will not be removed " +
+			"and download URLs will not be issued")
+		return nil
+	}
+
+	client, err := publisher.NewClient(a.cfg.PublisherClient, a.log)
+	if err != nil {
+		return fmt.Errorf("failed to initialize publisher client: %w", err)
+	}
+	closer.Add(func() error {
+		a.log.Info("Closing publisher client connection...")
+		return client.Close()
+	})
+
+	a.publisher = client
+	a.log.Info(fmt.Sprintf("Publisher client configured for %s", a.cfg.PublisherClient.Address))
+	return nil
+}
+
 func (a *App) initServices(_ context.Context) error {
 	if a.log == nil || a.cfg == nil || a.db == nil || a.KafkaProducer == nil {
 		a.log.Error("One or more dependencies are not initialized")
@@ -178,42 +264,109 @@ func (a *App) initServices(_ context.Context) error {
 	a.dbManagerRepo = dbManagerRepo
 
 	// Initialize the GraphQL service
-	a.graphqlService = graphql.New(a.log, a.cfg.HttpServ.Name, dbManagerRepo, a.db, a.KafkaProducer)
+	outboxRepo := request_repo.NewOutboxRepository(a.db, a.log, a.cfg.Database.Schema)
+	archiveDeletionRepo := request_repo.NewArchiveDeletionRepository(a.db, a.log, a.cfg.Database.Schema)
+	presetRepo := request_repo.NewPresetRepository(a.db, a.log, a.cfg.Database.Schema)
+	var downloadURLs *publisher.DownloadURLs
+	if a.publisher != nil {
+		downloadURLs = publisher.NewDownloadURLs(a.publisher, a.cfg.Templates.DownloadURLRefreshBefore)
+	}
 
-	// Reuse the same repository instance for Kafka consumers
-	// Initialize the Kafka consumer for archive-ready events
-	archiveConsumer := kafka.NewArchiveConsumerService(a.log, dbManagerRepo)
-
-	// Register the archive consumer with Kafka
-	if a.KafkaProducer != nil && a.KafkaProducer.ConsumerIsEnabled() {
-		// Проверяем наличие топиков в конфигурации
-		if len(a.cfg.Kafka.ConsumerConfig.Topic) > 0 {
-			// Получаем ID топика из конфигурации
-			topicID := a.cfg.Kafka.ConsumerConfig.Topic[0].Id
-			a.log.InfoContext(context.Background(), "Registering archive consumer for topic ID",
-				logger.String("topic_id", topicID))
-
-			// Регистрируем consumer для этого топика
-			err := a.KafkaProducer.RegisterConsumerWorkersByTopic(topicID, archiveConsumer)
-			if err != nil {
-				a.log.ErrorContext(context.Background(), "Failed to register archive Kafka consumer",
-					logger.Error(err))
-				return err
-			}
-			a.log.InfoContext(context.Background(), "Archive consumer registered successfully",
-				logger.String("topic_id", topicID))
-		} else {
-			a.log.ErrorContext(context.Background(), "No Kafka topics found in configuration")
-			return fmt.Errorf("no Kafka topics found in configuration")
-		}
+	// Limiter ограничивает запросы к API и создание шаблонов; счетчики в Postgres общие для всех реплик
+	rateLimitRepo := request_repo.NewRateLimitRepository(a.db, a.log, a.cfg.Database.Schema)
+	a.limiter = ratelimit.NewLimiter(&a.cfg.RateLimits, a.log, rateLimitRepo)
+	limiterCtx, cancelLimiter := context.WithCancel(context.Background())
+	go a.limiter.Run(limiterCtx)
+	closer.Add(func() error {
+		cancelLimiter()
+		return nil
+	})
+
+	a.graphqlService = graphql.New(&a.cfg.Templates, a.log, a.cfg.HttpServ.Name, dbManagerRepo, a.db,
+		outboxRepo, archiveDeletionRepo, presetRepo, downloadURLs, a.statusListener, a.limiter)
+
+	// Cleaner удаляет из хранилища publisher архивы удаленных шаблонов
+	if a.publisher != nil {
+		cleaner := cleanup.NewCleaner(&a.cfg.ArchiveCleanup, a.log, archiveDeletionRepo, a.publisher)
+		cleanerCtx, cancel := context.WithCancel(context.Background())
+		go cleaner.Run(cleanerCtx)
+		closer.Add(func() error {
+			cancel()
+			return nil
+		})
+	}
+
+	// Reaper перезапускает или завершает шаблоны, зависшие в PENDING или PROCESSING
+	if a.cfg.StaleTemplates.Disabled {
+		a.log.Warn("Stale template reaper is disabled, templates stuck in PENDING or PROCESSING stay there until retried")
+	} else {
+		staleReaper := reaper.NewReaper(&a.cfg.StaleTemplates, a.log, a.graphqlService)
+		reaperCtx, cancel := context.WithCancel(context.Background())
+		go staleReaper.Run(reaperCtx)
+		closer.Add(func() error {
+			cancel()
+			return nil
+		})
+	}
 
-		// Start consuming messages in a separate goroutine
-		a.log.InfoContext(context.Background(), "Starting Kafka consumer...")
-		go a.KafkaProducer.Start(context.Background())
-		a.log.InfoContext(context.Background(), "Kafka consumer started successfully")
+	// Relay публикует события, записанные в outbox вместе с шаблонами
+	if a.KafkaProducer.ProducerIsEnabled() {
+		relay := outbox.NewRelay(&a.cfg.Outbox, a.log, outboxRepo, &a.cfg.Kafka, a.KafkaProducer)
+		relayCtx, cancel := context.WithCancel(context.Background())
+		go relay.Run(relayCtx)
+		closer.Add(func() error {
+			cancel()
+			return nil
+		})
 	} else {
+		a.log.Warn("Kafka producer is disabled, outbox events will accumulate until it is enabled")
+	}
+
+	// Register the gRPC manager service used by generator and publisher to report progress
+	managerService := managerGrpc.NewManagerService(a.log, dbManagerRepo, a.db)
+	pb.RegisterManagerServiceServer(a.grpcServer.GetGRPCServer(), managerService)
+
+	if !a.cfg.Kafka.ConsumerConfig.Enabled {
 		a.log.InfoContext(context.Background(), "Kafka consumer is disabled, skipping archive consumer registration")
+		return nil
+	}
+
+	// Tracker пропускает повторные доставки событий и удаляет записи старше срока хранения
+	processedEventRepo := request_repo.NewProcessedEventRepository(a.db, a.log, a.cfg.Database.Schema)
+	tracker := idempotency.NewTracker(&a.cfg.ProcessedEvents, a.log, processedEventRepo)
+	trackerCtx, cancelTracker := context.WithCancel(context.Background())
+	go tracker.Run(trackerCtx)
+	closer.Add(func() error {
+		cancelTracker()
+		return nil
+	})
+
+	// Reuse the same repository instance for Kafka consumers
+	// Initialize the Kafka consumer for archive-ready events
+	archiveConsumer := kafka.NewArchiveConsumerService(a.log, dbManagerRepo, archiveDeletionRepo, a.db, tracker)
+
+	// Проверяем наличие топиков в конфигурации
+	if len(a.cfg.Kafka.ConsumerConfig.Topic) == 0 {
+		a.log.ErrorContext(context.Background(), "No Kafka topics found in configuration")
+		return fmt.Errorf("no Kafka topics found in configuration")
 	}
+	topic := a.cfg.Kafka.ConsumerConfig.Topic[0].Name
+
+	// Необработанные сообщения перекладываются в dead-letter топик, а не теряются и не блокируют партицию
+	consumer, err := deadletter.NewConsumer(&a.cfg.DeadLetter, a.log, &a.cfg.Kafka, topic, archiveConsumer, a.KafkaProducer.Client)
+	if err != nil {
+		a.log.ErrorContext(context.Background(), "Failed to create archive Kafka consumer", logger.Error(err))
+		return err
+	}
+
+	// Start consuming messages in a separate goroutine
+	a.log.InfoContext(context.Background(), "Starting Kafka consumer...", logger.String("topic", topic))
+	consumerCtx, cancel := context.WithCancel(context.Background())
+	go consumer.Run(consumerCtx)
+	closer.Add(func() error {
+		cancel()
+		return nil
+	})
 
 	return nil
 }
@@ -227,15 +380,42 @@ func (a *App) initDb(ctx context.Context) error {
 		return err
 	}
 
-	if a.cfg.Database.AutoMigrate {
-		err := a.db.Migrate(
-			models.Models...,
-		)
-		if err != nil {
-			a.log.Error(fmt.Sprintf("Migration failed: %v", err))
+	migrator, err := migrations.New(a.db, a.log, a.cfg.Database.Schema)
+	if err != nil {
+		return err
+	}
+
+	// Без auto_migrate схему обновляет команда migrate, а сервис только проверяет ее версию
+	if !a.cfg.Database.AutoMigrate {
+		if err := migrator.Check(ctx); err != nil {
+			a.log.Error(fmt.Sprintf("Database schema check failed: %v", err))
 			return err
 		}
+		return nil
 	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		a.log.Error(fmt.Sprintf("Migration failed: %v", err))
+		return err
+	}
+	if applied > 0 {
+		a.log.Info(fmt.Sprintf("Applied %d database migrations", applied))
+	}
+	return nil
+}
+
+// initStatusListener starts the LISTEN loop feeding GraphQL subscriptions with template changes
+func (a *App) initStatusListener(ctx context.Context) error {
+	a.statusListener = dbRepo.NewStatusListener(a.db, a.log)
+
+	listenCtx, cancel := context.WithCancel(ctx)
+	go a.statusListener.Run(listenCtx)
+	closer.Add(func() error {
+		cancel()
+		a.log.Info("Template status listener stopped")
+		return nil
+	})
 	return nil
 }
 
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"go-init/config"
+	"go-init/internal/deadletter"
+)
+
+// DLQUsage describes the dlq subcommand
+const DLQUsage = "usage: go-init-manager [-config config.yml] dlq list | show <partition:offset> | replay <partition:offset>... | replay all"
+
+// dlqTimeout bounds reading and replaying the dead-letter topic
+const dlqTimeout = time.Minute
+
+// DeadLetters runs the dlq subcommand: list prints the entries of the dead-letter topic, show prints one entry
+// with its headers and payload, and replay publishes entries back to their source topics to be processed again
+func DeadLetters(ctx context.Context, cfg *config.AppConfig, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(DLQUsage)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dlqTimeout)
+	defer cancel()
+
+	store, err := deadletter.NewStore(&cfg.DeadLetter, &cfg.Kafka)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	switch args[0] {
+	case "list":
+		if len(args) > 1 {
+			return errors.New(DLQUsage)
+		}
+		entries, err := store.List(ctx)
+		if err != nil {
+			return err
+		}
+		return printDeadLetters(out, entries)
+	case "show":
+		if len(args) != 2 {
+			return errors.New(DLQUsage)
+		}
+		entries, err := findDeadLetters(ctx, store, args[1:])
+		if err != nil {
+			return err
+		}
+		printDeadLetter(out, &entries[0])
+	case "replay":
+		if len(args) < 2 {
+			return errors.New(DLQUsage)
+		}
+		var entries []deadletter.Entry
+		if len(args) == 2 && args[1] == "all" {
+			entries, err = store.List(ctx)
+		} else {
+			entries, err = findDeadLetters(ctx, store, args[1:])
+		}
+		if err != nil {
+			return err
+		}
+		if err := store.Replay(ctx, entries); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Replayed %d entries\n", len(entries))
+	default:
+		return fmt.Errorf("unknown dlq command %q: %s", args[0], DLQUsage)
+	}
+	return nil
+}
+
+// findDeadLetters returns the entries at the given partition:offset positions, in the same order
+func findDeadLetters(ctx context.Context, store *deadletter.Store, positions []string) ([]deadletter.Entry, error) {
+	type position struct {
+		partition int32
+		offset    int64
+	}
+	wanted := make([]position, 0, len(positions))
+	for _, arg := range positions {
+		partition, offset, err := deadletter.ParsePosition(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, DLQUsage)
+		}
+		wanted = append(wanted, position{partition, offset})
+	}
+
+	entries, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byPosition := make(map[position]deadletter.Entry, len(entries))
+	for _, entry := range entries {
+		byPosition[position{entry.Partition, entry.Offset}] = entry
+	}
+
+	result := make([]deadletter.Entry, 0, len(wanted))
+	for i, p := range wanted {
+		entry, ok := byPosition[p]
+		if !ok {
+			return nil, fmt.Errorf("dead-letter entry %s not found", positions[i])
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// printDeadLetters prints one line per entry, oldest first within a partition
+func printDeadLetters(out io.Writer, entries []deadletter.Entry) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POSITION\tFAILED AT\tSOURCE\tATTEMPTS\tREASON")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", entry.Position(), formatFailedAt(entry.FailedAt),
+			fmt.Sprintf("%s/%d:%d", entry.SourceTopic, entry.SourcePartition, entry.SourceOffset),
+			entry.Attempts, entry.Reason)
+	}
+	return w.Flush()
+}
+
+// printDeadLetter prints an entry with the original headers and payload
+func printDeadLetter(out io.Writer, entry *deadletter.Entry) {
+	fmt.Fprintf(out, "Position:  %s\n", entry.Position())
+	fmt.Fprintf(out, "Failed at: %s\n", formatFailedAt(entry.FailedAt))
+	fmt.Fprintf(out, "Source:    %s/%d:%d\n", entry.SourceTopic, entry.SourcePartition, entry.SourceOffset)
+	fmt.Fprintf(out, "Attempts:  %d\n", entry.Attempts)
+	fmt.Fprintf(out, "Reason:    %s\n", entry.Reason)
+	fmt.Fprintf(out, "Key:       %s\n", entry.Key)
+	fmt.Fprintln(out, "Headers:")
+	for _, header := range entry.Headers {
+		fmt.Fprintf(out, "  %s: %s\n", header.Key, header.Value)
+	}
+	fmt.Fprintln(out, "Value:")
+	fmt.Fprintf(out, "%s\n", entry.Value)
+}
+
+func formatFailedAt(failedAt time.Time) string {
+	if failedAt.IsZero() {
+		return "-"
+	}
+	return failedAt.Format(time.RFC3339)
+}
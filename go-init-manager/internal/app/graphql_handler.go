@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-init/internal/auth"
+	managerGraphql "go-init/internal/graphql"
+	"go-init/internal/ratelimit"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gorilla/websocket"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// newGraphQLHandler повторяет myserver.NewGraphQLServer, но дополнительно аутентифицирует
+// websocket-подключения по connection_init: браузер не может передать заголовок при upgrade
+func newGraphQLHandler(es graphql.ExecutableSchema, authenticator *auth.Authenticator) http.Handler {
+	srv := handler.New(es)
+
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.Websocket{
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc: func(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+			// Заголовок upgrade-запроса уже обработан middleware
+			if _, err := auth.FromContext(ctx); err == nil {
+				return ctx, &payload, nil
+			}
+
+			authorization := payload.Authorization()
+			if authorization == "" {
+				return ctx, &payload, nil
+			}
+			identity, err := authenticator.Authenticate(authorization)
+			if err != nil {
+				return ctx, nil, err
+			}
+			return auth.WithIdentity(ctx, identity), &payload, nil
+		},
+	})
+
+	srv.Use(extension.Introspection{})
+
+	return srv
+}
+
+// writeGraphQLRateLimited отвечает на запрос, отклоненный лимитом, ответом GraphQL с одной ошибкой
+// RATE_LIMITED, чтобы клиенты обрабатывали его так же, как лимит создания шаблонов в резолвере
+func writeGraphQLRateLimited(w http.ResponseWriter, _ *http.Request, limitErr *ratelimit.LimitError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", strconv.Itoa(limitErr.RetryAfterSeconds()))
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(graphql.Response{
+		Errors: gqlerror.List{managerGraphql.RateLimitError(limitErr)},
+	})
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"go-init-observability/health"
+	pb "go-init/pkg/api/grpc"
+
+	"gitlab.com/go-init/go-init-common/default/closer"
+)
+
+// initHealth регистрирует проверки зависимостей для /readyz и gRPC health:
+// доступность Postgres и брокеров Kafka
+func (a *App) initHealth(_ context.Context) error {
+	a.health = health.NewChecker(&a.cfg.Health, a.log, metricNamespace)
+	a.health.Add("postgres", a.pingDatabase)
+	a.health.Add("kafka", func(ctx context.Context) error {
+		return a.KafkaProducer.Client.Ping(ctx)
+	})
+	pb.RegisterHealthService(a.grpcServer.GetGRPCServer(), a.health.GRPCServer())
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	go a.health.Run(healthCtx)
+	closer.Add(func() error {
+		cancel()
+		return nil
+	})
+	return nil
+}
+
+// pingDatabase проверяет соединение с Postgres
+func (a *App) pingDatabase(ctx context.Context) error {
+	sqlDB, err := a.db.DB().DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
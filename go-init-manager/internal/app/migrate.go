@@ -0,0 +1,96 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"go-init/config"
+	"go-init/internal/database/migrations"
+
+	database "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// MigrateUsage describes the migrate subcommand
+const MigrateUsage = "usage: go-init-manager [-config config.yml] migrate up | down [n] | status"
+
+// Migrate runs the migrate subcommand: up applies pending migrations, down [n] rolls back
+// the last n (default 1) and status prints every migration with the time it was applied
+func Migrate(ctx context.Context, cfg *config.AppConfig, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(MigrateUsage)
+	}
+
+	log := logger.New(&cfg.Logger, cfg.HttpServ.Name, cfg.HttpServ.Env)
+	db, err := database.NewAgent(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	migrator, err := migrations.New(db, log, cfg.Database.Schema)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		if len(args) > 1 {
+			return errors.New(MigrateUsage)
+		}
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Applied %d migrations\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			return errors.New(MigrateUsage)
+		}
+		if len(args) == 2 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of migrations %q: %s", args[1], MigrateUsage)
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Rolled back %d migrations\n", reverted)
+	case "status":
+		if len(args) > 1 {
+			return errors.New(MigrateUsage)
+		}
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatus(out, statuses)
+	default:
+		return fmt.Errorf("unknown migrate command %q: %s", args[0], MigrateUsage)
+	}
+	return nil
+}
+
+// printMigrationStatus prints one line per migration; versions of newer builds are marked unknown
+func printMigrationStatus(out io.Writer, statuses []migrations.Status) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if !status.Known {
+			state = "unknown"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
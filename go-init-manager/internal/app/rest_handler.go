@@ -0,0 +1,15 @@
+package app
+
+import (
+	"net/http"
+
+	"go-init/internal/rest"
+)
+
+// withRESTAPI отдает запросы под rest.BasePath обработчику api, остальные - основному роутеру
+func withRESTAPI(router, api http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(rest.BasePath+"/", api)
+	mux.Handle("/", router)
+	return mux
+}
@@ -0,0 +1,31 @@
+package auth
+
+import "time"
+
+// Config описывает проверку JWT bearer токенов
+type Config struct {
+	// Enabled выключает аутентификацию целиком: все запросы выполняются от анонимного администратора
+	Enabled bool `yaml:"enabled"`
+	// JwksFile путь к JSON Web Key Set с ключами проверки подписи
+	JwksFile string `yaml:"jwks_file"`
+	// StaticKeys ключи, заданные прямо в конфиге (дополняют JwksFile)
+	StaticKeys []StaticKey `yaml:"static_keys"`
+	// Issuer и Audience проверяются, если заданы
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+	// RolesClaim имя claim со списком ролей пользователя
+	RolesClaim string `yaml:"roles_claim" default:"roles"`
+	// TeamsClaim имя claim со списком команд пользователя; команде доступны ее пресеты
+	TeamsClaim string `yaml:"teams_claim" default:"teams"`
+	// AdminRole роль, которой доступны шаблоны всех пользователей
+	AdminRole string `yaml:"admin_role" default:"admin"`
+	// Leeway допустимое расхождение часов при проверке exp/nbf/iat
+	Leeway time.Duration `yaml:"leeway" default:"30s"`
+}
+
+// StaticKey ключ проверки подписи: HMAC секрет или открытый ключ RSA/ECDSA в PEM
+type StaticKey struct {
+	Kid       string `yaml:"kid"`
+	Secret    string `yaml:"secret"`
+	PublicKey string `yaml:"public_key"`
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnauthenticated is returned when a request carries no valid identity
+var ErrUnauthenticated = errors.New("authentication required")
+
+// Identity is the authenticated caller of the API
+type Identity struct {
+	// UserID is the token subject, or a UUIDv5 derived from it when the subject is not a UUID
+	UserID  uuid.UUID
+	Subject string
+	Roles   []string
+	Teams   []string // teams the caller belongs to, presets can be shared with them
+	Admin   bool
+}
+
+// CanAccess reports whether the caller may see a template owned by ownerID
+func (i *Identity) CanAccess(ownerID *uuid.UUID) bool {
+	if i.Admin {
+		return true
+	}
+	return ownerID != nil && *ownerID == i.UserID
+}
+
+// InTeam reports whether the caller is a member of team
+func (i *Identity) InTeam(team string) bool {
+	return team != "" && slices.Contains(i.Teams, team)
+}
+
+// anonymousAdmin is used for every request while authentication is disabled
+var anonymousAdmin = &Identity{UserID: uuid.Nil, Subject: "anonymous", Admin: true}
+
+type identityKey struct{}
+
+// WithIdentity stores the caller identity in the context
+func WithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the caller identity or ErrUnauthenticated
+func FromContext(ctx context.Context) (*Identity, error) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	if !ok || identity == nil {
+		return nil, ErrUnauthenticated
+	}
+	return identity, nil
+}
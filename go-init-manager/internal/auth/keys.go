@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwk is a single entry of a JSON Web Key Set (RFC 7517)
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	// oct (HMAC)
+	K string `json:"k"`
+}
+
+// loadKeys collects verification keys from the JWKS file and the static keys, indexed by kid
+func loadKeys(cfg *Config) (map[string]any, error) {
+	keys := make(map[string]any)
+
+	if cfg.JwksFile != "" {
+		data, err := os.ReadFile(cfg.JwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+
+		var set struct {
+			Keys []jwk `json:"keys"`
+		}
+		if err := json.Unmarshal(data, &set); err != nil {
+			return nil, fmt.Errorf("failed to parse JWKS file: %w", err)
+		}
+
+		for _, k := range set.Keys {
+			// Encryption keys are never used to sign tokens
+			if k.Use != "" && k.Use != "sig" {
+				continue
+			}
+			key, err := k.publicKey()
+			if err != nil {
+				return nil, fmt.Errorf("invalid JWKS key %q: %w", k.Kid, err)
+			}
+			keys[k.Kid] = key
+		}
+	}
+
+	for _, k := range cfg.StaticKeys {
+		switch {
+		case k.Secret != "":
+			keys[k.Kid] = []byte(k.Secret)
+		case k.PublicKey != "":
+			key, err := parsePublicKeyPEM([]byte(k.PublicKey))
+			if err != nil {
+				return nil, fmt.Errorf("invalid static key %q: %w", k.Kid, err)
+			}
+			keys[k.Kid] = key
+		default:
+			return nil, fmt.Errorf("static key %q has neither secret nor public_key", k.Kid)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no verification keys configured")
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("secret: %w", err)
+		}
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func parsePublicKeyPEM(data []byte) (any, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("expected an RSA or ECDSA public key in PEM format")
+}
+
+// keyMatchesMethod prevents algorithm confusion, e.g. an HS256 token signed with a public RSA key
+func keyMatchesMethod(key any, method jwt.SigningMethod) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		switch method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			return true
+		}
+	case *ecdsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodECDSA)
+		return ok
+	case []byte:
+		_, ok := method.(*jwt.SigningMethodHMAC)
+		return ok
+	}
+	return false
+}
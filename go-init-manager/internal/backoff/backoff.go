@@ -0,0 +1,19 @@
+// Package backoff computes delays between attempts of background retries
+package backoff
+
+import "time"
+
+// Exponential doubles the delay after every failed attempt, from Min up to Max
+type Exponential struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Delay returns the delay before the next attempt after the given number of failed attempts
+func (b Exponential) Delay(attempts int) time.Duration {
+	delay := b.Min
+	for i := 1; i < attempts && delay < b.Max; i++ {
+		delay *= 2
+	}
+	return min(delay, b.Max)
+}
@@ -0,0 +1,35 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponentialDelay(t *testing.T) {
+	b := Exponential{Min: time.Second, Max: time.Minute}
+
+	testCases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: time.Second},
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 7, want: time.Minute},
+		{attempts: 1000, want: time.Minute},
+	}
+
+	for _, tc := range testCases {
+		if got := b.Delay(tc.attempts); got != tc.want {
+			t.Errorf("Delay(%d) = %s, want %s", tc.attempts, got, tc.want)
+		}
+	}
+}
+
+func TestExponentialDelayMinAboveMax(t *testing.T) {
+	b := Exponential{Min: time.Minute, Max: time.Second}
+	if got := b.Delay(1); got != time.Second {
+		t.Fatalf("Delay(1) = %s, want the delay capped at Max", got)
+	}
+}
@@ -0,0 +1,111 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-init/internal/backoff"
+	"go-init/internal/database"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// statsInterval период обновления метрики размера очереди
+const statsInterval = 30 * time.Second
+
+// claimMargin запас аренды удалений сверх времени на запросы к publisher
+const claimMargin = 30 * time.Second
+
+// ArchiveDeleter removes archives from the publisher storage
+type ArchiveDeleter interface {
+	DeleteArchive(ctx context.Context, archiveID uuid.UUID) error
+}
+
+// Cleaner removes archives of deleted templates. Every manager replica may run one:
+// queued deletions are claimed with SKIP LOCKED, leased while the publisher deletes them outside of any transaction,
+// and retried with backoff until the publisher confirms them.
+type Cleaner struct {
+	cfg     *Config
+	log     *logger.Logger
+	repo    database.ArchiveDeletionRepository
+	deleter ArchiveDeleter
+	// backoff delays retries of failed deletions
+	backoff backoff.Exponential
+}
+
+// NewCleaner creates a cleaner deleting archives through the publisher
+func NewCleaner(cfg *Config, log *logger.Logger, repo database.ArchiveDeletionRepository, deleter ArchiveDeleter) *Cleaner {
+	return &Cleaner{
+		cfg:     cfg,
+		log:     log,
+		repo:    repo,
+		deleter: deleter,
+		backoff: backoff.Exponential{Min: cfg.MinBackoff, Max: cfg.MaxBackoff},
+	}
+}
+
+// Run deletes due archives until ctx is cancelled
+func (c *Cleaner) Run(ctx context.Context) {
+	c.log.Info(fmt.Sprintf("Archive cleaner started, polling every %s", c.cfg.PollInterval))
+
+	poll := time.NewTicker(c.cfg.PollInterval)
+	defer poll.Stop()
+	stats := time.NewTicker(statsInterval)
+	defer stats.Stop()
+
+	c.updateStats(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			c.log.Info("Archive cleaner stopped")
+			return
+		case <-poll.C:
+			c.drain(ctx)
+		case <-stats.C:
+			c.updateStats(ctx)
+		}
+	}
+}
+
+// drain processes batches until no due deletions are left
+func (c *Cleaner) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		// Архивы пачки удаляются последовательно, аренда покрывает таймауты всех запросов
+		lease := time.Duration(c.cfg.BatchSize)*c.cfg.RequestTimeout + claimMargin
+		processed, err := c.repo.ProcessArchiveDeletions(ctx, c.cfg.BatchSize, lease, c.deleteArchive, c.backoff.Delay)
+		if err != nil {
+			c.log.Error(fmt.Sprintf("Failed to process archive deletions: %v", err))
+			return
+		}
+		if processed < c.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+func (c *Cleaner) deleteArchive(ctx context.Context, archiveID uuid.UUID) error {
+	deleteCtx, cancel := context.WithTimeout(ctx, c.cfg.RequestTimeout)
+	defer cancel()
+
+	if err := c.deleter.DeleteArchive(deleteCtx, archiveID); err != nil {
+		failedDeletions.Inc()
+		c.log.Warn(fmt.Sprintf("Failed to delete archive %s, will retry: %v", archiveID, err))
+		return err
+	}
+	deletedArchives.Inc()
+	return nil
+}
+
+func (c *Cleaner) updateStats(ctx context.Context) {
+	pending, err := c.repo.CountArchiveDeletions(ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			c.log.Warn(fmt.Sprintf("Failed to collect archive deletion stats: %v", err))
+		}
+		return
+	}
+	pendingDeletions.Set(float64(pending))
+}
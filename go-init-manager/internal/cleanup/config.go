@@ -0,0 +1,16 @@
+package cleanup
+
+import "time"
+
+// Config настройки удаления архивов удаленных шаблонов
+type Config struct {
+	// PollInterval период опроса очереди удаления
+	PollInterval time.Duration `yaml:"poll_interval" default:"5s"`
+	// BatchSize сколько архивов забирается и удаляется за один проход
+	BatchSize int `yaml:"batch_size" default:"10"`
+	// RequestTimeout ожидание ответа publisher на одно удаление
+	RequestTimeout time.Duration `yaml:"request_timeout" default:"10s"`
+	// MinBackoff и MaxBackoff границы экспоненциальной задержки повторного удаления
+	MinBackoff time.Duration `yaml:"min_backoff" default:"5s"`
+	MaxBackoff time.Duration `yaml:"max_backoff" default:"10m"`
+}
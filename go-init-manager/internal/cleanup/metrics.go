@@ -0,0 +1,21 @@
+package cleanup
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	pendingDeletions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "go_init_manager_archive_deletions_pending",
+		Help: "Number of archives of deleted templates not yet removed from storage.",
+	})
+	deletedArchives = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_archive_deletions_total",
+		Help: "Archives of deleted templates removed from storage.",
+	})
+	failedDeletions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_archive_deletion_failures_total",
+		Help: "Failed attempts to remove archives; the deletions are retried.",
+	})
+)
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// ArchiveDeleteFunc removes a stored archive; a missing archive must not be reported as an error
+type ArchiveDeleteFunc func(ctx context.Context, archiveID uuid.UUID) error
+
+// ArchiveDeletionRepository queues archives of deleted templates for removal from the publisher storage
+type ArchiveDeletionRepository interface {
+	// AddArchiveDeletion queues the archive within the caller's transaction
+	AddArchiveDeletion(ctx context.Context, archiveID uuid.UUID, tx *orm.Transaction) error
+	// ProcessArchiveDeletions claims up to limit due deletions for lease, skipping rows held by other replicas,
+	// and hands them to del one by one outside of the claiming transaction. Completed deletions are removed from the queue,
+	// failed ones are retried after retryDelay(attempts), the ones of a replica that died while deleting after lease.
+	ProcessArchiveDeletions(ctx context.Context, limit int, lease time.Duration, del ArchiveDeleteFunc, retryDelay func(attempts int) time.Duration) (int, error)
+	// CountArchiveDeletions returns the number of queued deletions
+	CountArchiveDeletions(ctx context.Context) (int64, error)
+}
@@ -13,10 +13,23 @@ type GoInitManagerRepository interface {
 	CreateNewTemplate(ctx context.Context, model *dbModel.ServiceTemplate, tx *orm.Transaction) error
 	GetTemplateByUUID(ctx context.Context, templateUUID uuid.UUID) (*dbModel.ServiceTemplate, error)
 	GetTemplateByID(ctx context.Context, templateID int) (*dbModel.ServiceTemplate, error)
-	GetRecentTemplates(ctx context.Context, limit int) ([]*dbModel.ServiceTemplate, error)
-	UpdateZipUrl(ctx context.Context, templateUUID uuid.UUID, newZipUrl string) error
-	UpdateTemplateStatusByUUID(ctx context.Context, templateUUID uuid.UUID, newStatus string) error
-	UpdateTemplateErrorByUUID(ctx context.Context, templateUUID uuid.UUID, errorMessage string) error
+	GetRecentTemplates(ctx context.Context, limit int, ownerID *uuid.UUID) ([]*dbModel.ServiceTemplate, error)
+	ListTemplates(ctx context.Context, params TemplateListParams) (*TemplatePage, error)
+	GetTemplateStats(ctx context.Context, params TemplateStatsParams) (*TemplateStats, error)
+	UpdateZipUrl(ctx context.Context, templateUUID uuid.UUID, newZipUrl string, tx *orm.Transaction) error
+	UpdateArchiveLocation(ctx context.Context, templateUUID uuid.UUID, bucket, object string, tx *orm.Transaction) error
+	UpdateRevisionArchiveLocation(ctx context.Context, archiveID uuid.UUID, bucket, object string, tx *orm.Transaction) error
+	ResolveArchiveID(ctx context.Context, archiveID uuid.UUID) (*ArchiveRef, error)
+	UpdateTemplateStatusByUUID(ctx context.Context, templateUUID uuid.UUID, newStatus string, source string, tx *orm.Transaction) error
+	UpdateTemplateErrorByUUID(ctx context.Context, templateUUID uuid.UUID, errorMessage string, tx *orm.Transaction) error
+	ResetTemplateForRetry(ctx context.Context, templateID int, from []string, maxAttempts int, source string, tx *orm.Transaction) (*dbModel.ServiceTemplate, error)
+	CancelTemplate(ctx context.Context, templateID int, source string, tx *orm.Transaction) error
+	DeleteTemplate(ctx context.Context, templateID int, tx *orm.Transaction) ([]uuid.UUID, error)
+	ReviseTemplate(ctx context.Context, change TemplateRevisionChange, tx *orm.Transaction) error
+	ListTemplateRevisions(ctx context.Context, templateID int) ([]*dbModel.TemplateRevision, error)
+	TryLockStaleTemplates(ctx context.Context, tx *orm.Transaction) (bool, error)
+	LockStaleTemplates(ctx context.Context, timeouts StaleTemplateTimeouts, limit int, tx *orm.Transaction) ([]*dbModel.ServiceTemplate, error)
+	FailTemplate(ctx context.Context, templateID int, message string, source string, tx *orm.Transaction) error
 
 	// ...
 }
@@ -0,0 +1,213 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// baselineModels are a frozen copy of the GORM models the 0001_baseline migration was written from.
+// A schema created by AutoMigrate is brought to them before the baseline is recorded; they must not
+// follow later changes of request_repo/models, those arrive in the schema through numbered migrations.
+var baselineModels = []interface{}{
+	&baselineServiceTemplate{},
+	&baselineEndpoint{},
+	&baselineDatabaseConfig{},
+	&baselineDockerConfig{},
+	&baselineAdvancedConfig{},
+	&baselineTemplateStatusHistory{},
+	&baselineTemplateRevision{},
+	&baselineOutboxEvent{},
+	&baselineArchiveDeletion{},
+	&baselinePreset{},
+	&baselinePresetVersion{},
+}
+
+type baselineServiceTemplate struct {
+	ServiceTemplateId   *int       `gorm:"column:service_template_id;primaryKey;autoIncrement;index:idx_service_template_created,priority:2;index:idx_service_template_name,priority:2"`
+	ServiceTemplateUuid *uuid.UUID `gorm:"column:service_template_uuid;type:uuid;default:gen_random_uuid();uniqueIndex"`
+
+	ServiceTemplateName *string `gorm:"type:varchar(255);not null;index:idx_service_template_name,priority:1"`
+	ZipURL              *string `gorm:"type:text;not null"`
+	ArchiveBucket       *string `gorm:"type:varchar(255)"`
+	ArchiveObject       *string `gorm:"type:varchar(1024)"`
+
+	UserId   *uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
+	Status   *string    `gorm:"type:varchar(50);not null;default:'pending';index"`
+	Error    *string    `gorm:"type:text"`
+	Attempts int        `gorm:"not null;default:1"`
+
+	Revision    int        `gorm:"not null;default:1"`
+	Version     *string    `gorm:"type:varchar(10)"`
+	ArchiveUuid *uuid.UUID `gorm:"column:archive_uuid;type:uuid;uniqueIndex"`
+
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_service_template_created,priority:1"`
+	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Endpoints       []*baselineEndpoint              `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	DatabaseConfigs []*baselineDatabaseConfig        `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	DockerConfigs   []*baselineDockerConfig          `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	AdvancedConfigs []*baselineAdvancedConfig        `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	StatusHistory   []*baselineTemplateStatusHistory `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	Revisions       []*baselineTemplateRevision      `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+type baselineEndpoint struct {
+	EndpointId   *int       `gorm:"column:endpoint_id;primaryKey;autoIncrement"`
+	EndpointUuid *uuid.UUID `gorm:"column:endpoint_uuid;type:uuid;default:gen_random_uuid()"`
+
+	TemplateId int `gorm:"column:template_id;not null;index:idx_endpoint_template_protocol,priority:1"`
+
+	Protocol  *string    `gorm:"type:varchar(10);not null;index:idx_endpoint_template_protocol,priority:2"`
+	Role      *string    `gorm:"type:varchar(10);not null"`
+	Config    []byte     `gorm:"type:jsonb"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *baselineServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+type baselineDatabaseConfig struct {
+	DatabaseConfigId   *int       `gorm:"column:database_config_id;primaryKey;autoIncrement"`
+	DatabaseConfigUuid *uuid.UUID `gorm:"column:database_config_uuid;type:uuid;default:gen_random_uuid()"`
+
+	TemplateId int `gorm:"column:template_id;not null;index:idx_database_config_template_type,priority:1"`
+
+	Type       *string    `gorm:"type:varchar(10);not null;index:idx_database_config_template_type,priority:2"`
+	DDL        *string    `gorm:"type:text"`
+	Migrations *bool      `gorm:"default:false"`
+	Models     *bool      `gorm:"default:false"`
+	CreatedAt  *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *baselineServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+type baselineDockerConfig struct {
+	DockerConfigId   *int       `gorm:"column:docker_config_id;primaryKey;autoIncrement"`
+	DockerConfigUuid *uuid.UUID `gorm:"column:docker_config_uuid;type:uuid;default:gen_random_uuid()"`
+
+	TemplateId int `gorm:"column:template_id;not null;index"`
+
+	Registry  *string    `gorm:"type:varchar(255)"`
+	ImageName *string    `gorm:"type:varchar(255);not null"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *baselineServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+type baselineAdvancedConfig struct {
+	AdvancedConfigId   *int       `gorm:"column:advanced_config_id;primaryKey;autoIncrement"`
+	AdvancedConfigUuid *uuid.UUID `gorm:"column:advanced_config_uuid;type:uuid;default:gen_random_uuid()"`
+
+	TemplateId int `gorm:"column:template_id;not null;index"`
+
+	EnableAuthentication *bool      `gorm:"default:false"`
+	GenerateSwaggerDocs  *bool      `gorm:"default:false"`
+	ModulePath           *string    `gorm:"type:varchar(255)"`
+	ServiceDescription   *string    `gorm:"type:text"`
+	EnableGraphQL        *bool      `gorm:"column:enable_graphql;default:false"`
+	EnableGRPC           *bool      `gorm:"column:enable_grpc;default:false"`
+	CreatedAt            *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt            *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *baselineServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+type baselineTemplateStatusHistory struct {
+	TemplateStatusHistoryId *int `gorm:"column:template_status_history_id;primaryKey;autoIncrement"`
+
+	TemplateId int `gorm:"column:template_id;not null;index"`
+
+	FromStatus *string    `gorm:"type:varchar(50)"`
+	ToStatus   *string    `gorm:"type:varchar(50);not null"`
+	Source     *string    `gorm:"type:varchar(100);not null"`
+	CreatedAt  *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *baselineServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+type baselineTemplateRevision struct {
+	TemplateRevisionId *int `gorm:"column:template_revision_id;primaryKey;autoIncrement"`
+
+	TemplateId  int        `gorm:"column:template_id;not null;uniqueIndex:idx_template_revision,priority:1"`
+	Revision    int        `gorm:"not null;uniqueIndex:idx_template_revision,priority:2"`
+	ArchiveUuid *uuid.UUID `gorm:"column:archive_uuid;type:uuid;not null;uniqueIndex"`
+	Config      []byte     `gorm:"type:jsonb;not null"`
+
+	ArchiveBucket *string `gorm:"type:varchar(255)"`
+	ArchiveObject *string `gorm:"type:varchar(1024)"`
+
+	CreatedBy *uuid.UUID `gorm:"column:created_by;type:uuid"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *baselineServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+type baselineOutboxEvent struct {
+	OutboxEventId *int       `gorm:"column:outbox_event_id;primaryKey;autoIncrement"`
+	EventId       *uuid.UUID `gorm:"column:event_id;type:uuid;not null;uniqueIndex"`
+
+	AggregateId *string `gorm:"type:varchar(64);not null;index"`
+	TopicId     *string `gorm:"type:varchar(100);not null"`
+	EventType   *string `gorm:"type:varchar(100);not null"`
+	Payload     []byte  `gorm:"type:jsonb;not null"`
+
+	Attempts  int     `gorm:"not null;default:0"`
+	LastError *string `gorm:"type:text"`
+
+	NextAttemptAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_outbox_event_pending,where:sent_at IS NULL"`
+	CreatedAt     *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	SentAt        *time.Time
+}
+
+type baselineArchiveDeletion struct {
+	ArchiveDeletionId *int       `gorm:"column:archive_deletion_id;primaryKey;autoIncrement"`
+	ArchiveId         *uuid.UUID `gorm:"column:archive_id;type:uuid;not null;uniqueIndex"`
+
+	Attempts  int     `gorm:"not null;default:0"`
+	LastError *string `gorm:"type:text"`
+
+	NextAttemptAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index"`
+	CreatedAt     *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+type baselinePreset struct {
+	PresetId   *int       `gorm:"column:preset_id;primaryKey;autoIncrement"`
+	PresetUuid *uuid.UUID `gorm:"column:preset_uuid;type:uuid;default:gen_random_uuid();uniqueIndex"`
+
+	PresetName  *string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_preset_owner_name,priority:2"`
+	Description *string    `gorm:"type:text"`
+	OwnerId     *uuid.UUID `gorm:"column:owner_id;type:uuid;not null;uniqueIndex:idx_preset_owner_name,priority:1"`
+	Team        *string    `gorm:"type:varchar(255);index"`
+	Visibility  *string    `gorm:"type:varchar(20);not null;default:'PRIVATE'"`
+	Version     int        `gorm:"not null;default:1"`
+
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Versions []*baselinePresetVersion `gorm:"foreignKey:PresetId;references:PresetId;constraint:OnDelete:CASCADE"`
+}
+
+type baselinePresetVersion struct {
+	PresetVersionId *int `gorm:"column:preset_version_id;primaryKey;autoIncrement"`
+
+	PresetId  int        `gorm:"column:preset_id;not null;uniqueIndex:idx_preset_version,priority:1"`
+	Version   int        `gorm:"not null;uniqueIndex:idx_preset_version,priority:2"`
+	Input     []byte     `gorm:"type:jsonb;not null"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+func (baselineServiceTemplate) TableName() string       { return "service_template" }
+func (baselineEndpoint) TableName() string              { return "endpoint" }
+func (baselineDatabaseConfig) TableName() string        { return "database_config" }
+func (baselineDockerConfig) TableName() string          { return "docker_config" }
+func (baselineAdvancedConfig) TableName() string        { return "advanced_config" }
+func (baselineTemplateStatusHistory) TableName() string { return "template_status_history" }
+func (baselineTemplateRevision) TableName() string      { return "template_revision" }
+func (baselineOutboxEvent) TableName() string           { return "outbox_event" }
+func (baselineArchiveDeletion) TableName() string       { return "archive_deletion" }
+func (baselinePreset) TableName() string                { return "preset" }
+func (baselinePresetVersion) TableName() string         { return "preset_version" }
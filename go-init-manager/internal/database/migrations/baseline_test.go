@@ -0,0 +1,62 @@
+package migrations
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+// baselineTables parses the column names of every CREATE TABLE in the baseline migration
+func baselineTables(t *testing.T) map[string][]string {
+	t.Helper()
+
+	up, err := files.ReadFile("sql/0001_baseline.up.sql")
+	if err != nil {
+		t.Fatalf("Failed to read the baseline migration: %v", err)
+	}
+
+	tables := make(map[string][]string)
+	createTable := regexp.MustCompile(`(?s)CREATE TABLE (\w+) \((.*?)\n\);`)
+	for _, match := range createTable.FindAllStringSubmatch(string(up), -1) {
+		var columns []string
+		for _, line := range strings.Split(match[2], "\n") {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				columns = append(columns, fields[0])
+			}
+		}
+		sort.Strings(columns)
+		tables[match[1]] = columns
+	}
+	return tables
+}
+
+func TestBaselineModelsMatchBaselineMigration(t *testing.T) {
+	tables := baselineTables(t)
+	if len(tables) != len(baselineModels) {
+		t.Fatalf("Baseline migration creates %d tables, baseline models have %d", len(tables), len(baselineModels))
+	}
+
+	cache := &sync.Map{}
+	for _, model := range baselineModels {
+		parsed, err := schema.Parse(model, cache, schema.NamingStrategy{})
+		if err != nil {
+			t.Fatalf("Failed to parse %T: %v", model, err)
+		}
+
+		t.Run(parsed.Table, func(t *testing.T) {
+			want, ok := tables[parsed.Table]
+			if !ok {
+				t.Fatalf("Baseline migration does not create table %s", parsed.Table)
+			}
+			got := append([]string(nil), parsed.DBNames...)
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Fatalf("Columns of %s = %v, baseline migration has %v", parsed.Table, got, want)
+			}
+		})
+	}
+}
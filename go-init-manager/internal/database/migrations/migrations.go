@@ -0,0 +1,443 @@
+// Package migrations applies the versioned SQL migrations embedded into go-init-manager.
+// Applied versions are recorded in the schema_migrations table of the configured schema;
+// the service refuses to start on a schema with versions this build does not know.
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// advisoryLockKey serializes migrations of manager replicas starting at the same time
+const advisoryLockKey = 7_016_519_036_415_854
+
+// baselineVersion is the migration that creates the tables previously created by GORM AutoMigrate
+const baselineVersion = 1
+
+var (
+	// ErrUnknownVersion means the schema was migrated by a newer build
+	ErrUnknownVersion = errors.New("database schema has migrations unknown to this build")
+	// ErrPending means the schema is older than this build and auto_migrate is off
+	ErrPending = errors.New("database schema has pending migrations")
+	// ErrChecksumMismatch means an applied migration was edited after it had been applied
+	ErrChecksumMismatch = errors.New("applied migration differs from the embedded one")
+)
+
+// Migration is one embedded up/down pair, named NNNN_name.up.sql and NNNN_name.down.sql
+type Migration struct {
+	Version  int
+	Name     string
+	Checksum string
+	up       string
+	down     string
+}
+
+// Status describes an embedded or applied migration
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Known is false for versions applied by a newer build
+	Known bool
+}
+
+// appliedMigration is a row of schema_migrations
+type appliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies embedded migrations to one schema
+type Migrator struct {
+	db         *orm.AgentImpl
+	log        *logger.Logger
+	schema     string
+	migrations []Migration
+}
+
+// New loads the embedded migrations for the given schema
+func New(db *orm.AgentImpl, log *logger.Logger, schema string) (*Migrator, error) {
+	if schema == "" {
+		return nil, fmt.Errorf("postgres_db.schema is not configured")
+	}
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		log:        log,
+		schema:     schema,
+		migrations: migrations,
+	}, nil
+}
+
+// load reads and pairs the migration files, ordered by version
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		if !ok || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("migration %s: expected NNNN_name.up.sql or NNNN_name.down.sql", name)
+		}
+		prefix, title, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: invalid version %q", name, prefix)
+		}
+
+		body, err := fs.ReadFile(fsys, path.Join("sql", name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: title}
+			byVersion[version] = migration
+		} else if migration.Name != title {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, title)
+		}
+		if direction == "up" {
+			migration.up = string(body)
+			sum := sha256.Sum256(body)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Up applies all pending migrations and returns how many were applied.
+// A schema created by AutoMigrate before migrations existed is adopted as the baseline.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		done, err := m.apply(ctx, migration)
+		if err != nil {
+			return count, err
+		}
+		if done {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Down rolls back the last steps applied migrations and returns how many were rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("number of migrations to roll back must be positive")
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		done, err := m.revert(ctx, migration)
+		if err != nil {
+			return count, err
+		}
+		if done {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Status lists embedded migrations and versions applied by newer builds, ordered by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name, Known: true}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		result = append(result, status)
+	}
+	for _, row := range applied {
+		result = append(result, Status{Version: row.Version, Name: row.Name, Applied: true, AppliedAt: &row.AppliedAt})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Check verifies that the schema is exactly at the latest embedded version without changing it
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	if err := m.verify(applied); err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %s, run `migrate up` or enable postgres_db.auto_migrate",
+			ErrPending, strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// verify rejects versions unknown to this build and applied migrations whose files changed
+func (m *Migrator) verify(applied map[int]appliedMigration) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, row := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: version %d (%s) in schema %s", ErrUnknownVersion, version, row.Name, m.schema)
+		}
+		if row.Checksum != migration.Checksum {
+			return fmt.Errorf("%w: %04d_%s", ErrChecksumMismatch, version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// ensureTable creates the schema and the schema_migrations table
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	schema := m.quotedSchema()
+	err := m.db.DB().WithContext(ctx).Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s;
+CREATE TABLE IF NOT EXISTS %s.schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    checksum   TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)`, schema, schema)).Error
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// applied returns the rows of schema_migrations by version; a missing table means nothing is applied
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	db := m.db.DB().WithContext(ctx)
+
+	exists, err := m.tableExists(db, "schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	result := map[int]appliedMigration{}
+	if !exists {
+		return result, nil
+	}
+
+	var rows []appliedMigration
+	err = db.Raw(fmt.Sprintf("SELECT version, name, checksum, applied_at FROM %s.schema_migrations", m.quotedSchema())).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for _, row := range rows {
+		result[row.Version] = row
+	}
+	return result, nil
+}
+
+// apply runs one migration in its own transaction; false means another replica applied it first
+func (m *Migrator) apply(ctx context.Context, migration Migration) (bool, error) {
+	applied := false
+	err := m.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := m.lock(tx); err != nil {
+			return err
+		}
+		done, err := m.isApplied(tx, migration.Version)
+		if err != nil || done {
+			return err
+		}
+
+		adopted, err := m.adoptLegacySchema(tx, migration)
+		if err != nil {
+			return err
+		}
+		if !adopted {
+			if err := tx.Exec(migration.up).Error; err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+		}
+
+		err = tx.Exec(fmt.Sprintf("INSERT INTO %s.schema_migrations (version, name, checksum) VALUES (?, ?, ?)", m.quotedSchema()),
+			migration.Version, migration.Name, migration.Checksum).Error
+		if err != nil {
+			return fmt.Errorf("failed to record migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if applied {
+		m.log.Info(fmt.Sprintf("Applied migration %04d_%s to schema %s", migration.Version, migration.Name, m.schema))
+	}
+	return applied, nil
+}
+
+// revert rolls one migration back in its own transaction; false means another replica reverted it first
+func (m *Migrator) revert(ctx context.Context, migration Migration) (bool, error) {
+	reverted := false
+	err := m.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := m.lock(tx); err != nil {
+			return err
+		}
+		done, err := m.isApplied(tx, migration.Version)
+		if err != nil || !done {
+			return err
+		}
+
+		if err := tx.Exec(migration.down).Error; err != nil {
+			return fmt.Errorf("rollback of migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		err = tx.Exec(fmt.Sprintf("DELETE FROM %s.schema_migrations WHERE version = ?", m.quotedSchema()), migration.Version).Error
+		if err != nil {
+			return fmt.Errorf("failed to unrecord migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		reverted = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	if reverted {
+		m.log.Info(fmt.Sprintf("Rolled back migration %04d_%s in schema %s", migration.Version, migration.Name, m.schema))
+	}
+	return reverted, nil
+}
+
+// lock takes the migration lock until the end of the transaction and points unqualified names at the schema.
+// Transaction-scoped lock and SET LOCAL keep working behind pgbouncer in transaction pooling mode.
+func (m *Migrator) lock(tx *gorm.DB) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", advisoryLockKey).Error; err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if err := tx.Exec(fmt.Sprintf("SET LOCAL search_path TO %s", m.quotedSchema())).Error; err != nil {
+		return fmt.Errorf("failed to set search_path: %w", err)
+	}
+	return nil
+}
+
+// isApplied re-reads schema_migrations under the migration lock
+func (m *Migrator) isApplied(tx *gorm.DB, version int) (bool, error) {
+	var count int64
+	err := tx.Raw(fmt.Sprintf("SELECT count(*) FROM %s.schema_migrations WHERE version = ?", m.quotedSchema()), version).
+		Scan(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	return count > 0, nil
+}
+
+// adoptLegacySchema records the baseline without running it when the tables were created by AutoMigrate.
+// AutoMigrate runs one last time to bring such a schema to the frozen baseline models first,
+// so a schema adopted by a later build does not receive columns of migrations that are recorded as pending.
+func (m *Migrator) adoptLegacySchema(tx *gorm.DB, migration Migration) (bool, error) {
+	if migration.Version != baselineVersion {
+		return false, nil
+	}
+	exists, err := m.tableExists(tx, "service_template")
+	if err != nil || !exists {
+		return false, err
+	}
+
+	m.log.Warn(fmt.Sprintf("Schema %s was created by AutoMigrate, adopting it as migration %04d_%s",
+		m.schema, migration.Version, migration.Name))
+	if err := tx.AutoMigrate(baselineModels...); err != nil {
+		return false, fmt.Errorf("failed to bring legacy schema to the baseline: %w", err)
+	}
+	return true, nil
+}
+
+// tableExists reports whether the schema has the table
+func (m *Migrator) tableExists(db *gorm.DB, table string) (bool, error) {
+	var exists bool
+	err := db.Raw("SELECT to_regclass(?) IS NOT NULL", m.quotedSchema()+"."+table).Scan(&exists).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to look up table %s: %w", table, err)
+	}
+	return exists, nil
+}
+
+func (m *Migrator) quotedSchema() string {
+	return pgx.Identifier{m.schema}.Sanitize()
+}
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS preset_version;
+DROP TABLE IF EXISTS preset;
+DROP TABLE IF EXISTS archive_deletion;
+DROP TABLE IF EXISTS outbox_event;
+DROP TABLE IF EXISTS template_revision;
+DROP TABLE IF EXISTS template_status_history;
+DROP TABLE IF EXISTS advanced_config;
+DROP TABLE IF EXISTS docker_config;
+DROP TABLE IF EXISTS database_config;
+DROP TABLE IF EXISTS endpoint;
+DROP TABLE IF EXISTS service_template;
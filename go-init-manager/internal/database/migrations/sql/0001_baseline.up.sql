@@ -0,0 +1,155 @@
+-- Базовая схема go-init-manager: таблицы, которые раньше создавал GORM AutoMigrate
+
+CREATE TABLE service_template (
+    service_template_id   BIGSERIAL PRIMARY KEY,
+    service_template_uuid UUID DEFAULT gen_random_uuid(),
+    service_template_name VARCHAR(255) NOT NULL,
+    zip_url               TEXT NOT NULL,
+    archive_bucket        VARCHAR(255),
+    archive_object        VARCHAR(1024),
+    user_id               UUID NOT NULL,
+    status                VARCHAR(50) NOT NULL DEFAULT 'pending',
+    error                 TEXT,
+    attempts              BIGINT NOT NULL DEFAULT 1,
+    revision              BIGINT NOT NULL DEFAULT 1,
+    version               VARCHAR(10),
+    archive_uuid          UUID,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_service_template_uuid ON service_template (service_template_uuid);
+CREATE UNIQUE INDEX idx_service_template_archive_uuid ON service_template (archive_uuid);
+CREATE INDEX idx_service_template_status ON service_template (status);
+-- keyset-пагинация списка шаблонов
+CREATE INDEX idx_service_template_created ON service_template (created_at, service_template_id);
+CREATE INDEX idx_service_template_name ON service_template (service_template_name, service_template_id);
+
+CREATE TABLE endpoint (
+    endpoint_id   BIGSERIAL PRIMARY KEY,
+    endpoint_uuid UUID DEFAULT gen_random_uuid(),
+    template_id   BIGINT NOT NULL REFERENCES service_template (service_template_id) ON DELETE CASCADE,
+    protocol      VARCHAR(10) NOT NULL,
+    role          VARCHAR(10) NOT NULL,
+    config        JSONB,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_endpoint_template_protocol ON endpoint (template_id, protocol);
+
+CREATE TABLE database_config (
+    database_config_id   BIGSERIAL PRIMARY KEY,
+    database_config_uuid UUID DEFAULT gen_random_uuid(),
+    template_id          BIGINT NOT NULL REFERENCES service_template (service_template_id) ON DELETE CASCADE,
+    type                 VARCHAR(10) NOT NULL,
+    ddl                  TEXT,
+    migrations           BOOLEAN DEFAULT FALSE,
+    models               BOOLEAN DEFAULT FALSE,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_database_config_template_type ON database_config (template_id, type);
+
+CREATE TABLE docker_config (
+    docker_config_id   BIGSERIAL PRIMARY KEY,
+    docker_config_uuid UUID DEFAULT gen_random_uuid(),
+    template_id        BIGINT NOT NULL REFERENCES service_template (service_template_id) ON DELETE CASCADE,
+    registry           VARCHAR(255),
+    image_name         VARCHAR(255) NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_docker_config_template_id ON docker_config (template_id);
+
+CREATE TABLE advanced_config (
+    advanced_config_id    BIGSERIAL PRIMARY KEY,
+    advanced_config_uuid  UUID DEFAULT gen_random_uuid(),
+    template_id           BIGINT NOT NULL REFERENCES service_template (service_template_id) ON DELETE CASCADE,
+    enable_authentication BOOLEAN DEFAULT FALSE,
+    generate_swagger_docs BOOLEAN DEFAULT FALSE,
+    module_path           VARCHAR(255),
+    service_description   TEXT,
+    enable_graphql        BOOLEAN DEFAULT FALSE,
+    enable_grpc           BOOLEAN DEFAULT FALSE,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_advanced_config_template_id ON advanced_config (template_id);
+
+CREATE TABLE template_status_history (
+    template_status_history_id BIGSERIAL PRIMARY KEY,
+    template_id                BIGINT NOT NULL REFERENCES service_template (service_template_id) ON DELETE CASCADE,
+    from_status                VARCHAR(50),
+    to_status                  VARCHAR(50) NOT NULL,
+    source                     VARCHAR(100) NOT NULL,
+    created_at                 TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_template_status_history_template_id ON template_status_history (template_id);
+
+CREATE TABLE template_revision (
+    template_revision_id BIGSERIAL PRIMARY KEY,
+    template_id          BIGINT NOT NULL REFERENCES service_template (service_template_id) ON DELETE CASCADE,
+    revision             BIGINT NOT NULL,
+    archive_uuid         UUID NOT NULL,
+    config               JSONB NOT NULL,
+    archive_bucket       VARCHAR(255),
+    archive_object       VARCHAR(1024),
+    created_by           UUID,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_template_revision ON template_revision (template_id, revision);
+CREATE UNIQUE INDEX idx_template_revision_archive_uuid ON template_revision (archive_uuid);
+
+CREATE TABLE outbox_event (
+    outbox_event_id BIGSERIAL PRIMARY KEY,
+    event_id        UUID NOT NULL,
+    aggregate_id    VARCHAR(64) NOT NULL,
+    topic_id        VARCHAR(100) NOT NULL,
+    event_type      VARCHAR(100) NOT NULL,
+    payload         JSONB NOT NULL,
+    attempts        BIGINT NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    sent_at         TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_outbox_event_event_id ON outbox_event (event_id);
+CREATE INDEX idx_outbox_event_aggregate_id ON outbox_event (aggregate_id);
+-- relay выбирает только неотправленные события
+CREATE INDEX idx_outbox_event_pending ON outbox_event (next_attempt_at) WHERE sent_at IS NULL;
+
+CREATE TABLE archive_deletion (
+    archive_deletion_id BIGSERIAL PRIMARY KEY,
+    archive_id          UUID NOT NULL,
+    attempts            BIGINT NOT NULL DEFAULT 0,
+    last_error          TEXT,
+    next_attempt_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_archive_deletion_archive_id ON archive_deletion (archive_id);
+CREATE INDEX idx_archive_deletion_next_attempt_at ON archive_deletion (next_attempt_at);
+
+CREATE TABLE preset (
+    preset_id   BIGSERIAL PRIMARY KEY,
+    preset_uuid UUID DEFAULT gen_random_uuid(),
+    preset_name VARCHAR(255) NOT NULL,
+    description TEXT,
+    owner_id    UUID NOT NULL,
+    team        VARCHAR(255),
+    visibility  VARCHAR(20) NOT NULL DEFAULT 'PRIVATE',
+    version     BIGINT NOT NULL DEFAULT 1,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_preset_preset_uuid ON preset (preset_uuid);
+CREATE UNIQUE INDEX idx_preset_owner_name ON preset (owner_id, preset_name);
+CREATE INDEX idx_preset_team ON preset (team);
+
+CREATE TABLE preset_version (
+    preset_version_id BIGSERIAL PRIMARY KEY,
+    preset_id         BIGINT NOT NULL REFERENCES preset (preset_id) ON DELETE CASCADE,
+    version           BIGINT NOT NULL,
+    input             JSONB NOT NULL,
+    created_by        UUID NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_preset_version ON preset_version (preset_id, version);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_template_status_history_template_created;
+DROP INDEX IF EXISTS idx_service_template_user_created;
@@ -0,0 +1,4 @@
+-- Шаблоны владельца в порядке создания
+CREATE INDEX IF NOT EXISTS idx_service_template_user_created ON service_template (user_id, created_at);
+-- История статусов шаблона в хронологическом порядке
+CREATE INDEX IF NOT EXISTS idx_template_status_history_template_created ON template_status_history (template_id, created_at);
@@ -0,0 +1,11 @@
+-- CloudEvents, уже обработанные потребителем менеджера; повторные доставки пропускаются.
+-- По спецификации CloudEvents событие уникально по паре source и id
+CREATE TABLE processed_events (
+    source       VARCHAR(255) NOT NULL,
+    event_id     VARCHAR(255) NOT NULL,
+    event_type   VARCHAR(100) NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, event_id)
+);
+-- очистка записей старше срока хранения
+CREATE INDEX idx_processed_events_processed_at ON processed_events (processed_at);
@@ -0,0 +1,10 @@
+-- Счетчики запросов и созданий шаблонов в фиксированных окнах, общие для всех реплик
+CREATE TABLE rate_limit_counter (
+    counter_key  VARCHAR(255) NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    hits         INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (counter_key, window_start)
+);
+-- удаление истекших окон
+CREATE INDEX idx_rate_limit_counter_expires_at ON rate_limit_counter (expires_at);
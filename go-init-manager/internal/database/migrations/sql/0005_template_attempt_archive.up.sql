@@ -0,0 +1,10 @@
+-- ID архивов прежних попыток генерации: каждая повторная попытка получает новый ID архива,
+-- поздние события прежних попыток находят по нему шаблон и не меняют его
+CREATE TABLE template_attempt_archive (
+    archive_uuid UUID PRIMARY KEY,
+    template_id  BIGINT NOT NULL REFERENCES service_template (service_template_id) ON DELETE CASCADE,
+    revision     BIGINT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+-- архивы попыток удаляемого шаблона
+CREATE INDEX idx_template_attempt_archive_template_id ON template_attempt_archive (template_id);
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// OutboxPublishFunc publishes a batch of events and returns one error (or nil) per event, in order
+type OutboxPublishFunc func(ctx context.Context, events []*dbModel.OutboxEvent) []error
+
+// OutboxStats describes events that are still waiting to be published
+type OutboxStats struct {
+	Pending         int64
+	OldestPendingAt *time.Time
+}
+
+// OutboxRepository stores events written in the same transaction as the data they describe
+// and tracks their delivery to Kafka
+type OutboxRepository interface {
+	AddOutboxEvent(ctx context.Context, event *dbModel.OutboxEvent, tx *orm.Transaction) error
+	// PublishPending claims up to limit due events for lease, skipping rows held by other replicas,
+	// hands them to publish outside of the claiming transaction and records the outcome.
+	// Failed events are retried after retryDelay(attempts); events of a replica that died while publishing after lease.
+	PublishPending(ctx context.Context, limit int, lease time.Duration, publish OutboxPublishFunc, retryDelay func(attempts int) time.Duration) (int, error)
+	GetOutboxStats(ctx context.Context) (*OutboxStats, error)
+	DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) (int64, error)
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"github.com/google/uuid"
+)
+
+// Видимость пресета
+const (
+	PresetVisibilityPrivate = "PRIVATE" // только владелец
+	PresetVisibilityTeam    = "TEAM"    // члены команды пресета
+	PresetVisibilityPublic  = "PUBLIC"  // все пользователи
+)
+
+var (
+	// ErrPresetNotFound is returned when a preset or its version does not exist
+	ErrPresetNotFound = errors.New("preset not found")
+	// ErrPresetNameTaken is returned when the owner already has a preset with this name
+	ErrPresetNameTaken = errors.New("preset name is already used")
+)
+
+// PresetListParams filters a preset listing
+type PresetListParams struct {
+	// ViewerID and ViewerTeams limit the listing to presets the viewer can see; ignored when All is set
+	ViewerID    uuid.UUID
+	ViewerTeams []string
+	All         bool
+
+	OwnerID      *uuid.UUID
+	Team         *string
+	Visibility   *string
+	NameContains *string
+	Limit        int
+}
+
+// PresetUpdate holds changed preset fields; nil fields are kept and an empty Team clears the team.
+// A non-nil Input is saved as a new version of the preset.
+type PresetUpdate struct {
+	Name        *string
+	Description *string
+	Team        *string
+	Visibility  *string
+	Input       []byte
+	UpdatedBy   uuid.UUID
+}
+
+// PresetRepository stores template presets and their versions
+type PresetRepository interface {
+	// CreatePreset saves the preset together with its first version
+	CreatePreset(ctx context.Context, preset *dbModel.Preset, input []byte) error
+	GetPresetByUUID(ctx context.Context, presetUUID uuid.UUID) (*dbModel.Preset, error)
+	// GetPresetVersion returns a version of the preset; version 0 means the current one
+	GetPresetVersion(ctx context.Context, presetID int, version int) (*dbModel.PresetVersion, error)
+	// ListPresets returns matching presets with only their current version in Versions
+	ListPresets(ctx context.Context, params PresetListParams) ([]*dbModel.Preset, error)
+	// UpdatePreset applies the update and returns the updated preset
+	UpdatePreset(ctx context.Context, presetID int, update PresetUpdate) (*dbModel.Preset, error)
+	DeletePreset(ctx context.Context, presetID int) error
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// ProcessedEvent identifies a consumed CloudEvent; CloudEvents are unique by source and ID
+type ProcessedEvent struct {
+	Source string
+	ID     string
+	Type   string
+}
+
+// ProcessedEventRepository records consumed events so that redeliveries of the same event are skipped
+type ProcessedEventRepository interface {
+	// MarkEventProcessed records the event within the caller's transaction, together with the changes it causes.
+	// It returns false when the event is already recorded; a concurrent delivery waits until the first one commits.
+	MarkEventProcessed(ctx context.Context, event ProcessedEvent, tx *orm.Transaction) (bool, error)
+	// DeleteProcessedEvents removes records of events processed before processedBefore
+	DeleteProcessedEvents(ctx context.Context, processedBefore time.Time) (int64, error)
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitCounter is the counter of one limit in one fixed window
+type RateLimitCounter struct {
+	// Name of the limit, reported when it is exhausted
+	Name string
+	// Key identifies the counter row the hit is counted in, e.g. the limit name and the user it applies to
+	Key string
+	// ShardKeys are all rows of a global counter spread over several rows, Key among them.
+	// Limit applies to their sum; empty for counters kept in a single row
+	ShardKeys   []string
+	WindowStart time.Time
+	WindowEnd   time.Time
+	// Limit is the number of hits allowed in the window
+	Limit int
+}
+
+// RateLimitRepository stores rate limit counters shared by all replicas
+type RateLimitRepository interface {
+	// IncrementRateLimits counts a hit in every counter in one transaction, unless one of them is at its limit.
+	// It returns the exhausted counter, in which case nothing is counted, or nil when the hit was counted.
+	IncrementRateLimits(ctx context.Context, counters []RateLimitCounter) (*RateLimitCounter, error)
+	// RefundRateLimits takes back a hit counted by IncrementRateLimits
+	RefundRateLimits(ctx context.Context, counters []RateLimitCounter) error
+	// DeleteExpiredRateLimits removes counters of windows that ended before expiredBefore
+	DeleteExpiredRateLimits(ctx context.Context, expiredBefore time.Time) (int64, error)
+}
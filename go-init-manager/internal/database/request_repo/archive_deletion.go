@@ -0,0 +1,94 @@
+package request_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"github.com/google/uuid"
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NewArchiveDeletionRepository creates the archive deletion queue on the same schema as NewRepository
+func NewArchiveDeletionRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string) database.ArchiveDeletionRepository {
+	return newRepository(db, log, schemaName...)
+}
+
+// AddArchiveDeletion queues an archive within the caller's transaction; queueing it twice is a no-op
+func (r *Repository) AddArchiveDeletion(ctx context.Context, archiveID uuid.UUID, tx *orm.Transaction) error {
+	err := tx.Tx.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&dbModel.ArchiveDeletion{ArchiveId: &archiveID}).Error
+	if err != nil {
+		return fmt.Errorf("failed to queue archive deletion: %w", err)
+	}
+	return nil
+}
+
+// ProcessArchiveDeletions claims due deletions and calls del outside of any transaction: claimed rows are postponed
+// by lease, so other replicas skip them while the publisher is deleting the archives
+func (r *Repository) ProcessArchiveDeletions(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+	del database.ArchiveDeleteFunc,
+	retryDelay func(attempts int) time.Duration,
+) (int, error) {
+	deletions, err := archiveDeletionQueue.claim(ctx, r.db.DB(), limit, lease)
+	if err != nil || len(deletions) == 0 {
+		return 0, err
+	}
+
+	results := make([]error, len(deletions))
+	for i, deletion := range deletions {
+		results[i] = del(ctx, *deletion.ArchiveId)
+	}
+	// Результат записывается и при остановке сервиса, иначе удаленные архивы будут удаляться повторно после аренды
+	return len(deletions), r.recordArchiveDeletionResults(context.WithoutCancel(ctx), deletions, results, retryDelay)
+}
+
+// archiveDeletionQueue claims queued archive deletions
+var archiveDeletionQueue = leasedQueue[dbModel.ArchiveDeletion]{
+	name:     "archive deletions",
+	idColumn: "archive_deletion_id",
+	due:      "next_attempt_at <= NOW()",
+	id:       func(deletion *dbModel.ArchiveDeletion) int { return *deletion.ArchiveDeletionId },
+	attempts: func(deletion *dbModel.ArchiveDeletion) int { return deletion.Attempts },
+}
+
+// recordArchiveDeletionResults removes completed deletions from the queue and reschedules the failed ones
+func (r *Repository) recordArchiveDeletionResults(
+	ctx context.Context,
+	deletions []*dbModel.ArchiveDeletion,
+	results []error,
+	retryDelay func(attempts int) time.Duration,
+) error {
+	return r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		done, err := archiveDeletionQueue.reschedule(tx, deletions, results, retryDelay)
+		if err != nil || len(done) == 0 {
+			return err
+		}
+
+		err = tx.Where("archive_deletion_id IN ?", archiveDeletionQueue.ids(done)).
+			Delete(&dbModel.ArchiveDeletion{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to remove completed archive deletions: %w", err)
+		}
+		return nil
+	})
+}
+
+// CountArchiveDeletions returns the number of archives still waiting to be deleted
+func (r *Repository) CountArchiveDeletions(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.DB().WithContext(ctx).Model(&dbModel.ArchiveDeletion{}).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count archive deletions: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,87 @@
+package request_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// leasedQueue describes a table of jobs that replicas claim for a lease and work on outside of any transaction,
+// such as outbox events and archive deletions. Failed jobs are retried with backoff.
+type leasedQueue[T any] struct {
+	// name is the plural name of the jobs used in errors
+	name     string
+	idColumn string
+	// due selects jobs ready to be claimed
+	due      string
+	id       func(job *T) int
+	attempts func(job *T) int
+}
+
+// claim locks up to limit due jobs, skipping rows held by other replicas, and postpones them by lease
+func (q leasedQueue[T]) claim(ctx context.Context, db *gorm.DB, limit int, lease time.Duration) ([]*T, error) {
+	var jobs []*T
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where(q.due).
+			Order(q.idColumn).
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil {
+			return fmt.Errorf("failed to fetch pending %s: %w", q.name, err)
+		}
+		if len(jobs) == 0 {
+			return nil
+		}
+
+		err = tx.Model(new(T)).
+			Where(q.idColumn+" IN ?", q.ids(jobs)).
+			Update("next_attempt_at", gorm.Expr("NOW() + make_interval(secs => ?)", lease.Seconds())).Error
+		if err != nil {
+			return fmt.Errorf("failed to claim %s: %w", q.name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// reschedule records the failed attempt of every job with an error in results within tx
+// and returns the jobs that succeeded; the caller completes them
+func (q leasedQueue[T]) reschedule(tx *gorm.DB, jobs []*T, results []error, retryDelay func(attempts int) time.Duration) ([]*T, error) {
+	now := time.Now()
+	done := make([]*T, 0, len(jobs))
+	for i, job := range jobs {
+		if results[i] == nil {
+			done = append(done, job)
+			continue
+		}
+
+		attempts := q.attempts(job) + 1
+		err := tx.Model(new(T)).
+			Where(q.idColumn+" = ?", q.id(job)).
+			Updates(map[string]any{
+				"attempts":        attempts,
+				"last_error":      results[i].Error(),
+				"next_attempt_at": now.Add(retryDelay(attempts)),
+			}).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to reschedule %s: %w", q.name, err)
+		}
+	}
+	return done, nil
+}
+
+// ids returns the primary keys of jobs
+func (q leasedQueue[T]) ids(jobs []*T) []int {
+	ids := make([]int, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, q.id(job))
+	}
+	return ids
+}
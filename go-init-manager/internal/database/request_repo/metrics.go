@@ -0,0 +1,35 @@
+package request_repo
+
+import (
+	"time"
+
+	"go-init/internal/database"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+var (
+	templateStatuses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "go_init_manager_template_status_total",
+		Help: "Templates that entered a status, by status: PENDING on creation, retry and update, then the statuses reported by the pipeline.",
+	}, []string{"status"})
+	generationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "go_init_manager_generation_duration_seconds",
+		Help:    "Time from the request of a generation (the last move to PENDING) to COMPLETED or FAILED, by final status.",
+		Buckets: []float64{1, 2, 5, 10, 30, 60, 120, 300, 600, 1800},
+	}, []string{"status"})
+)
+
+// recordStatusChange counts a status transition and, for final statuses, the generation latency.
+// Метрики пишутся после коммита транзакции вызывающего, откаченные переходы не учитываются.
+func recordStatusChange(tx *orm.Transaction, status string, requestedAt *time.Time) {
+	database.AfterCommit(tx, func() {
+		templateStatuses.WithLabelValues(status).Inc()
+		if requestedAt == nil || (status != database.StatusCompleted && status != database.StatusFailed) {
+			return
+		}
+		generationDuration.WithLabelValues(status).Observe(time.Since(*requestedAt).Seconds())
+	})
+}
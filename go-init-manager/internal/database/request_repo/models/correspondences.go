@@ -79,3 +79,93 @@ func (m *AdvancedConfig) Name() string {
 func (m *AdvancedConfig) GenericID() db.GenericID {
 	return m.AdvancedConfigId
 }
+
+// ==================================
+// TemplateStatusHistory methods
+// ==================================
+func (m *TemplateStatusHistory) String() string {
+	return db.ModelToString(m)
+}
+
+func (m *TemplateStatusHistory) Name() string {
+	return "TemplateStatusHistory"
+}
+
+func (m *TemplateStatusHistory) GenericID() db.GenericID {
+	return m.TemplateStatusHistoryId
+}
+
+// ==================================
+// TemplateRevision methods
+// ==================================
+func (m *TemplateRevision) String() string {
+	return db.ModelToString(m)
+}
+
+func (m *TemplateRevision) Name() string {
+	return "TemplateRevision"
+}
+
+func (m *TemplateRevision) GenericID() db.GenericID {
+	return m.TemplateRevisionId
+}
+
+// ==================================
+// OutboxEvent methods
+// ==================================
+func (m *OutboxEvent) String() string {
+	return db.ModelToString(m)
+}
+
+func (m *OutboxEvent) Name() string {
+	return "OutboxEvent"
+}
+
+func (m *OutboxEvent) GenericID() db.GenericID {
+	return m.OutboxEventId
+}
+
+// ==================================
+// ArchiveDeletion methods
+// ==================================
+func (m *ArchiveDeletion) String() string {
+	return db.ModelToString(m)
+}
+
+func (m *ArchiveDeletion) Name() string {
+	return "ArchiveDeletion"
+}
+
+func (m *ArchiveDeletion) GenericID() db.GenericID {
+	return m.ArchiveDeletionId
+}
+
+// ==================================
+// Preset methods
+// ==================================
+func (m *Preset) String() string {
+	return db.ModelToString(m)
+}
+
+func (m *Preset) Name() string {
+	return "Preset"
+}
+
+func (m *Preset) GenericID() db.GenericID {
+	return m.PresetId
+}
+
+// ==================================
+// PresetVersion methods
+// ==================================
+func (m *PresetVersion) String() string {
+	return db.ModelToString(m)
+}
+
+func (m *PresetVersion) Name() string {
+	return "PresetVersion"
+}
+
+func (m *PresetVersion) GenericID() db.GenericID {
+	return m.PresetVersionId
+}
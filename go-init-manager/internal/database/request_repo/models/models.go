@@ -6,42 +6,49 @@ import (
 	"github.com/google/uuid"
 )
 
-// Массив моделей для AutoMigrate в GORM
-var Models = []interface{}{
-	&ServiceTemplate{},
-	&Endpoint{},
-	&DatabaseConfig{},
-	&DockerConfig{},
-	&AdvancedConfig{},
-}
+// Схему таблиц задают SQL-миграции в internal/database/migrations, теги gorm должны им соответствовать
 
 // ===========================
 // Request
 // ===========================
 type ServiceTemplate struct {
-	ServiceTemplateId   *int       `gorm:"column:service_template_id;primaryKey;autoIncrement"`
-	ServiceTemplateUuid *uuid.UUID `gorm:"column:service_template_uuid;type:uuid;default:gen_random_uuid()"`
+	// Составные индексы (created_at, id) и (name, id) обслуживают keyset-пагинацию списка шаблонов
+	ServiceTemplateId   *int       `gorm:"column:service_template_id;primaryKey;autoIncrement;index:idx_service_template_created,priority:2;index:idx_service_template_name,priority:2"`
+	ServiceTemplateUuid *uuid.UUID `gorm:"column:service_template_uuid;type:uuid;default:gen_random_uuid();uniqueIndex"`
 
-	ServiceTemplateName *string `gorm:"type:varchar(255);not null"`
-	ZipURL              *string `gorm:"type:text;not null"`
+	ServiceTemplateName *string `gorm:"type:varchar(255);not null;index:idx_service_template_name,priority:1"`
+	// Внешняя ссылка на архив; для архивов publisher ссылка выдается по запросу через ArchiveBucket/ArchiveObject
+	ZipURL *string `gorm:"type:text;not null"`
+	// Расположение архива в хранилище publisher
+	ArchiveBucket *string `gorm:"type:varchar(255)"`
+	ArchiveObject *string `gorm:"type:varchar(1024)"`
 
 	// владелец (кто создал этот шаблон)
 	UserId *uuid.UUID `gorm:"column:user_id;type:uuid;not null"`
 	// Новый статус
-	Status *string `gorm:"type:varchar(50);not null;default:'pending'"`
+	Status *string `gorm:"type:varchar(50);not null;default:'pending';index"`
 	// Информация об ошибке, если статус FAILED
 	Error *string `gorm:"type:text"`
+	// Номер попытки генерации, увеличивается при каждом retryTemplate
+	Attempts int `gorm:"not null;default:1"`
 
-	// Версия API, которая была использована при создании шаблона
+	// Номер текущей ревизии конфигурации, растет при каждом updateTemplate
+	Revision int `gorm:"not null;default:1"`
+	// Номер текущей ревизии строкой (поле version в API); у старых шаблонов - версия API "1.0"
 	Version *string `gorm:"type:varchar(10)"`
+	// ID архива текущей ревизии: под ним генератор и publisher сообщают о генерации.
+	// Пусто, пока шаблон не менялся: тогда ID архива совпадает с UUID шаблона
+	ArchiveUuid *uuid.UUID `gorm:"column:archive_uuid;type:uuid;uniqueIndex"`
 
-	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_service_template_created,priority:1"`
 	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 
-	Endpoints       []*Endpoint       `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
-	DatabaseConfigs []*DatabaseConfig `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
-	DockerConfigs   []*DockerConfig   `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
-	AdvancedConfigs []*AdvancedConfig `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	Endpoints       []*Endpoint              `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	DatabaseConfigs []*DatabaseConfig        `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	DockerConfigs   []*DockerConfig          `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	AdvancedConfigs []*AdvancedConfig        `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	StatusHistory   []*TemplateStatusHistory `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+	Revisions       []*TemplateRevision      `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
 	// Удалили поле Requests []*Request
 }
 
@@ -52,10 +59,11 @@ type Endpoint struct {
 	EndpointId   *int       `gorm:"column:endpoint_id;primaryKey;autoIncrement"`
 	EndpointUuid *uuid.UUID `gorm:"column:endpoint_uuid;type:uuid;default:gen_random_uuid()"`
 
-	TemplateId int `gorm:"column:template_id;not null"`
+	TemplateId int `gorm:"column:template_id;not null;index:idx_endpoint_template_protocol,priority:1"`
 
-	Protocol  *string    `gorm:"type:varchar(10);not null"` // 'GRPC','REST','GRAPHQL'
-	Role      *string    `gorm:"type:varchar(10);not null"` // 'CLIENT','SERVER'
+	Protocol  *string    `gorm:"type:varchar(10);not null;index:idx_endpoint_template_protocol,priority:2"` // 'GRPC','REST','GRAPHQL'
+	Role      *string    `gorm:"type:varchar(10);not null"`                                                 // 'CLIENT','SERVER'
+	Config    []byte     `gorm:"type:jsonb"`                                                                // параметры для генератора: {"key": "value"}
 	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 
@@ -69,12 +77,14 @@ type DatabaseConfig struct {
 	DatabaseConfigId   *int       `gorm:"column:database_config_id;primaryKey;autoIncrement"`
 	DatabaseConfigUuid *uuid.UUID `gorm:"column:database_config_uuid;type:uuid;default:gen_random_uuid()"`
 
-	TemplateId int `gorm:"column:template_id;not null"`
+	TemplateId int `gorm:"column:template_id;not null;index:idx_database_config_template_type,priority:1"`
 
-	Type      *string    `gorm:"type:varchar(10);not null"` // 'POSTGRESQL', 'MYSQL', 'NONE'
-	DDL       *string    `gorm:"type:text"`
-	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
-	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	Type       *string    `gorm:"type:varchar(10);not null;index:idx_database_config_template_type,priority:2"` // 'POSTGRESQL', 'MYSQL', 'NONE'
+	DDL        *string    `gorm:"type:text"`
+	Migrations *bool      `gorm:"default:false"` // генерировать миграции из DDL
+	Models     *bool      `gorm:"default:false"` // генерировать модели из DDL
+	CreatedAt  *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt  *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 
 	Template *ServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
 }
@@ -86,7 +96,7 @@ type DockerConfig struct {
 	DockerConfigId   *int       `gorm:"column:docker_config_id;primaryKey;autoIncrement"`
 	DockerConfigUuid *uuid.UUID `gorm:"column:docker_config_uuid;type:uuid;default:gen_random_uuid()"`
 
-	TemplateId int `gorm:"column:template_id;not null"`
+	TemplateId int `gorm:"column:template_id;not null;index"`
 
 	Registry  *string    `gorm:"type:varchar(255)"`
 	ImageName *string    `gorm:"type:varchar(255);not null"`
@@ -103,12 +113,138 @@ type AdvancedConfig struct {
 	AdvancedConfigId   *int       `gorm:"column:advanced_config_id;primaryKey;autoIncrement"`
 	AdvancedConfigUuid *uuid.UUID `gorm:"column:advanced_config_uuid;type:uuid;default:gen_random_uuid()"`
 
-	TemplateId int `gorm:"column:template_id;not null"`
+	TemplateId int `gorm:"column:template_id;not null;index"`
 
 	EnableAuthentication *bool      `gorm:"default:false"`
 	GenerateSwaggerDocs  *bool      `gorm:"default:false"`
+	ModulePath           *string    `gorm:"type:varchar(255)"`
+	ServiceDescription   *string    `gorm:"type:text"`
+	EnableGraphQL        *bool      `gorm:"column:enable_graphql;default:false"`
+	EnableGRPC           *bool      `gorm:"column:enable_grpc;default:false"`
 	CreatedAt            *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt            *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
 
 	Template *ServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
 }
+
+// ===========================
+// TemplateStatusHistory
+// ===========================
+type TemplateStatusHistory struct {
+	TemplateStatusHistoryId *int `gorm:"column:template_status_history_id;primaryKey;autoIncrement"`
+
+	TemplateId int `gorm:"column:template_id;not null;index"`
+
+	// Предыдущий статус (NULL для первой записи при создании шаблона)
+	FromStatus *string `gorm:"type:varchar(50)"`
+	ToStatus   *string `gorm:"type:varchar(50);not null"`
+	// Сервис, инициировавший переход (go-init-manager, go-init-generator, ...)
+	Source    *string    `gorm:"type:varchar(100);not null"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *ServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+// ===========================
+// TemplateRevision
+// ===========================
+// Неизменяемый снимок конфигурации шаблона и архив, сгенерированный по нему
+type TemplateRevision struct {
+	TemplateRevisionId *int `gorm:"column:template_revision_id;primaryKey;autoIncrement"`
+
+	TemplateId int `gorm:"column:template_id;not null;uniqueIndex:idx_template_revision,priority:1"`
+	Revision   int `gorm:"not null;uniqueIndex:idx_template_revision,priority:2"`
+	// ID архива ревизии, у первой ревизии совпадает с UUID шаблона
+	ArchiveUuid *uuid.UUID `gorm:"column:archive_uuid;type:uuid;not null;uniqueIndex"`
+	// CreateTemplateInput ревизии в JSON
+	Config []byte `gorm:"type:jsonb;not null"`
+
+	// Расположение архива ревизии в хранилище publisher, пусто до окончания генерации
+	ArchiveBucket *string `gorm:"type:varchar(255)"`
+	ArchiveObject *string `gorm:"type:varchar(1024)"`
+
+	CreatedBy *uuid.UUID `gorm:"column:created_by;type:uuid"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Template *ServiceTemplate `gorm:"foreignKey:TemplateId;references:ServiceTemplateId;constraint:OnDelete:CASCADE"`
+}
+
+// ===========================
+// OutboxEvent
+// ===========================
+// Событие, записанное в той же транзакции, что и изменение данных, и ожидающее отправки в Kafka
+type OutboxEvent struct {
+	OutboxEventId *int `gorm:"column:outbox_event_id;primaryKey;autoIncrement"`
+	// ID CloudEvent, не меняется при повторных отправках
+	EventId *uuid.UUID `gorm:"column:event_id;type:uuid;not null;uniqueIndex"`
+
+	// Сущность, к которой относится событие (UUID шаблона)
+	AggregateId *string `gorm:"type:varchar(64);not null;index"`
+	TopicId     *string `gorm:"type:varchar(100);not null"`
+	EventType   *string `gorm:"type:varchar(100);not null"`
+	// Сериализованный CloudEvent
+	Payload []byte `gorm:"type:jsonb;not null"`
+
+	Attempts  int     `gorm:"not null;default:0"`
+	LastError *string `gorm:"type:text"`
+
+	NextAttemptAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_outbox_event_pending,where:sent_at IS NULL"`
+	CreatedAt     *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	SentAt        *time.Time
+}
+
+// ===========================
+// ArchiveDeletion
+// ===========================
+// Архив удаленного шаблона, который еще нужно удалить из хранилища publisher
+type ArchiveDeletion struct {
+	ArchiveDeletionId *int `gorm:"column:archive_deletion_id;primaryKey;autoIncrement"`
+	// ID архива ревизии удаленного шаблона
+	ArchiveId *uuid.UUID `gorm:"column:archive_id;type:uuid;not null;uniqueIndex"`
+
+	Attempts  int     `gorm:"not null;default:0"`
+	LastError *string `gorm:"type:text"`
+
+	NextAttemptAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index"`
+	CreatedAt     *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
+
+// ===========================
+// Preset
+// ===========================
+// Сохраненные параметры CreateTemplateInput, из которых можно создавать шаблоны
+type Preset struct {
+	PresetId   *int       `gorm:"column:preset_id;primaryKey;autoIncrement"`
+	PresetUuid *uuid.UUID `gorm:"column:preset_uuid;type:uuid;default:gen_random_uuid();uniqueIndex"`
+
+	// Имя уникально в пределах владельца
+	PresetName  *string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_preset_owner_name,priority:2"`
+	Description *string    `gorm:"type:text"`
+	OwnerId     *uuid.UUID `gorm:"column:owner_id;type:uuid;not null;uniqueIndex:idx_preset_owner_name,priority:1"`
+	// Команда, которой доступен пресет с видимостью TEAM
+	Team       *string `gorm:"type:varchar(255);index"`
+	Visibility *string `gorm:"type:varchar(20);not null;default:'PRIVATE'"` // 'PRIVATE', 'TEAM', 'PUBLIC'
+	// Текущая версия параметров, растет при каждом изменении шаблона пресета
+	Version int `gorm:"not null;default:1"`
+
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+
+	Versions []*PresetVersion `gorm:"foreignKey:PresetId;references:PresetId;constraint:OnDelete:CASCADE"`
+}
+
+// ===========================
+// PresetVersion
+// ===========================
+// Неизменяемая версия параметров пресета
+type PresetVersion struct {
+	PresetVersionId *int `gorm:"column:preset_version_id;primaryKey;autoIncrement"`
+
+	PresetId int `gorm:"column:preset_id;not null;uniqueIndex:idx_preset_version,priority:1"`
+	Version  int `gorm:"not null;uniqueIndex:idx_preset_version,priority:2"`
+	// CreateTemplateInput в JSON
+	Input []byte `gorm:"type:jsonb;not null"`
+	// Пользователь, сохранивший версию
+	CreatedBy *uuid.UUID `gorm:"type:uuid;not null"`
+	CreatedAt *time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+}
@@ -0,0 +1,109 @@
+package request_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"gorm.io/gorm"
+)
+
+// NewOutboxRepository creates the outbox repository on the same schema as NewRepository
+func NewOutboxRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string) database.OutboxRepository {
+	return newRepository(db, log, schemaName...)
+}
+
+// AddOutboxEvent stores an event within the caller's transaction
+func (r *Repository) AddOutboxEvent(ctx context.Context, event *dbModel.OutboxEvent, tx *orm.Transaction) error {
+	if err := tx.Tx.WithContext(ctx).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to add outbox event: %w", err)
+	}
+	return nil
+}
+
+// PublishPending claims due events and publishes them outside of any transaction: claimed rows are postponed
+// by lease, so other replicas skip them while Kafka is waiting for acknowledgements
+func (r *Repository) PublishPending(
+	ctx context.Context,
+	limit int,
+	lease time.Duration,
+	publish database.OutboxPublishFunc,
+	retryDelay func(attempts int) time.Duration,
+) (int, error) {
+	events, err := outboxQueue.claim(ctx, r.db.DB(), limit, lease)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	results := publish(ctx, events)
+	// Результат записывается и при остановке сервиса, иначе отправленные события уйдут повторно после аренды
+	return len(events), r.recordOutboxResults(context.WithoutCancel(ctx), events, results, retryDelay)
+}
+
+// outboxQueue claims unpublished events
+var outboxQueue = leasedQueue[dbModel.OutboxEvent]{
+	name:     "outbox events",
+	idColumn: "outbox_event_id",
+	due:      "sent_at IS NULL AND next_attempt_at <= NOW()",
+	id:       func(event *dbModel.OutboxEvent) int { return *event.OutboxEventId },
+	attempts: func(event *dbModel.OutboxEvent) int { return event.Attempts },
+}
+
+// recordOutboxResults marks published events as sent and reschedules the failed ones
+func (r *Repository) recordOutboxResults(
+	ctx context.Context,
+	events []*dbModel.OutboxEvent,
+	results []error,
+	retryDelay func(attempts int) time.Duration,
+) error {
+	return r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		sent, err := outboxQueue.reschedule(tx, events, results, retryDelay)
+		if err != nil || len(sent) == 0 {
+			return err
+		}
+
+		err = tx.Model(&dbModel.OutboxEvent{}).
+			Where("outbox_event_id IN ?", outboxQueue.ids(sent)).
+			Updates(map[string]any{
+				"attempts": gorm.Expr("attempts + 1"),
+				"sent_at":  time.Now(),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox events as sent: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetOutboxStats returns the size and the age of the unpublished backlog
+func (r *Repository) GetOutboxStats(ctx context.Context) (*database.OutboxStats, error) {
+	var row struct {
+		Pending         int64
+		OldestPendingAt *time.Time
+	}
+	err := r.db.DB().WithContext(ctx).
+		Model(&dbModel.OutboxEvent{}).
+		Select("COUNT(*) AS pending, MIN(created_at) AS oldest_pending_at").
+		Where("sent_at IS NULL").
+		Scan(&row).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get outbox stats: %w", err)
+	}
+	return &database.OutboxStats{Pending: row.Pending, OldestPendingAt: row.OldestPendingAt}, nil
+}
+
+// DeleteSentOutboxEvents removes published events older than sentBefore
+func (r *Repository) DeleteSentOutboxEvents(ctx context.Context, sentBefore time.Time) (int64, error) {
+	result := r.db.DB().WithContext(ctx).
+		Where("sent_at IS NOT NULL AND sent_at < ?", sentBefore).
+		Delete(&dbModel.OutboxEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sent outbox events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,205 @@
+package request_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// uniqueViolation - код ошибки Postgres при нарушении уникального индекса
+const uniqueViolation = "23505"
+
+// NewPresetRepository creates the preset repository on the same schema as NewRepository
+func NewPresetRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string) database.PresetRepository {
+	return newRepository(db, log, schemaName...)
+}
+
+// CreatePreset saves the preset and its first version in one transaction
+func (r *Repository) CreatePreset(ctx context.Context, preset *dbModel.Preset, input []byte) error {
+	return r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		preset.Version = 1
+		if err := tx.Create(preset).Error; err != nil {
+			return presetWriteError(err)
+		}
+
+		version := &dbModel.PresetVersion{
+			PresetId:  *preset.PresetId,
+			Version:   preset.Version,
+			Input:     input,
+			CreatedBy: preset.OwnerId,
+		}
+		if err := tx.Create(version).Error; err != nil {
+			return fmt.Errorf("failed to save preset version: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPresetByUUID retrieves a preset without its versions
+func (r *Repository) GetPresetByUUID(ctx context.Context, presetUUID uuid.UUID) (*dbModel.Preset, error) {
+	var preset dbModel.Preset
+	err := r.db.DB().WithContext(ctx).
+		Where("preset_uuid = ?", presetUUID).
+		First(&preset).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, database.ErrPresetNotFound
+		}
+		return nil, fmt.Errorf("failed to get preset: %w", err)
+	}
+	return &preset, nil
+}
+
+// GetPresetVersion retrieves a stored version of the preset; version 0 selects the current one
+func (r *Repository) GetPresetVersion(ctx context.Context, presetID int, version int) (*dbModel.PresetVersion, error) {
+	query := r.db.DB().WithContext(ctx).Where("preset_id = ?", presetID)
+	if version > 0 {
+		query = query.Where("version = ?", version)
+	} else {
+		query = query.Order("version DESC")
+	}
+
+	var presetVersion dbModel.PresetVersion
+	if err := query.First(&presetVersion).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: version %d", database.ErrPresetNotFound, version)
+		}
+		return nil, fmt.Errorf("failed to get preset version: %w", err)
+	}
+	return &presetVersion, nil
+}
+
+// ListPresets returns presets visible to the viewer ordered by name, each with its current version preloaded
+func (r *Repository) ListPresets(ctx context.Context, params database.PresetListParams) ([]*dbModel.Preset, error) {
+	query := r.db.DB().WithContext(ctx).
+		Preload("Versions", fmt.Sprintf(
+			"version = (SELECT p.version FROM %s.preset AS p WHERE p.preset_id = preset_version.preset_id)", r.schemaName))
+
+	if !params.All {
+		visible := r.db.DB().
+			Where("owner_id = ?", params.ViewerID).
+			Or("visibility = ?", database.PresetVisibilityPublic)
+		if len(params.ViewerTeams) > 0 {
+			visible = visible.Or("visibility = ? AND team IN ?", database.PresetVisibilityTeam, params.ViewerTeams)
+		}
+		query = query.Where(visible)
+	}
+	if params.OwnerID != nil {
+		query = query.Where("owner_id = ?", *params.OwnerID)
+	}
+	if params.Team != nil {
+		query = query.Where("team = ?", *params.Team)
+	}
+	if params.Visibility != nil {
+		query = query.Where("visibility = ?", *params.Visibility)
+	}
+	if params.NameContains != nil && *params.NameContains != "" {
+		query = query.Where("preset_name ILIKE ?", "%"+escapeLike(*params.NameContains)+"%")
+	}
+
+	var presets []*dbModel.Preset
+	err := query.
+		Order("preset_name ASC").
+		Order("preset_id ASC").
+		Limit(params.Limit).
+		Find(&presets).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presets: %w", err)
+	}
+	return presets, nil
+}
+
+// UpdatePreset changes preset fields; a new input is stored as the next version.
+// The row is locked so that concurrent updates get consecutive version numbers.
+func (r *Repository) UpdatePreset(ctx context.Context, presetID int, update database.PresetUpdate) (*dbModel.Preset, error) {
+	var preset dbModel.Preset
+	err := r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("preset_id = ?", presetID).
+			First(&preset).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return database.ErrPresetNotFound
+			}
+			return fmt.Errorf("failed to lock preset: %w", err)
+		}
+
+		updates := map[string]any{"updated_at": time.Now()}
+		if update.Name != nil {
+			updates["preset_name"] = *update.Name
+		}
+		if update.Description != nil {
+			updates["description"] = *update.Description
+		}
+		if update.Team != nil {
+			if *update.Team == "" {
+				updates["team"] = nil
+			} else {
+				updates["team"] = *update.Team
+			}
+		}
+		if update.Visibility != nil {
+			updates["visibility"] = *update.Visibility
+		}
+
+		if update.Input != nil {
+			version := &dbModel.PresetVersion{
+				PresetId:  presetID,
+				Version:   preset.Version + 1,
+				Input:     update.Input,
+				CreatedBy: &update.UpdatedBy,
+			}
+			if err := tx.Create(version).Error; err != nil {
+				return fmt.Errorf("failed to save preset version: %w", err)
+			}
+			updates["version"] = version.Version
+		}
+
+		if err := tx.Model(&preset).Updates(updates).Error; err != nil {
+			return presetWriteError(err)
+		}
+		return tx.Where("preset_id = ?", presetID).First(&preset).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &preset, nil
+}
+
+// DeletePreset removes the preset with all its versions
+func (r *Repository) DeletePreset(ctx context.Context, presetID int) error {
+	return r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("preset_id = ?", presetID).Delete(&dbModel.PresetVersion{}).Error; err != nil {
+			return fmt.Errorf("failed to delete preset versions: %w", err)
+		}
+
+		result := tx.Where("preset_id = ?", presetID).Delete(&dbModel.Preset{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete preset: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return database.ErrPresetNotFound
+		}
+		return nil
+	})
+}
+
+// presetWriteError maps the owner/name unique index violation to ErrPresetNameTaken
+func presetWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return database.ErrPresetNameTaken
+	}
+	return fmt.Errorf("failed to save preset: %w", err)
+}
@@ -0,0 +1,42 @@
+package request_repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-init/internal/database"
+
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// NewProcessedEventRepository creates the log of consumed events on the same schema as NewRepository
+func NewProcessedEventRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string) database.ProcessedEventRepository {
+	return newRepository(db, log, schemaName...)
+}
+
+// MarkEventProcessed inserts the event unless it is already recorded
+func (r *Repository) MarkEventProcessed(ctx context.Context, event database.ProcessedEvent, tx *orm.Transaction) (bool, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.processed_events (source, event_id, event_type)
+		VALUES (?, ?, ?)
+		ON CONFLICT (source, event_id) DO NOTHING`, r.schemaName)
+
+	result := tx.Tx.WithContext(ctx).Exec(query, event.Source, event.ID, event.Type)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to record processed event: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteProcessedEvents removes records older than processedBefore
+func (r *Repository) DeleteProcessedEvents(ctx context.Context, processedBefore time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s.processed_events WHERE processed_at < ?`, r.schemaName)
+
+	result := r.db.DB().WithContext(ctx).Exec(query, processedBefore)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete processed events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,106 @@
+package request_repo
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go-init/internal/database"
+
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"gorm.io/gorm"
+)
+
+// NewRateLimitRepository creates the rate limit counters on the same schema as NewRepository
+func NewRateLimitRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string) database.RateLimitRepository {
+	return newRepository(db, log, schemaName...)
+}
+
+// errRateLimitExhausted rolls back the hits already counted when a later counter is exhausted
+var errRateLimitExhausted = errors.New("rate limit exhausted")
+
+// IncrementRateLimits counts a hit with one conditional upsert per counter
+func (r *Repository) IncrementRateLimits(ctx context.Context, counters []database.RateLimitCounter) (*database.RateLimitCounter, error) {
+	// Счетчики блокируются в одном порядке во всех транзакциях, чтобы не было взаимных блокировок
+	sorted := slices.Clone(counters)
+	slices.SortFunc(sorted, func(a, b database.RateLimitCounter) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.rate_limit_counter AS c (counter_key, window_start, expires_at, hits)
+		VALUES (?, ?, ?, 1)
+		ON CONFLICT (counter_key, window_start) DO UPDATE SET hits = c.hits + 1
+		WHERE c.hits < ?
+		RETURNING c.hits`, r.schemaName)
+	// Глобальный счетчик разнесен по нескольким строкам: лимит сравнивается с их суммой без блокировки,
+	// блокируется только строка, в которую засчитывается попадание. Одновременные запросы могут
+	// превысить лимит на число параллельных проверок
+	shardedQuery := fmt.Sprintf(`
+		INSERT INTO %[1]s.rate_limit_counter AS c (counter_key, window_start, expires_at, hits)
+		SELECT ?, ?, ?, 1
+		WHERE (SELECT COALESCE(SUM(hits), 0) FROM %[1]s.rate_limit_counter
+			   WHERE counter_key IN ? AND window_start = ?) < ?
+		ON CONFLICT (counter_key, window_start) DO UPDATE SET hits = c.hits + 1
+		RETURNING c.hits`, r.schemaName)
+
+	var exhausted *database.RateLimitCounter
+	err := r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range sorted {
+			counter := &sorted[i]
+			increment := tx.Raw(query, counter.Key, counter.WindowStart, counter.WindowEnd, counter.Limit)
+			if len(counter.ShardKeys) > 0 {
+				increment = tx.Raw(shardedQuery, counter.Key, counter.WindowStart, counter.WindowEnd,
+					counter.ShardKeys, counter.WindowStart, counter.Limit)
+			}
+
+			var hits []int
+			if err := increment.Scan(&hits).Error; err != nil {
+				return fmt.Errorf("failed to increment rate limit %s: %w", counter.Name, err)
+			}
+			// Строка не вернулась: условие WHERE не выполнено, лимит окна исчерпан
+			if len(hits) == 0 {
+				exhausted = counter
+				return errRateLimitExhausted
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errRateLimitExhausted) {
+		return exhausted, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// RefundRateLimits decrements the rows the hits were counted in, in the windows they were counted in
+func (r *Repository) RefundRateLimits(ctx context.Context, counters []database.RateLimitCounter) error {
+	query := fmt.Sprintf(`
+		UPDATE %s.rate_limit_counter
+		SET hits = hits - 1
+		WHERE counter_key = ? AND window_start = ? AND hits > 0`, r.schemaName)
+
+	for _, counter := range counters {
+		if err := r.db.DB().WithContext(ctx).Exec(query, counter.Key, counter.WindowStart).Error; err != nil {
+			return fmt.Errorf("failed to refund rate limit %s: %w", counter.Name, err)
+		}
+	}
+	return nil
+}
+
+// DeleteExpiredRateLimits removes counters of ended windows
+func (r *Repository) DeleteExpiredRateLimits(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s.rate_limit_counter WHERE expires_at < ?`, r.schemaName)
+
+	result := r.db.DB().WithContext(ctx).Exec(query, expiredBefore)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired rate limit counters: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
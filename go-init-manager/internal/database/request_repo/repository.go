@@ -2,8 +2,12 @@ package request_repo
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"go-init/internal/database"
 	dbModel "go-init/internal/database/request_repo/models"
@@ -12,6 +16,7 @@ import (
 	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
 	"gitlab.com/go-init/go-init-common/default/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ToDo сейчас есть бан при котором в методе получения шпблона по uuid реквеста мтоды пытвется получить сразу шаблон но надо сначала пойти в табличку с реквестом и потом из нее получить id габлона
@@ -26,6 +31,10 @@ type Repository struct {
 // NewRepository creates a new instance of Repository with direct database access
 // If schemaName is empty, "go_init" will be used as default
 func NewRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string) database.GoInitManagerRepository {
+	return newRepository(db, log, schemaName...)
+}
+
+func newRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string) *Repository {
 	schema := "go_init" // Default schema name
 
 	// Override default schema if provided
@@ -40,9 +49,22 @@ func NewRepository(db *orm.AgentImpl, log *logger.Logger, schemaName ...string)
 	}
 }
 
+// orderStatusHistory sorts preloaded status history chronologically
+func orderStatusHistory(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC")
+}
+
 // CreateNewTemplate creates a new template in the database using the provided transaction
 func (r *Repository) CreateNewTemplate(ctx context.Context, model *dbModel.ServiceTemplate, tx *orm.Transaction) error {
-	return tx.Tx.WithContext(ctx).Create(model).Error
+	if err := tx.Tx.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	status := database.StatusPending
+	if model.Status != nil {
+		status = database.NormalizeStatus(*model.Status)
+	}
+	recordStatusChange(tx, status, nil)
+	return nil
 }
 
 // GetTemplateByUUID retrieves a template by its UUID with all related data
@@ -53,6 +75,7 @@ func (r *Repository) GetTemplateByUUID(ctx context.Context, templateUUID uuid.UU
 		Preload("DatabaseConfigs").
 		Preload("DockerConfigs").
 		Preload("AdvancedConfigs").
+		Preload("StatusHistory", orderStatusHistory).
 		Where("service_template_uuid = ?", templateUUID).
 		First(&template).Error
 	if err != nil {
@@ -72,6 +95,7 @@ func (r *Repository) GetTemplateByID(ctx context.Context, templateID int) (*dbMo
 		Preload("DatabaseConfigs").
 		Preload("DockerConfigs").
 		Preload("AdvancedConfigs").
+		Preload("StatusHistory", orderStatusHistory).
 		Where("service_template_id = ?", templateID).
 		First(&template).Error
 	if err != nil {
@@ -83,14 +107,21 @@ func (r *Repository) GetTemplateByID(ctx context.Context, templateID int) (*dbMo
 	return &template, nil
 }
 
-// GetRecentTemplates retrieves a list of recent templates ordered by creation date
-func (r *Repository) GetRecentTemplates(ctx context.Context, limit int) ([]*dbModel.ServiceTemplate, error) {
+// GetRecentTemplates retrieves a list of recent templates ordered by creation date.
+// When ownerID is set only templates of that user are returned.
+func (r *Repository) GetRecentTemplates(ctx context.Context, limit int, ownerID *uuid.UUID) ([]*dbModel.ServiceTemplate, error) {
+	query := r.db.DB().WithContext(ctx)
+	if ownerID != nil {
+		query = query.Where("user_id = ?", *ownerID)
+	}
+
 	var templates []*dbModel.ServiceTemplate
-	err := r.db.DB().WithContext(ctx).
+	err := query.
 		Preload("Endpoints").
 		Preload("DatabaseConfigs").
 		Preload("DockerConfigs").
 		Preload("AdvancedConfigs").
+		Preload("StatusHistory", orderStatusHistory).
 		Order("created_at DESC").
 		Limit(limit).
 		Find(&templates).Error
@@ -100,66 +131,464 @@ func (r *Repository) GetRecentTemplates(ctx context.Context, limit int) ([]*dbMo
 	return templates, nil
 }
 
-// UpdateZipUrl updates the zip url for a template identified by UUID.
-func (r *Repository) UpdateZipUrl(ctx context.Context, templateUUID uuid.UUID, newZipUrl string) error {
-	var template dbModel.ServiceTemplate
-	err := r.db.DB().WithContext(ctx).
-		Where("service_template_uuid = ?", templateUUID).
-		First(&template).Error
+// ListTemplates returns a page of templates using keyset pagination on (order column, primary key),
+// so that deep pages cost the same as the first one.
+func (r *Repository) ListTemplates(ctx context.Context, params database.TemplateListParams) (*database.TemplatePage, error) {
+	filtered := r.applyTemplateFilter(r.db.DB().WithContext(ctx).Model(&dbModel.ServiceTemplate{}), params.Filter)
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count templates: %w", err)
+	}
+
+	keyset, err := params.Keyset()
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("template not found: %w", err)
-		}
-		return fmt.Errorf("failed to find template: %w", err)
+		return nil, err
+	}
+	query := filtered.Session(&gorm.Session{})
+	if keyset.Where != "" {
+		query = query.Where(keyset.Where, keyset.Args...)
+	}
+
+	var templates []*dbModel.ServiceTemplate
+	err = query.
+		Preload("Endpoints").
+		Preload("DatabaseConfigs").
+		Preload("DockerConfigs").
+		Preload("AdvancedConfigs").
+		Preload("StatusHistory", orderStatusHistory).
+		Order(keyset.OrderBy).
+		// One extra row tells whether a next page exists
+		Limit(params.First + 1).
+		Find(&templates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	page := &database.TemplatePage{TotalCount: total}
+	if len(templates) > params.First {
+		page.HasNextPage = true
+		templates = templates[:params.First]
+	}
+	page.Templates = templates
+	return page, nil
+}
+
+// applyTemplateFilter adds the WHERE conditions of a template listing
+func (r *Repository) applyTemplateFilter(db *gorm.DB, filter database.TemplateListFilter) *gorm.DB {
+	if filter.OwnerID != nil {
+		db = db.Where("user_id = ?", *filter.OwnerID)
+	}
+	if filter.Status != nil {
+		// Rows created before the lifecycle was introduced keep lower-case statuses
+		status := database.NormalizeStatus(*filter.Status)
+		db = db.Where("status IN ?", []string{status, strings.ToLower(status)})
+	}
+	if filter.NameContains != nil && *filter.NameContains != "" {
+		db = db.Where("service_template_name ILIKE ?", "%"+escapeLike(*filter.NameContains)+"%")
+	}
+	if filter.Protocol != nil {
+		db = db.Where(fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %s.endpoint AS e
+			WHERE e.template_id = service_template.service_template_id AND e.protocol = ?)`, r.schemaName),
+			*filter.Protocol)
+	}
+	if filter.DatabaseType != nil {
+		db = db.Where(fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM %s.database_config AS d
+			WHERE d.template_id = service_template.service_template_id AND d.type = ?)`, r.schemaName),
+			*filter.DatabaseType)
+	}
+	if filter.CreatedAfter != nil {
+		db = db.Where("created_at >= ?", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		db = db.Where("created_at < ?", *filter.CreatedBefore)
+	}
+	return db
+}
+
+// escapeLike escapes LIKE wildcards so that user input is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// UpdateZipUrl updates the zip url for a template identified by UUID within the caller's transaction.
+func (r *Repository) UpdateZipUrl(ctx context.Context, templateUUID uuid.UUID, newZipUrl string, tx *orm.Transaction) error {
+	db := tx.Tx.WithContext(ctx)
+	template, err := findTemplateByUUID(db, templateUUID)
+	if err != nil {
+		return err
 	}
 
 	// Update the zip url
 	template.ZipURL = &newZipUrl
-	if err := r.db.DB().WithContext(ctx).Save(&template).Error; err != nil {
+	if err := db.Save(template).Error; err != nil {
 		return fmt.Errorf("failed to update zip URL: %w", err)
 	}
 
-	return nil
+	return notifyTemplateSaved(db, template)
+}
+
+// UpdateArchiveLocation stores where the publisher keeps the archive of a template identified by UUID
+// on the template and its current revision within the caller's transaction.
+// Download URLs are not stored: they expire and are requested from the publisher when the template is read.
+func (r *Repository) UpdateArchiveLocation(ctx context.Context, templateUUID uuid.UUID, bucket, object string, tx *orm.Transaction) error {
+	db := tx.Tx.WithContext(ctx)
+	template, err := findTemplateByUUID(db, templateUUID)
+	if err != nil {
+		return err
+	}
+
+	template.ArchiveBucket = &bucket
+	template.ArchiveObject = &object
+	if err := db.Save(template).Error; err != nil {
+		return fmt.Errorf("failed to update archive location: %w", err)
+	}
+
+	// У шаблонов, созданных до появления ревизий, строки ревизии может не быть
+	err = db.Model(&dbModel.TemplateRevision{}).
+		Where("template_id = ? AND revision = ?", *template.ServiceTemplateId, template.Revision).
+		Updates(map[string]any{"archive_bucket": bucket, "archive_object": object}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update revision archive location: %w", err)
+	}
+
+	return notifyTemplateSaved(db, template)
 }
 
-// UpdateTemplateStatusByUUID updates the status of a template identified by UUID.
-func (r *Repository) UpdateTemplateStatusByUUID(ctx context.Context, templateUUID uuid.UUID, newStatus string) error {
+// findTemplateByUUID loads a template without its configs for an update
+func findTemplateByUUID(db *gorm.DB, templateUUID uuid.UUID) (*dbModel.ServiceTemplate, error) {
 	var template dbModel.ServiceTemplate
-	err := r.db.DB().WithContext(ctx).
-		Where("service_template_uuid = ?", templateUUID).
+	err := db.Where("service_template_uuid = ?", templateUUID).
 		First(&template).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("template not found: %w", err)
+			return nil, fmt.Errorf("template not found: %w", err)
 		}
-		return fmt.Errorf("failed to find template: %w", err)
+		return nil, fmt.Errorf("failed to find template: %w", err)
 	}
+	return &template, nil
+}
 
-	template.Status = &newStatus
-	if err := r.db.DB().WithContext(ctx).Save(&template).Error; err != nil {
+// statusUpdateResult is the row returned by the conditional status UPDATE
+type statusUpdateResult struct {
+	ServiceTemplateId int
+	PreviousStatus    string
+	// RequestedAt is when the template last moved to PENDING, the start of the current generation
+	RequestedAt *time.Time
+}
+
+// UpdateTemplateStatusByUUID moves a template identified by UUID to a new status.
+// The change is applied with a single conditional UPDATE that only matches rows whose
+// current status may transition to newStatus; rejected changes return *database.StatusTransitionError.
+// Every applied transition is recorded in template_status_history within the caller's transaction.
+func (r *Repository) UpdateTemplateStatusByUUID(ctx context.Context, templateUUID uuid.UUID, newStatus string, source string, tx *orm.Transaction) error {
+	return r.updateTemplateStatus(ctx, tx, "service_template_uuid", templateUUID, newStatus, source)
+}
+
+// CancelTemplate moves a pending or processing template to CANCELLED within the caller's transaction.
+// Templates in any other status are left untouched and *database.StatusTransitionError is returned.
+func (r *Repository) CancelTemplate(ctx context.Context, templateID int, source string, tx *orm.Transaction) error {
+	return r.updateTemplateStatus(ctx, tx, "service_template_id", templateID, database.StatusCancelled, source)
+}
+
+// updateTemplateStatus applies a status transition to the template matched by keyColumn = key
+func (r *Repository) updateTemplateStatus(ctx context.Context, tx *orm.Transaction, keyColumn string, key any, newStatus string, source string) error {
+	db := tx.Tx.WithContext(ctx)
+	newStatus = database.NormalizeStatus(newStatus)
+
+	var updated []statusUpdateResult
+	query := fmt.Sprintf(`
+		UPDATE %[1]s.service_template AS t
+		SET status = ?, updated_at = NOW()
+		FROM (
+			SELECT service_template_id, status
+			FROM %[1]s.service_template
+			WHERE %[2]s = ?
+			FOR UPDATE
+		) AS prev
+		WHERE t.service_template_id = prev.service_template_id
+			AND UPPER(prev.status) IN ?
+		RETURNING t.service_template_id, prev.status AS previous_status,
+			(SELECT MAX(h.created_at) FROM %[1]s.template_status_history AS h
+			 WHERE h.template_id = t.service_template_id AND h.to_status = 'PENDING') AS requested_at`, r.schemaName, keyColumn)
+
+	err := db.Raw(query, newStatus, key, database.AllowedPreviousStatuses(newStatus)).
+		Scan(&updated).Error
+	if err != nil {
 		return fmt.Errorf("failed to update template status: %w", err)
 	}
 
-	return nil
+	if len(updated) == 0 {
+		var current dbModel.ServiceTemplate
+		err := db.Select("status").
+			Where(keyColumn+" = ?", key).
+			First(&current).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("template not found: %w", err)
+			}
+			return fmt.Errorf("failed to find template: %w", err)
+		}
+
+		from := ""
+		if current.Status != nil {
+			from = database.NormalizeStatus(*current.Status)
+		}
+		return &database.StatusTransitionError{From: from, To: newStatus}
+	}
+
+	previousStatus := database.NormalizeStatus(updated[0].PreviousStatus)
+	history := &dbModel.TemplateStatusHistory{
+		TemplateId: updated[0].ServiceTemplateId,
+		FromStatus: &previousStatus,
+		ToStatus:   &newStatus,
+		Source:     &source,
+	}
+	if err := db.Create(history).Error; err != nil {
+		return fmt.Errorf("failed to record template status history: %w", err)
+	}
+	recordStatusChange(tx, newStatus, updated[0].RequestedAt)
+
+	// Delivered by Postgres only when the transaction commits
+	return notifyTemplateChanged(db, updated[0].ServiceTemplateId, newStatus)
+}
+
+// templateDeletedStatus is sent to status subscribers when a template is deleted
+const templateDeletedStatus = "DELETED"
+
+// retryUpdateResult is the row returned by the conditional retry UPDATE
+type retryUpdateResult struct {
+	PreviousStatus string
+	// PreviousArchiveUuid is the archive ID of the abandoned attempt
+	PreviousArchiveUuid uuid.UUID
+	Revision            int
 }
 
-// UpdateTemplateErrorByUUID updates the error message of a template identified by UUID.
-func (r *Repository) UpdateTemplateErrorByUUID(ctx context.Context, templateUUID uuid.UUID, errorMessage string) error {
+// ResetTemplateForRetry moves a template in one of the from statuses back to PENDING within the caller's transaction,
+// clears its error, bumps the attempt counter and gives the new attempt its own archive ID, so late events
+// of the abandoned attempt no longer match the template. The template is returned with its configs as locked
+// by the reset; *database.StatusTransitionError is returned for templates in other statuses
+// and database.ErrMaxAttemptsReached once maxAttempts is used up.
+func (r *Repository) ResetTemplateForRetry(
+	ctx context.Context,
+	templateID int,
+	from []string,
+	maxAttempts int,
+	source string,
+	tx *orm.Transaction,
+) (*dbModel.ServiceTemplate, error) {
+	db := tx.Tx.WithContext(ctx)
+
+	archiveID := uuid.New()
+	var updated []retryUpdateResult
+	query := fmt.Sprintf(`
+		UPDATE %[1]s.service_template AS t
+		SET status = ?, error = NULL, attempts = prev.attempts + 1, archive_uuid = ?, updated_at = NOW()
+		FROM (
+			SELECT service_template_id, status, attempts, COALESCE(archive_uuid, service_template_uuid) AS archive_uuid
+			FROM %[1]s.service_template
+			WHERE service_template_id = ?
+			FOR UPDATE
+		) AS prev
+		WHERE t.service_template_id = prev.service_template_id
+			AND UPPER(prev.status) IN ?
+			AND prev.attempts < ?
+		RETURNING prev.status AS previous_status, prev.archive_uuid AS previous_archive_uuid, t.revision`, r.schemaName)
+
+	err := db.Raw(query, database.StatusPending, archiveID, templateID, from, maxAttempts).
+		Scan(&updated).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to reset template for retry: %w", err)
+	}
+
+	if len(updated) == 0 {
+		var current dbModel.ServiceTemplate
+		err := db.Select("status", "attempts").
+			Where("service_template_id = ?", templateID).
+			First(&current).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("template not found: %w", err)
+			}
+			return nil, fmt.Errorf("failed to find template: %w", err)
+		}
+
+		status := ""
+		if current.Status != nil {
+			status = database.NormalizeStatus(*current.Status)
+		}
+		if !slices.Contains(from, status) {
+			return nil, &database.StatusTransitionError{From: status, To: database.StatusPending}
+		}
+		return nil, fmt.Errorf("%w (%d of %d)", database.ErrMaxAttemptsReached, current.Attempts, maxAttempts)
+	}
+
+	// Архив прежней попытки остается известным, чтобы ее поздние события не считались чужими
+	err = db.Exec(fmt.Sprintf(`
+		INSERT INTO %s.template_attempt_archive (archive_uuid, template_id, revision)
+		VALUES (?, ?, ?)`, r.schemaName),
+		updated[0].PreviousArchiveUuid, templateID, updated[0].Revision).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to record template attempt archive: %w", err)
+	}
+
+	// У шаблонов, созданных до появления ревизий, строки ревизии может не быть
+	err = db.Model(&dbModel.TemplateRevision{}).
+		Where("template_id = ? AND revision = ?", templateID, updated[0].Revision).
+		Update("archive_uuid", archiveID).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to update revision archive ID: %w", err)
+	}
+
+	previousStatus := database.NormalizeStatus(updated[0].PreviousStatus)
+	pending := database.StatusPending
+	history := &dbModel.TemplateStatusHistory{
+		TemplateId: templateID,
+		FromStatus: &previousStatus,
+		ToStatus:   &pending,
+		Source:     &source,
+	}
+	if err := db.Create(history).Error; err != nil {
+		return nil, fmt.Errorf("failed to record template status history: %w", err)
+	}
+	recordStatusChange(tx, pending, nil)
+
+	if err := notifyTemplateChanged(db, templateID, pending); err != nil {
+		return nil, err
+	}
+
+	// Событие для генератора строится из строки, заблокированной этой транзакцией
 	var template dbModel.ServiceTemplate
-	err := r.db.DB().WithContext(ctx).
-		Where("service_template_uuid = ?", templateUUID).
+	err = db.Preload("Endpoints").
+		Preload("DatabaseConfigs").
+		Preload("DockerConfigs").
+		Preload("AdvancedConfigs").
+		Where("service_template_id = ?", templateID).
 		First(&template).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+	return &template, nil
+}
+
+// DeleteTemplate removes a template and all of its configs, revisions and status history within the caller's transaction
+// and returns the archive IDs of its revisions and generation attempts. Templates the generator may still be working on are kept
+// and database.ErrTemplateActive is returned.
+func (r *Repository) DeleteTemplate(ctx context.Context, templateID int, tx *orm.Transaction) ([]uuid.UUID, error) {
+	db := tx.Tx.WithContext(ctx)
+
+	var current dbModel.ServiceTemplate
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("service_template_id", "service_template_uuid", "archive_uuid", "status").
+		Where("service_template_id = ?", templateID).
+		First(&current).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("template not found: %w", err)
+			return nil, fmt.Errorf("template not found: %w", err)
 		}
-		return fmt.Errorf("failed to find template: %w", err)
+		return nil, fmt.Errorf("failed to find template: %w", err)
+	}
+
+	status := ""
+	if current.Status != nil {
+		status = database.NormalizeStatus(*current.Status)
+	}
+	if slices.Contains(database.ActiveStatuses, status) {
+		return nil, fmt.Errorf("%w (status %s)", database.ErrTemplateActive, status)
+	}
+
+	// Шаблоны без ревизий хранят архив под своим UUID
+	var archiveIDs []uuid.UUID
+	err = db.Model(&dbModel.TemplateRevision{}).
+		Where("template_id = ?", templateID).
+		Order("revision ASC").
+		Pluck("archive_uuid", &archiveIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template revisions: %w", err)
+	}
+	currentArchiveID := *current.ServiceTemplateUuid
+	if current.ArchiveUuid != nil {
+		currentArchiveID = *current.ArchiveUuid
+	}
+	if !slices.Contains(archiveIDs, currentArchiveID) {
+		archiveIDs = append(archiveIDs, currentArchiveID)
+	}
+
+	// Прежние попытки могли успеть загрузить архив до того, как их сменила повторная
+	var attemptArchiveIDs []uuid.UUID
+	err = db.Raw(fmt.Sprintf(`
+		DELETE FROM %s.template_attempt_archive
+		WHERE template_id = ?
+		RETURNING archive_uuid`, r.schemaName), templateID).
+		Scan(&attemptArchiveIDs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete template attempt archives: %w", err)
+	}
+	archiveIDs = append(archiveIDs, attemptArchiveIDs...)
+
+	// Child rows are removed explicitly, so the deletion does not depend on ON DELETE CASCADE
+	// being present in databases created before the constraints were declared
+	children := []any{
+		&dbModel.Endpoint{},
+		&dbModel.DatabaseConfig{},
+		&dbModel.DockerConfig{},
+		&dbModel.AdvancedConfig{},
+		&dbModel.TemplateStatusHistory{},
+		&dbModel.TemplateRevision{},
+	}
+	for _, child := range children {
+		if err := db.Where("template_id = ?", templateID).Delete(child).Error; err != nil {
+			return nil, fmt.Errorf("failed to delete template %T rows: %w", child, err)
+		}
+	}
+
+	if err := db.Where("service_template_id = ?", templateID).Delete(&dbModel.ServiceTemplate{}).Error; err != nil {
+		return nil, fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	// Subscribers re-read the template, find it missing and finish
+	if err := notifyTemplateChanged(db, templateID, templateDeletedStatus); err != nil {
+		return nil, err
+	}
+	return archiveIDs, nil
+}
+
+// UpdateTemplateErrorByUUID updates the error message of a template identified by UUID within the caller's transaction.
+func (r *Repository) UpdateTemplateErrorByUUID(ctx context.Context, templateUUID uuid.UUID, errorMessage string, tx *orm.Transaction) error {
+	db := tx.Tx.WithContext(ctx)
+	template, err := findTemplateByUUID(db, templateUUID)
+	if err != nil {
+		return err
 	}
 
 	template.Error = &errorMessage
-	if err := r.db.DB().WithContext(ctx).Save(&template).Error; err != nil {
+	if err := db.Save(template).Error; err != nil {
 		return fmt.Errorf("failed to update template error message: %w", err)
 	}
 
+	return notifyTemplateSaved(db, template)
+}
+
+// notifyTemplateChanged publishes a template change to database.TemplateChangedChannel
+func notifyTemplateChanged(db *gorm.DB, templateID int, status string) error {
+	payload, err := json.Marshal(database.TemplateChange{TemplateId: templateID, Status: status})
+	if err != nil {
+		return fmt.Errorf("failed to encode template change: %w", err)
+	}
+
+	if err := db.Exec("SELECT pg_notify(?, ?)", database.TemplateChangedChannel, string(payload)).Error; err != nil {
+		return fmt.Errorf("failed to notify template change: %w", err)
+	}
 	return nil
 }
+
+// notifyTemplateSaved notifies about a change of the template saved in the same transaction
+func notifyTemplateSaved(db *gorm.DB, template *dbModel.ServiceTemplate) error {
+	status := ""
+	if template.Status != nil {
+		status = database.NormalizeStatus(*template.Status)
+	}
+	return notifyTemplateChanged(db, *template.ServiceTemplateId, status)
+}
@@ -0,0 +1,245 @@
+package request_repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"github.com/google/uuid"
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReviseTemplate replaces the configs of a finished template, stores the new revision and sends
+// the template back to PENDING within the caller's transaction. The archive of the previous revision
+// stays on its revision row. Templates the generator may still be working on are kept
+// and database.ErrTemplateActive is returned; database.ErrRevisionConflict is returned
+// when the template is no longer at change.BaseRevision.
+func (r *Repository) ReviseTemplate(ctx context.Context, change database.TemplateRevisionChange, tx *orm.Transaction) error {
+	db := tx.Tx.WithContext(ctx)
+
+	var current dbModel.ServiceTemplate
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("service_template_id", "status", "revision").
+		Where("service_template_id = ?", change.TemplateID).
+		First(&current).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("template not found: %w", err)
+		}
+		return fmt.Errorf("failed to find template: %w", err)
+	}
+
+	previousStatus := ""
+	if current.Status != nil {
+		previousStatus = database.NormalizeStatus(*current.Status)
+	}
+	if slices.Contains(database.ActiveStatuses, previousStatus) {
+		return fmt.Errorf("%w (status %s)", database.ErrTemplateActive, previousStatus)
+	}
+	if current.Revision != change.BaseRevision {
+		return fmt.Errorf("%w: revision %d, expected %d", database.ErrRevisionConflict, current.Revision, change.BaseRevision)
+	}
+
+	// Снимок исходной ревизии сохраняется один раз; у новых шаблонов он уже есть
+	if change.Initial != nil {
+		change.Initial.TemplateId = change.TemplateID
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(change.Initial).Error; err != nil {
+			return fmt.Errorf("failed to save initial template revision: %w", err)
+		}
+	}
+
+	if err := replaceTemplateConfigs(db, change.TemplateID, change.Template); err != nil {
+		return err
+	}
+
+	pending := database.StatusPending
+	revision := change.Revision
+	revision.TemplateId = change.TemplateID
+	updates := map[string]any{
+		"service_template_name": change.Template.ServiceTemplateName,
+		"revision":              revision.Revision,
+		"version":               strconv.Itoa(revision.Revision),
+		"archive_uuid":          revision.ArchiveUuid,
+		"status":                pending,
+		"error":                 nil,
+		"attempts":              1,
+		"zip_url":               "",
+		"archive_bucket":        nil,
+		"archive_object":        nil,
+		"updated_at":            time.Now(),
+	}
+	err = db.Model(&dbModel.ServiceTemplate{}).
+		Where("service_template_id = ?", change.TemplateID).
+		Updates(updates).Error
+	if err != nil {
+		return fmt.Errorf("failed to update template: %w", err)
+	}
+
+	if err := db.Create(revision).Error; err != nil {
+		return fmt.Errorf("failed to save template revision: %w", err)
+	}
+
+	history := &dbModel.TemplateStatusHistory{
+		TemplateId: change.TemplateID,
+		FromStatus: &previousStatus,
+		ToStatus:   &pending,
+		Source:     &change.Source,
+	}
+	if err := db.Create(history).Error; err != nil {
+		return fmt.Errorf("failed to record template status history: %w", err)
+	}
+	recordStatusChange(tx, pending, nil)
+
+	return notifyTemplateChanged(db, change.TemplateID, pending)
+}
+
+// replaceTemplateConfigs swaps endpoint, database, Docker and advanced configs of the template
+func replaceTemplateConfigs(db *gorm.DB, templateID int, template *dbModel.ServiceTemplate) error {
+	children := []any{
+		&dbModel.Endpoint{},
+		&dbModel.DatabaseConfig{},
+		&dbModel.DockerConfig{},
+		&dbModel.AdvancedConfig{},
+	}
+	for _, child := range children {
+		if err := db.Where("template_id = ?", templateID).Delete(child).Error; err != nil {
+			return fmt.Errorf("failed to delete template %T rows: %w", child, err)
+		}
+	}
+
+	for _, endpoint := range template.Endpoints {
+		endpoint.TemplateId = templateID
+	}
+	for _, config := range template.DatabaseConfigs {
+		config.TemplateId = templateID
+	}
+	for _, config := range template.DockerConfigs {
+		config.TemplateId = templateID
+	}
+	for _, config := range template.AdvancedConfigs {
+		config.TemplateId = templateID
+	}
+
+	if err := createConfigs(db, template.Endpoints); err != nil {
+		return err
+	}
+	if err := createConfigs(db, template.DatabaseConfigs); err != nil {
+		return err
+	}
+	if err := createConfigs(db, template.DockerConfigs); err != nil {
+		return err
+	}
+	return createConfigs(db, template.AdvancedConfigs)
+}
+
+// createConfigs inserts config rows of one kind, skipping empty slices
+func createConfigs[T any](db *gorm.DB, rows []*T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := db.Create(rows).Error; err != nil {
+		return fmt.Errorf("failed to save template %T rows: %w", rows[0], err)
+	}
+	return nil
+}
+
+// ListTemplateRevisions returns the stored revisions of a template, oldest first
+func (r *Repository) ListTemplateRevisions(ctx context.Context, templateID int) ([]*dbModel.TemplateRevision, error) {
+	var revisions []*dbModel.TemplateRevision
+	err := r.db.DB().WithContext(ctx).
+		Where("template_id = ?", templateID).
+		Order("revision ASC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list template revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// ResolveArchiveID finds the template revision an archive ID belongs to.
+// Templates without revisions use their own UUID as the archive ID until they are retried.
+func (r *Repository) ResolveArchiveID(ctx context.Context, archiveID uuid.UUID) (*database.ArchiveRef, error) {
+	db := r.db.DB().WithContext(ctx)
+
+	var template dbModel.ServiceTemplate
+	err := db.Select("service_template_uuid", "revision").
+		Where("archive_uuid = ? OR (archive_uuid IS NULL AND service_template_uuid = ?)", archiveID, archiveID).
+		First(&template).Error
+	if err == nil {
+		return &database.ArchiveRef{
+			TemplateUUID: *template.ServiceTemplateUuid,
+			Revision:     template.Revision,
+			Current:      true,
+		}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find template: %w", err)
+	}
+
+	var revision dbModel.TemplateRevision
+	err = db.Preload("Template", func(db *gorm.DB) *gorm.DB {
+		return db.Select("service_template_id", "service_template_uuid")
+	}).
+		Where("archive_uuid = ?", archiveID).
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.resolveAttemptArchiveID(db, archiveID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template revision: %w", err)
+	}
+	if revision.Template == nil {
+		return nil, fmt.Errorf("template not found: %w", gorm.ErrRecordNotFound)
+	}
+
+	return &database.ArchiveRef{
+		TemplateUUID: *revision.Template.ServiceTemplateUuid,
+		Revision:     revision.Revision,
+		Current:      false,
+	}, nil
+}
+
+// resolveAttemptArchiveID finds the template an archive ID of an abandoned generation attempt belongs to
+func (r *Repository) resolveAttemptArchiveID(db *gorm.DB, archiveID uuid.UUID) (*database.ArchiveRef, error) {
+	var refs []database.ArchiveRef
+	err := db.Raw(fmt.Sprintf(`
+		SELECT t.service_template_uuid AS template_uuid, a.revision
+		FROM %[1]s.template_attempt_archive AS a
+		JOIN %[1]s.service_template AS t ON t.service_template_id = a.template_id
+		WHERE a.archive_uuid = ?`, r.schemaName), archiveID).
+		Scan(&refs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find template attempt archive: %w", err)
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("template not found: %w", gorm.ErrRecordNotFound)
+	}
+
+	ref := refs[0]
+	ref.Superseded = true
+	return &ref, nil
+}
+
+// UpdateRevisionArchiveLocation stores the archive location of the revision with the given archive ID
+// without touching the template, within the caller's transaction
+func (r *Repository) UpdateRevisionArchiveLocation(ctx context.Context, archiveID uuid.UUID, bucket, object string, tx *orm.Transaction) error {
+	result := tx.Tx.WithContext(ctx).
+		Model(&dbModel.TemplateRevision{}).
+		Where("archive_uuid = ?", archiveID).
+		Updates(map[string]any{"archive_bucket": bucket, "archive_object": object})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update revision archive location: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("template revision not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
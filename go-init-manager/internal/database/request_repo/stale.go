@@ -0,0 +1,92 @@
+package request_repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+
+	orm "gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gorm.io/gorm/clause"
+)
+
+// staleTemplatesLockKey is the advisory lock held by the replica sweeping stale templates
+const staleTemplatesLockKey = 7_016_519_036_415_855
+
+// TryLockStaleTemplates takes the stale template sweep lock until the end of the caller's transaction.
+// It returns false without waiting when another replica is sweeping.
+func (r *Repository) TryLockStaleTemplates(ctx context.Context, tx *orm.Transaction) (bool, error) {
+	var locked bool
+	err := tx.Tx.WithContext(ctx).Raw("SELECT pg_try_advisory_xact_lock(?)", staleTemplatesLockKey).Scan(&locked).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire stale templates lock: %w", err)
+	}
+	return locked, nil
+}
+
+// LockStaleTemplates locks up to limit templates that have not changed for longer than the timeout
+// of their status, oldest first, and loads their configs. Rows locked by status updates in flight are skipped.
+func (r *Repository) LockStaleTemplates(
+	ctx context.Context,
+	timeouts database.StaleTemplateTimeouts,
+	limit int,
+	tx *orm.Transaction,
+) ([]*dbModel.ServiceTemplate, error) {
+	if len(timeouts) == 0 {
+		return nil, nil
+	}
+
+	db := tx.Tx.WithContext(ctx)
+	conditions := make([]string, 0, len(timeouts))
+	args := make([]any, 0, 2*len(timeouts))
+	for status, timeout := range timeouts {
+		// Время сравнивается по часам Postgres, чтобы расхождение часов реплик не влияло на таймаут
+		conditions = append(conditions, "(UPPER(status) = ? AND updated_at < NOW() - make_interval(secs => ?))")
+		args = append(args, database.NormalizeStatus(status), timeout.Seconds())
+	}
+
+	var ids []int
+	err := db.Model(&dbModel.ServiceTemplate{}).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where(strings.Join(conditions, " OR "), args...).
+		Order("updated_at ASC").
+		Limit(limit).
+		Pluck("service_template_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock stale templates: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var templates []*dbModel.ServiceTemplate
+	err = db.Preload("Endpoints").
+		Preload("DatabaseConfigs").
+		Preload("DockerConfigs").
+		Preload("AdvancedConfigs").
+		Where("service_template_id IN ?", ids).
+		Order("updated_at ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stale templates: %w", err)
+	}
+	return templates, nil
+}
+
+// FailTemplate moves a pending or processing template to FAILED with the given error within the caller's transaction.
+// Templates in any other status are left untouched and *database.StatusTransitionError is returned.
+func (r *Repository) FailTemplate(ctx context.Context, templateID int, message string, source string, tx *orm.Transaction) error {
+	if err := r.updateTemplateStatus(ctx, tx, "service_template_id", templateID, database.StatusFailed, source); err != nil {
+		return err
+	}
+
+	err := tx.Tx.WithContext(ctx).Model(&dbModel.ServiceTemplate{}).
+		Where("service_template_id = ?", templateID).
+		Update("error", message).Error
+	if err != nil {
+		return fmt.Errorf("failed to update template error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package request_repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-init/internal/database"
+
+	"gorm.io/gorm"
+)
+
+// GetTemplateStats computes the statistics with one aggregate query per figure.
+// The queries run in one read-only REPEATABLE READ transaction so that all figures describe the same snapshot.
+func (r *Repository) GetTemplateStats(ctx context.Context, params database.TemplateStatsParams) (*database.TemplateStats, error) {
+	scope, args := r.templateStatsScope(params)
+	stats := &database.TemplateStats{StatusCounts: make(map[string]int64)}
+
+	err := r.db.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var statuses []struct {
+			Status    string
+			Templates int64
+		}
+		// Rows created before the lifecycle was introduced keep lower-case statuses
+		err := tx.Raw(fmt.Sprintf(`
+			SELECT UPPER(COALESCE(t.status, ?)) AS status, COUNT(*) AS templates
+			FROM (%s) AS t
+			GROUP BY 1`, scope), slices.Concat([]any{database.StatusPending}, args)...).
+			Scan(&statuses).Error
+		if err != nil {
+			return fmt.Errorf("failed to count templates by status: %w", err)
+		}
+		for _, row := range statuses {
+			stats.StatusCounts[row.Status] = row.Templates
+		}
+
+		// Шаблон без базы данных считается с типом NONE, шаблоны без endpoint'ов не учитываются
+		err = tx.Raw(fmt.Sprintf(`
+			SELECT e.protocol, e.role, COALESCE(d.type, ?) AS database_type, COUNT(DISTINCT t.service_template_id) AS templates
+			FROM (%s) AS t
+			JOIN %s.endpoint AS e ON e.template_id = t.service_template_id
+			LEFT JOIN %s.database_config AS d ON d.template_id = t.service_template_id
+			GROUP BY 1, 2, 3
+			ORDER BY templates DESC, 1, 2, 3
+			LIMIT ?`, scope, r.schemaName, r.schemaName),
+			slices.Concat([]any{"NONE"}, args, []any{params.Top})...).
+			Scan(&stats.EndpointDatabase).Error
+		if err != nil {
+			return fmt.Errorf("failed to count endpoint and database combinations: %w", err)
+		}
+
+		err = tx.Raw(fmt.Sprintf(`
+			SELECT NULLIF(dc.registry, '') AS registry, COUNT(DISTINCT t.service_template_id) AS templates
+			FROM (%s) AS t
+			JOIN %s.docker_config AS dc ON dc.template_id = t.service_template_id
+			GROUP BY 1
+			ORDER BY templates DESC, 1 NULLS FIRST
+			LIMIT ?`, scope, r.schemaName), slices.Concat(args, []any{params.Top})...).
+			Scan(&stats.DockerRegistries).Error
+		if err != nil {
+			return fmt.Errorf("failed to count docker registries: %w", err)
+		}
+
+		// Генерация начинается с последнего перехода в PENDING перед COMPLETED: повторы и ревизии считаются отдельно
+		var completion struct {
+			Completed  int64
+			AvgSeconds sql.NullFloat64
+			P95Seconds sql.NullFloat64
+		}
+		err = tx.Raw(fmt.Sprintf(`
+			SELECT COUNT(*) AS completed,
+				AVG(EXTRACT(EPOCH FROM c.created_at - p.created_at))::float8 AS avg_seconds,
+				percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM c.created_at - p.created_at)::float8) AS p95_seconds
+			FROM (%s) AS t
+			JOIN %s.template_status_history AS c
+				ON c.template_id = t.service_template_id AND c.to_status = ?
+			CROSS JOIN LATERAL (
+				SELECT h.created_at
+				FROM %s.template_status_history AS h
+				WHERE h.template_id = c.template_id AND h.to_status = ? AND h.created_at <= c.created_at
+				ORDER BY h.created_at DESC
+				LIMIT 1
+			) AS p`, scope, r.schemaName, r.schemaName),
+			slices.Concat(args, []any{database.StatusCompleted, database.StatusPending})...).
+			Scan(&completion).Error
+		if err != nil {
+			return fmt.Errorf("failed to compute completion durations: %w", err)
+		}
+		if completion.Completed > 0 && completion.AvgSeconds.Valid && completion.P95Seconds.Valid {
+			stats.Completion = &database.CompletionDurations{
+				Average: secondsToDuration(completion.AvgSeconds.Float64),
+				P95:     secondsToDuration(completion.P95Seconds.Float64),
+			}
+		}
+
+		err = tx.Raw(fmt.Sprintf(`
+			SELECT t.error AS message, COUNT(*) AS templates
+			FROM (%s) AS t
+			WHERE UPPER(t.status) = ? AND COALESCE(t.error, '') <> ''
+			GROUP BY 1
+			ORDER BY templates DESC, 1
+			LIMIT ?`, scope), slices.Concat(args, []any{database.StatusFailed, params.Top})...).
+			Scan(&stats.TopErrors).Error
+		if err != nil {
+			return fmt.Errorf("failed to count template errors: %w", err)
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// templateStatsScope returns the subquery selecting the templates of the statistics and its arguments
+func (r *Repository) templateStatsScope(params database.TemplateStatsParams) (string, []any) {
+	conditions := []string{"TRUE"}
+	var args []any
+	if params.OwnerID != nil {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, *params.OwnerID)
+	}
+	if params.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *params.CreatedAfter)
+	}
+	if params.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *params.CreatedBefore)
+	}
+	return fmt.Sprintf("SELECT service_template_id, status, error FROM %s.service_template WHERE %s",
+		r.schemaName, strings.Join(conditions, " AND ")), args
+}
+
+// secondsToDuration converts fractional seconds returned by EXTRACT(EPOCH ...) to a duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
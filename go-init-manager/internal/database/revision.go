@@ -0,0 +1,38 @@
+package database
+
+import (
+	"errors"
+
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrRevisionConflict is returned when a template got a new revision while a change was being prepared
+var ErrRevisionConflict = errors.New("template was changed concurrently")
+
+// ArchiveRef links an archive ID used by the generator and publisher to the template revision it belongs to
+type ArchiveRef struct {
+	TemplateUUID uuid.UUID
+	Revision     int
+	// Current is false for archives of earlier revisions and attempts; their events do not change the template
+	Current bool
+	// Superseded is true for archives of earlier generation attempts of the revision: every retry
+	// generates under a new archive ID, and archives of abandoned attempts are not kept
+	Superseded bool
+}
+
+// TemplateRevisionChange describes a new revision of a template
+type TemplateRevisionChange struct {
+	TemplateID int
+	// BaseRevision is the revision the change was made from
+	BaseRevision int
+	// Template carries the new name and configs of the template
+	Template *dbModel.ServiceTemplate
+	// Revision is the snapshot stored for the new revision
+	Revision *dbModel.TemplateRevision
+	// Initial is the snapshot of BaseRevision; it is stored only if the template has no revision rows yet
+	// (templates created before revisions were recorded)
+	Initial *dbModel.TemplateRevision
+	Source  string
+}
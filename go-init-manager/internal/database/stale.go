@@ -0,0 +1,7 @@
+package database
+
+import "time"
+
+// StaleTemplateTimeouts maps an active status to how long a template may stay in it without any change
+// before the reaper considers it stuck; statuses missing from the map are never reaped
+type StaleTemplateTimeouts map[string]time.Duration
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Template status values stored in service_template.status
+const (
+	StatusPending    = "PENDING"
+	StatusProcessing = "PROCESSING"
+	StatusCompleted  = "COMPLETED"
+	StatusFailed     = "FAILED"
+	StatusCancelled  = "CANCELLED"
+)
+
+var (
+	// ErrInvalidStatusTransition is returned when a status change is not allowed by the lifecycle
+	ErrInvalidStatusTransition = errors.New("invalid template status transition")
+	// ErrMaxAttemptsReached is returned when a template has used up its generation attempts
+	ErrMaxAttemptsReached = errors.New("maximum number of generation attempts reached")
+	// ErrTemplateActive is returned when a template that is still being generated is deleted
+	ErrTemplateActive = errors.New("template generation is in progress")
+)
+
+// ActiveStatuses are the statuses of templates the generator may still be working on
+var ActiveStatuses = []string{StatusPending, StatusProcessing}
+
+// RetryableStatuses are the statuses a user can send a template back to PENDING from.
+// Templates stuck in ActiveStatuses are re-enqueued only by the reaper, once their timeout has passed.
+var RetryableStatuses = []string{StatusFailed, StatusCancelled}
+
+// statusTransitions lists, for every target status, the statuses it may be reached from.
+// CANCELLED is final for generator events: late generation-started, archive-ready
+// or generation-failed events of a cancelled template are rejected.
+var statusTransitions = map[string][]string{
+	StatusPending:    {},
+	StatusProcessing: {StatusPending},
+	StatusCompleted:  {StatusPending, StatusProcessing},
+	StatusFailed:     {StatusPending, StatusProcessing},
+	StatusCancelled:  {StatusPending, StatusProcessing},
+}
+
+// StatusTransitionError describes a rejected status change
+type StatusTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidStatusTransition, e.From, e.To)
+}
+
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrInvalidStatusTransition
+}
+
+// NormalizeStatus converts legacy lower-case statuses to the canonical form
+func NormalizeStatus(status string) string {
+	return strings.ToUpper(strings.TrimSpace(status))
+}
+
+// AllowedPreviousStatuses returns statuses from which the template may move to the given status
+func AllowedPreviousStatuses(to string) []string {
+	return statusTransitions[NormalizeStatus(to)]
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// TemplateChangedChannel is the Postgres NOTIFY channel the repository publishes template changes to
+const TemplateChangedChannel = "go_init_template_changed"
+
+// listenerReconnectDelay is the pause before re-establishing a broken LISTEN connection
+const listenerReconnectDelay = 2 * time.Second
+
+// TemplateChange is the NOTIFY payload sent whenever status, archive URL or error of a template changes
+type TemplateChange struct {
+	TemplateId int    `json:"templateId"`
+	Status     string `json:"status"`
+}
+
+// StatusListener holds a dedicated LISTEN connection and fans out template changes
+// to local subscribers. Every manager replica runs its own listener, so a change committed
+// by any replica reaches subscribers connected to all of them.
+type StatusListener struct {
+	log *logger.Logger
+	db  *orm.AgentImpl
+
+	mu          sync.RWMutex
+	subscribers map[int]map[chan TemplateChange]struct{}
+}
+
+// NewStatusListener creates a listener on top of the shared connection pool
+func NewStatusListener(db *orm.AgentImpl, log *logger.Logger) *StatusListener {
+	return &StatusListener{
+		log:         log,
+		db:          db,
+		subscribers: make(map[int]map[chan TemplateChange]struct{}),
+	}
+}
+
+// Subscribe registers interest in a template. The returned channel is buffered and coalesces
+// bursts of changes, so subscribers should re-read the template on every signal.
+// The returned function must be called to release the subscription.
+func (l *StatusListener) Subscribe(templateId int) (<-chan TemplateChange, func()) {
+	ch := make(chan TemplateChange, 1)
+
+	l.mu.Lock()
+	if l.subscribers[templateId] == nil {
+		l.subscribers[templateId] = make(map[chan TemplateChange]struct{})
+	}
+	l.subscribers[templateId][ch] = struct{}{}
+	l.mu.Unlock()
+
+	return ch, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.subscribers[templateId], ch)
+		if len(l.subscribers[templateId]) == 0 {
+			delete(l.subscribers, templateId)
+		}
+	}
+}
+
+// Run listens for notifications until ctx is cancelled, reconnecting after failures
+func (l *StatusListener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		l.log.Warn(fmt.Sprintf("Template status listener disconnected, reconnecting in %s: %v", listenerReconnectDelay, err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenerReconnectDelay):
+		}
+	}
+}
+
+// listen takes a connection out of the pool and blocks on it while waiting for notifications
+func (l *StatusListener) listen(ctx context.Context) error {
+	sqlDB, err := l.db.DB().DB()
+	if err != nil {
+		return fmt.Errorf("failed to get sql connection pool: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire listener connection: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected driver connection type %T", driverConn)
+		}
+		pgConn := stdConn.Conn()
+
+		// The connection is left in LISTEN state, so it must never go back to the pool
+		defer pgConn.Close(context.Background())
+
+		if _, err := pgConn.Exec(ctx, "LISTEN "+TemplateChangedChannel); err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", TemplateChangedChannel, err)
+		}
+		l.log.Info("Listening for template changes on channel " + TemplateChangedChannel)
+
+		// Changes committed while the listener was down are not replayed, so wake everyone up to re-read
+		l.broadcast()
+
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to wait for notification: %w", err)
+			}
+			l.dispatch(notification.Payload)
+		}
+	})
+}
+
+// dispatch delivers a NOTIFY payload to the subscribers of the affected template
+func (l *StatusListener) dispatch(payload string) {
+	var change TemplateChange
+	if err := json.Unmarshal([]byte(payload), &change); err != nil {
+		l.log.Warn(fmt.Sprintf("Skipping malformed template change notification %q: %v", payload, err))
+		return
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for ch := range l.subscribers[change.TemplateId] {
+		signal(ch, change)
+	}
+}
+
+// broadcast signals every subscriber without a concrete change
+func (l *StatusListener) broadcast() {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for templateId, subscribers := range l.subscribers {
+		for ch := range subscribers {
+			signal(ch, TemplateChange{TemplateId: templateId})
+		}
+	}
+}
+
+// signal performs a non-blocking send; a pending signal already tells the subscriber to re-read
+func signal(ch chan TemplateChange, change TemplateChange) {
+	select {
+	case ch <- change:
+	default:
+	}
+}
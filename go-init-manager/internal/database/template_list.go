@@ -0,0 +1,152 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"github.com/google/uuid"
+)
+
+// TemplateOrderField is a column templates can be listed by; the primary key is always the tie-breaker
+type TemplateOrderField string
+
+const (
+	TemplateOrderByCreatedAt TemplateOrderField = "created_at"
+	TemplateOrderByName      TemplateOrderField = "service_template_name"
+)
+
+// TemplateListFilter narrows a template listing; nil fields are ignored
+type TemplateListFilter struct {
+	// OwnerID restricts the listing to templates of one user
+	OwnerID       *uuid.UUID
+	Status        *string
+	NameContains  *string
+	Protocol      *string
+	DatabaseType  *string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// TemplateListParams describes one page of a keyset-paginated template listing
+type TemplateListParams struct {
+	Filter     TemplateListFilter
+	OrderBy    TemplateOrderField
+	Descending bool
+	// First is the page size
+	First int
+	// After is the cursor of the last template of the previous page
+	After *TemplateCursor
+}
+
+// TemplateKeyset is the ordering of a template listing and the condition selecting the rows after its cursor
+type TemplateKeyset struct {
+	// OrderBy is the ORDER BY clause, the primary key breaks ties
+	OrderBy string
+	// Where is the keyset condition on (order column, primary key); empty on the first page
+	Where string
+	Args  []any
+}
+
+// Keyset builds the ordering and the keyset condition of the page described by p
+func (p TemplateListParams) Keyset() (TemplateKeyset, error) {
+	orderBy := p.OrderBy
+	switch orderBy {
+	case "":
+		orderBy = TemplateOrderByCreatedAt
+	case TemplateOrderByCreatedAt, TemplateOrderByName:
+	default:
+		return TemplateKeyset{}, fmt.Errorf("unsupported template order %q", orderBy)
+	}
+	direction, comparison := "ASC", ">"
+	if p.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	keyset := TemplateKeyset{OrderBy: fmt.Sprintf("%s %s, service_template_id %s", orderBy, direction, direction)}
+	if p.After == nil {
+		return keyset, nil
+	}
+	if p.After.OrderBy != orderBy {
+		return TemplateKeyset{}, fmt.Errorf("cursor was issued for order %q, not %q", p.After.OrderBy, orderBy)
+	}
+	value, err := p.After.OrderValue()
+	if err != nil {
+		return TemplateKeyset{}, err
+	}
+	keyset.Where = fmt.Sprintf("(%s, service_template_id) %s (?, ?)", orderBy, comparison)
+	keyset.Args = []any{value, p.After.ID}
+	return keyset, nil
+}
+
+// TemplatePage is a page of templates in the requested order
+type TemplatePage struct {
+	Templates   []*dbModel.ServiceTemplate
+	HasNextPage bool
+	TotalCount  int64
+}
+
+// TemplateCursor points at a template within a listing ordered by OrderBy
+type TemplateCursor struct {
+	OrderBy TemplateOrderField `json:"o"`
+	Value   string             `json:"v"`
+	ID      int                `json:"id"`
+}
+
+// NewTemplateCursor builds the cursor of a template for the given ordering
+func NewTemplateCursor(template *dbModel.ServiceTemplate, orderBy TemplateOrderField) TemplateCursor {
+	cursor := TemplateCursor{OrderBy: orderBy, ID: *template.ServiceTemplateId}
+	switch orderBy {
+	case TemplateOrderByName:
+		if template.ServiceTemplateName != nil {
+			cursor.Value = *template.ServiceTemplateName
+		}
+	default:
+		if template.CreatedAt != nil {
+			cursor.Value = template.CreatedAt.Format(time.RFC3339Nano)
+		}
+	}
+	return cursor
+}
+
+// Encode returns the opaque string representation handed out to API clients
+func (c TemplateCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// OrderValue returns the cursor value typed for comparison with the order column
+func (c TemplateCursor) OrderValue() (any, error) {
+	if c.OrderBy == TemplateOrderByName {
+		return c.Value, nil
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor timestamp: %w", err)
+	}
+	return createdAt, nil
+}
+
+// DecodeTemplateCursor parses a cursor produced by TemplateCursor.Encode
+func DecodeTemplateCursor(encoded string) (*TemplateCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var cursor TemplateCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if cursor.OrderBy != TemplateOrderByCreatedAt && cursor.OrderBy != TemplateOrderByName {
+		return nil, fmt.Errorf("invalid cursor: unknown order %q", cursor.OrderBy)
+	}
+	if _, err := cursor.OrderValue(); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
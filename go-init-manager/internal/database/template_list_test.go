@@ -0,0 +1,196 @@
+package database
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	dbModel "go-init/internal/database/request_repo/models"
+)
+
+func testTemplate(id int, name string, createdAt time.Time) *dbModel.ServiceTemplate {
+	return &dbModel.ServiceTemplate{
+		ServiceTemplateId:   &id,
+		ServiceTemplateName: &name,
+		CreatedAt:           &createdAt,
+	}
+}
+
+func TestTemplateCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.FixedZone("MSK", 3*60*60))
+	template := testTemplate(42, "auth-service", createdAt)
+
+	testCases := []struct {
+		name    string
+		orderBy TemplateOrderField
+		value   any
+	}{
+		{name: "created_at", orderBy: TemplateOrderByCreatedAt, value: createdAt},
+		{name: "name", orderBy: TemplateOrderByName, value: "auth-service"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := NewTemplateCursor(template, tc.orderBy).Encode()
+			cursor, err := DecodeTemplateCursor(encoded)
+			if err != nil {
+				t.Fatalf("DecodeTemplateCursor(%q) failed: %v", encoded, err)
+			}
+			if cursor.OrderBy != tc.orderBy || cursor.ID != 42 {
+				t.Fatalf("Decoded cursor = %+v, want order %q and id 42", cursor, tc.orderBy)
+			}
+
+			value, err := cursor.OrderValue()
+			if err != nil {
+				t.Fatalf("OrderValue failed: %v", err)
+			}
+			// Timestamps keep nanoseconds, otherwise rows created in the same second would be skipped
+			if want, ok := tc.value.(time.Time); ok {
+				if got, ok := value.(time.Time); !ok || !got.Equal(want) {
+					t.Fatalf("OrderValue = %v, want %v", value, want)
+				}
+				return
+			}
+			if value != tc.value {
+				t.Fatalf("OrderValue = %v, want %v", value, tc.value)
+			}
+		})
+	}
+}
+
+func TestDecodeTemplateCursorInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	testCases := []struct {
+		name    string
+		encoded string
+		wantErr string
+	}{
+		{name: "not base64", encoded: "not a cursor!", wantErr: "invalid cursor"},
+		{name: "not json", encoded: encode("created_at|42"), wantErr: "invalid cursor"},
+		{name: "unknown order", encoded: encode(`{"o":"user_id","v":"x","id":1}`), wantErr: "unknown order"},
+		{name: "missing order", encoded: encode(`{"v":"2025-01-01T00:00:00Z","id":1}`), wantErr: "unknown order"},
+		{name: "invalid timestamp", encoded: encode(`{"o":"created_at","v":"yesterday","id":1}`), wantErr: "invalid cursor timestamp"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := DecodeTemplateCursor(tc.encoded)
+			if err == nil {
+				t.Fatalf("DecodeTemplateCursor(%q) = %+v, want error", tc.encoded, cursor)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("DecodeTemplateCursor(%q) error = %v, want it to contain %q", tc.encoded, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateListKeyset(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	template := testTemplate(7, "billing", createdAt)
+	byCreatedAt := NewTemplateCursor(template, TemplateOrderByCreatedAt)
+	byName := NewTemplateCursor(template, TemplateOrderByName)
+
+	testCases := []struct {
+		name   string
+		params TemplateListParams
+		want   TemplateKeyset
+	}{
+		{
+			name:   "default order first page",
+			params: TemplateListParams{},
+			want:   TemplateKeyset{OrderBy: "created_at ASC, service_template_id ASC"},
+		},
+		{
+			name:   "created_at ascending",
+			params: TemplateListParams{OrderBy: TemplateOrderByCreatedAt, After: &byCreatedAt},
+			want: TemplateKeyset{
+				OrderBy: "created_at ASC, service_template_id ASC",
+				Where:   "(created_at, service_template_id) > (?, ?)",
+				Args:    []any{createdAt, 7},
+			},
+		},
+		{
+			name:   "created_at descending",
+			params: TemplateListParams{OrderBy: TemplateOrderByCreatedAt, Descending: true, After: &byCreatedAt},
+			want: TemplateKeyset{
+				OrderBy: "created_at DESC, service_template_id DESC",
+				Where:   "(created_at, service_template_id) < (?, ?)",
+				Args:    []any{createdAt, 7},
+			},
+		},
+		{
+			name:   "name ascending",
+			params: TemplateListParams{OrderBy: TemplateOrderByName, After: &byName},
+			want: TemplateKeyset{
+				OrderBy: "service_template_name ASC, service_template_id ASC",
+				Where:   "(service_template_name, service_template_id) > (?, ?)",
+				Args:    []any{"billing", 7},
+			},
+		},
+		{
+			name:   "name descending",
+			params: TemplateListParams{OrderBy: TemplateOrderByName, Descending: true, After: &byName},
+			want: TemplateKeyset{
+				OrderBy: "service_template_name DESC, service_template_id DESC",
+				Where:   "(service_template_name, service_template_id) < (?, ?)",
+				Args:    []any{"billing", 7},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.params.Keyset()
+			if err != nil {
+				t.Fatalf("Keyset failed: %v", err)
+			}
+			if got.OrderBy != tc.want.OrderBy || got.Where != tc.want.Where {
+				t.Fatalf("Keyset = %q WHERE %q, want %q WHERE %q", got.OrderBy, got.Where, tc.want.OrderBy, tc.want.Where)
+			}
+			if len(got.Args) != len(tc.want.Args) {
+				t.Fatalf("Keyset args = %v, want %v", got.Args, tc.want.Args)
+			}
+			for i := range got.Args {
+				if want, ok := tc.want.Args[i].(time.Time); ok {
+					if value, ok := got.Args[i].(time.Time); !ok || !value.Equal(want) {
+						t.Fatalf("Keyset arg %d = %v, want %v", i, got.Args[i], want)
+					}
+					continue
+				}
+				if !reflect.DeepEqual(got.Args[i], tc.want.Args[i]) {
+					t.Fatalf("Keyset arg %d = %v, want %v", i, got.Args[i], tc.want.Args[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateListKeysetRejectsForeignCursor(t *testing.T) {
+	template := testTemplate(7, "billing", time.Now())
+	byName := NewTemplateCursor(template, TemplateOrderByName)
+	byCreatedAt := NewTemplateCursor(template, TemplateOrderByCreatedAt)
+
+	testCases := []struct {
+		name   string
+		params TemplateListParams
+	}{
+		{name: "name cursor on created_at order", params: TemplateListParams{OrderBy: TemplateOrderByCreatedAt, After: &byName}},
+		{name: "name cursor on default order", params: TemplateListParams{After: &byName}},
+		{name: "created_at cursor on name order", params: TemplateListParams{OrderBy: TemplateOrderByName, After: &byCreatedAt}},
+		{name: "unsupported order", params: TemplateListParams{OrderBy: "user_id"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if keyset, err := tc.params.Keyset(); err == nil {
+				t.Fatalf("Keyset = %+v, want error", keyset)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateStatsParams selects the templates aggregated by GetTemplateStats; nil fields are ignored
+type TemplateStatsParams struct {
+	// OwnerID restricts the statistics to templates of one user
+	OwnerID *uuid.UUID
+	// CreatedAfter and CreatedBefore bound the creation time of the templates, [CreatedAfter, CreatedBefore)
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Top limits the endpoint/database combinations, docker registries and error messages returned
+	Top int
+}
+
+// TemplateStats are aggregates over the templates selected by TemplateStatsParams
+type TemplateStats struct {
+	// StatusCounts maps canonical statuses to the number of templates currently in them
+	StatusCounts     map[string]int64
+	EndpointDatabase []EndpointDatabaseCount
+	DockerRegistries []DockerRegistryCount
+	// Completion is nil when no template of the selection has completed
+	Completion *CompletionDurations
+	TopErrors  []TemplateErrorCount
+}
+
+// EndpointDatabaseCount is the number of templates with an endpoint of the protocol and role and the database type
+type EndpointDatabaseCount struct {
+	Protocol     string
+	Role         string
+	DatabaseType string
+	Templates    int64
+}
+
+// DockerRegistryCount is the number of templates pushing images to the registry; nil is the default registry
+type DockerRegistryCount struct {
+	Registry  *string
+	Templates int64
+}
+
+// CompletionDurations describe the time from the last transition to PENDING to COMPLETED of completed generations
+type CompletionDurations struct {
+	Average time.Duration
+	P95     time.Duration
+}
+
+// TemplateErrorCount is the number of failed templates with the error message
+type TemplateErrorCount struct {
+	Message   string
+	Templates int64
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// afterCommit holds the hooks of the transactions run by WithTransaction
+var afterCommit = struct {
+	sync.Mutex
+	hooks map[*orm.Transaction][]func()
+}{hooks: make(map[*orm.Transaction][]func())}
+
+// WithTransaction runs fn in a transaction. Unlike deferring tx.Enfold on the returned error directly,
+// the error of fn survives a successful rollback and a failed commit is reported to the caller.
+// Hooks registered with AfterCommit run once the transaction has committed.
+func WithTransaction(ctx context.Context, agent *orm.AgentImpl, fn func(tx *orm.Transaction) error) (err error) {
+	tx, err := agent.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	afterCommit.Lock()
+	afterCommit.hooks[tx] = nil
+	afterCommit.Unlock()
+
+	defer func() {
+		afterCommit.Lock()
+		hooks := afterCommit.hooks[tx]
+		delete(afterCommit.hooks, tx)
+		afterCommit.Unlock()
+
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+
+		txErr := err
+		tx.Enfold(ctx, &txErr)
+		if err == nil && txErr != nil {
+			err = fmt.Errorf("failed to commit transaction: %w", txErr)
+		}
+		if err != nil {
+			return
+		}
+		for _, hook := range hooks {
+			hook()
+		}
+	}()
+
+	return fn(tx)
+}
+
+// AfterCommit runs fn once tx commits and drops it when tx rolls back, so side effects outside
+// the database, such as metrics, are not left behind by rolled back changes.
+// tx must be a transaction run by WithTransaction: for any other transaction the commit is not observed,
+// so AfterCommit panics instead of guessing whether the changes will be kept.
+func AfterCommit(tx *orm.Transaction, fn func()) {
+	afterCommit.Lock()
+	defer afterCommit.Unlock()
+
+	hooks, ok := afterCommit.hooks[tx]
+	if !ok {
+		panic("database.AfterCommit: transaction was not started by WithTransaction or has already finished")
+	}
+	afterCommit.hooks[tx] = append(hooks, fn)
+}
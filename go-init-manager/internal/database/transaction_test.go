@@ -0,0 +1,21 @@
+package database
+
+import (
+	"testing"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+func TestAfterCommitUnknownTransaction(t *testing.T) {
+	ran := false
+	defer func() {
+		if recover() == nil {
+			t.Fatal("AfterCommit accepted a transaction that was not started by WithTransaction")
+		}
+		if ran {
+			t.Fatal("Hook of an unknown transaction ran before its commit")
+		}
+	}()
+
+	AfterCommit(&orm.Transaction{}, func() { ran = true })
+}
@@ -0,0 +1,17 @@
+package deadletter
+
+import "time"
+
+// Config настройки обработки сообщений, которые потребитель не смог обработать
+type Config struct {
+	// Topic топик, в который перекладываются необработанные сообщения
+	Topic string `yaml:"topic" default:"go-init-done-dlq"`
+	// MaxAttempts сколько раз обрабатывается сообщение с временной ошибкой до отправки в Topic.
+	// Сообщения, которые невозможно разобрать, отправляются сразу
+	MaxAttempts int `yaml:"max_attempts" default:"5"`
+	// MinBackoff и MaxBackoff границы экспоненциальной задержки между попытками
+	MinBackoff time.Duration `yaml:"min_backoff" default:"1s"`
+	MaxBackoff time.Duration `yaml:"max_backoff" default:"30s"`
+	// PublishTimeout ожидание подтверждения от брокера
+	PublishTimeout time.Duration `yaml:"publish_timeout" default:"10s"`
+}
@@ -0,0 +1,182 @@
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-init/internal/backoff"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"gitlab.com/go-init/go-init-common/default/kafka"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// ErrUnprocessable marks messages that will fail on every attempt, e.g. malformed CloudEvents.
+// They are moved to the dead-letter topic without retries.
+var ErrUnprocessable = errors.New("unprocessable message")
+
+// Unprocessable wraps err so that the consumer does not retry the message
+func Unprocessable(err error) error {
+	return fmt.Errorf("%w: %w", ErrUnprocessable, err)
+}
+
+// Consumer reads a topic with a consumer group and hands messages to a worker one at a time.
+// A message the worker rejects with ErrUnprocessable, or keeps failing after Config.MaxAttempts,
+// is copied to the dead-letter topic with the reason and its original headers, and consumption continues.
+// Offsets are committed only after a message is processed or dead-lettered.
+type Consumer struct {
+	cfg        *Config
+	log        *logger.Logger
+	client     *kgo.Client
+	producer   *kgo.Client
+	worker     kafka.ConsumerWorker
+	autoCommit bool
+	// backoff delays retries of a failing message and of dead-letter publishing
+	backoff backoff.Exponential
+}
+
+// NewConsumer creates a consumer of topic in the group from kafkaCfg. It uses its own client so that
+// offsets are not committed for polled messages still being retried; dead letters are sent through producer.
+func NewConsumer(
+	cfg *Config,
+	log *logger.Logger,
+	kafkaCfg *kafka.Config,
+	topic string,
+	worker kafka.ConsumerWorker,
+	producer *kgo.Client,
+) (*Consumer, error) {
+	options := []kgo.Opt{
+		kgo.SeedBrokers(kafkaCfg.Address...),
+		kgo.ConsumerGroup(kafkaCfg.ConsumerConfig.GroupId),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	}
+	if !kafkaCfg.ConsumerConfig.AutoCommit {
+		options = append(options, kgo.DisableAutoCommit())
+	}
+
+	client, err := kgo.NewClient(options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka consumer client: %w", err)
+	}
+
+	return &Consumer{
+		cfg:        cfg,
+		log:        log,
+		client:     client,
+		producer:   producer,
+		worker:     worker,
+		autoCommit: kafkaCfg.ConsumerConfig.AutoCommit,
+		backoff:    backoff.Exponential{Min: cfg.MinBackoff, Max: cfg.MaxBackoff},
+	}, nil
+}
+
+// Run consumes messages until ctx is cancelled and then leaves the group.
+// A message interrupted by cancellation is not committed and is consumed again after restart.
+func (c *Consumer) Run(ctx context.Context) {
+	c.log.Info(fmt.Sprintf("Kafka consumer started, dead letters go to %s", c.cfg.Topic))
+	defer func() {
+		c.client.Close()
+		c.log.Info("Kafka consumer stopped")
+	}()
+
+	for {
+		fetches := c.client.PollFetches(ctx)
+		if ctx.Err() != nil || fetches.IsClientClosed() {
+			return
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			fetchErrors.Inc()
+			c.log.Error(fmt.Sprintf("Error polling fetches topic=%s, partition=%d: %v", topic, partition, err))
+		})
+		fetches.EachRecord(func(record *kgo.Record) {
+			if ctx.Err() == nil {
+				c.handle(ctx, record)
+			}
+		})
+	}
+}
+
+// handle processes one message, dead-letters it on failure and commits its offset
+func (c *Consumer) handle(ctx context.Context, record *kgo.Record) {
+	attempts, err := c.process(ctx, record)
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		reason := "retries_exhausted"
+		if errors.Is(err, ErrUnprocessable) {
+			reason = "unprocessable"
+		}
+		if err := c.publish(ctx, record, err, attempts); err != nil {
+			return
+		}
+		deadLettered.WithLabelValues(reason).Inc()
+		c.log.Warn(fmt.Sprintf("Message topic=%s, partition=%d, offset=%d moved to dead-letter topic %s after %d attempt(s): %v",
+			record.Topic, record.Partition, record.Offset, c.cfg.Topic, attempts, err))
+	}
+
+	if !c.autoCommit {
+		if err := c.client.CommitRecords(ctx, record); err != nil {
+			c.log.Error(fmt.Sprintf("Error committing record topic=%s, partition=%d, offset=%d: %v",
+				record.Topic, record.Partition, record.Offset, err))
+		}
+	}
+}
+
+// process runs the worker until it succeeds, the error is unprocessable or the attempts are exhausted
+func (c *Consumer) process(ctx context.Context, record *kgo.Record) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := c.work(ctx, record)
+		if err == nil || errors.Is(err, ErrUnprocessable) || attempt >= c.cfg.MaxAttempts {
+			return attempt, err
+		}
+
+		processingRetries.Inc()
+		delay := c.backoff.Delay(attempt)
+		c.log.Warn(fmt.Sprintf("Error processing record topic=%s, partition=%d, offset=%d (attempt %d of %d), retrying in %s: %v",
+			record.Topic, record.Partition, record.Offset, attempt, c.cfg.MaxAttempts, delay, err))
+		select {
+		case <-ctx.Done():
+			return attempt, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// work calls the worker; a panic is reported as an ordinary error and retried
+func (c *Consumer) work(ctx context.Context, record *kgo.Record) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while processing message: %v", r)
+		}
+	}()
+	return c.worker.Work(ctx, record.Value)
+}
+
+// publish copies the record to the dead-letter topic, retrying until it succeeds or ctx is cancelled:
+// the offset must not be committed before the message is stored somewhere
+func (c *Consumer) publish(ctx context.Context, record *kgo.Record, cause error, attempts int) error {
+	deadLetter := deadLetterRecord(c.cfg.Topic, record, cause, attempts, time.Now())
+	for attempt := 1; ; attempt++ {
+		publishCtx, cancel := context.WithTimeout(ctx, c.cfg.PublishTimeout)
+		err := c.producer.ProduceSync(publishCtx, deadLetter).FirstErr()
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		failedPublishes.Inc()
+		delay := c.backoff.Delay(attempt)
+		c.log.Error(fmt.Sprintf("Failed to publish record topic=%s, partition=%d, offset=%d to dead-letter topic %s, retrying in %s: %v",
+			record.Topic, record.Partition, record.Offset, c.cfg.Topic, delay, err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
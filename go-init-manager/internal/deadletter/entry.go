@@ -0,0 +1,140 @@
+package deadletter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Заголовки, которые добавляются к сообщению при отправке в dead-letter топик.
+// Исходные заголовки сообщения сохраняются как есть.
+const (
+	headerPrefix          = "dlq-"
+	HeaderReason          = headerPrefix + "reason"
+	HeaderSourceTopic     = headerPrefix + "source-topic"
+	HeaderSourcePartition = headerPrefix + "source-partition"
+	HeaderSourceOffset    = headerPrefix + "source-offset"
+	HeaderAttempts        = headerPrefix + "attempts"
+	HeaderFailedAt        = headerPrefix + "failed-at"
+	// HeaderReplayedFrom is set on replayed messages to the position of the dead-letter entry
+	HeaderReplayedFrom = headerPrefix + "replayed-from"
+)
+
+// Entry is a message in the dead-letter topic
+type Entry struct {
+	// Partition and Offset locate the entry in the dead-letter topic
+	Partition int32
+	Offset    int64
+
+	Reason          string
+	SourceTopic     string
+	SourcePartition int32
+	SourceOffset    int64
+	Attempts        int
+	FailedAt        time.Time
+
+	Key   []byte
+	Value []byte
+	// Headers of the original message, without the dead-letter headers
+	Headers []kgo.RecordHeader
+}
+
+// Position is how commands refer to the entry: partition:offset
+func (e *Entry) Position() string {
+	return fmt.Sprintf("%d:%d", e.Partition, e.Offset)
+}
+
+// ParsePosition parses partition:offset as printed by Position
+func ParsePosition(position string) (int32, int64, error) {
+	partition, offset, ok := strings.Cut(position, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid position %q, expected partition:offset", position)
+	}
+	p, err := strconv.ParseInt(partition, 10, 32)
+	if err != nil || p < 0 {
+		return 0, 0, fmt.Errorf("invalid partition in position %q", position)
+	}
+	o, err := strconv.ParseInt(offset, 10, 64)
+	if err != nil || o < 0 {
+		return 0, 0, fmt.Errorf("invalid offset in position %q", position)
+	}
+	return int32(p), o, nil
+}
+
+// deadLetterRecord copies a consumed record to the dead-letter topic with the reason of the failure
+func deadLetterRecord(topic string, record *kgo.Record, cause error, attempts int, failedAt time.Time) *kgo.Record {
+	headers := append(originalHeaders(record.Headers),
+		kgo.RecordHeader{Key: HeaderReason, Value: []byte(cause.Error())},
+		kgo.RecordHeader{Key: HeaderSourceTopic, Value: []byte(record.Topic)},
+		kgo.RecordHeader{Key: HeaderSourcePartition, Value: []byte(strconv.FormatInt(int64(record.Partition), 10))},
+		kgo.RecordHeader{Key: HeaderSourceOffset, Value: []byte(strconv.FormatInt(record.Offset, 10))},
+		kgo.RecordHeader{Key: HeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kgo.RecordHeader{Key: HeaderFailedAt, Value: []byte(failedAt.UTC().Format(time.RFC3339))},
+	)
+
+	return &kgo.Record{
+		Topic:   topic,
+		Key:     record.Key,
+		Value:   record.Value,
+		Headers: headers,
+	}
+}
+
+// entryFromRecord reads the dead-letter headers back; malformed ones are left at zero values
+func entryFromRecord(record *kgo.Record) Entry {
+	entry := Entry{
+		Partition: record.Partition,
+		Offset:    record.Offset,
+		Key:       record.Key,
+		Value:     record.Value,
+		Headers:   originalHeaders(record.Headers),
+	}
+
+	for _, header := range record.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case HeaderReason:
+			entry.Reason = value
+		case HeaderSourceTopic:
+			entry.SourceTopic = value
+		case HeaderSourcePartition:
+			if p, err := strconv.ParseInt(value, 10, 32); err == nil {
+				entry.SourcePartition = int32(p)
+			}
+		case HeaderSourceOffset:
+			entry.SourceOffset, _ = strconv.ParseInt(value, 10, 64)
+		case HeaderAttempts:
+			entry.Attempts, _ = strconv.Atoi(value)
+		case HeaderFailedAt:
+			entry.FailedAt, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+	return entry
+}
+
+// replayRecord returns the original message of an entry, marked with the entry position
+func replayRecord(topic string, entry *Entry) *kgo.Record {
+	headers := append(originalHeaders(entry.Headers),
+		kgo.RecordHeader{Key: HeaderReplayedFrom, Value: []byte(fmt.Sprintf("%s/%s", topic, entry.Position()))})
+
+	return &kgo.Record{
+		Topic:   entry.SourceTopic,
+		Key:     entry.Key,
+		Value:   entry.Value,
+		Headers: headers,
+	}
+}
+
+// originalHeaders drops dead-letter headers, e.g. of a replayed message that failed again
+func originalHeaders(headers []kgo.RecordHeader) []kgo.RecordHeader {
+	result := make([]kgo.RecordHeader, 0, len(headers))
+	for _, header := range headers {
+		if !strings.HasPrefix(header.Key, headerPrefix) {
+			result = append(result, header)
+		}
+	}
+	return result
+}
@@ -0,0 +1,25 @@
+package deadletter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	deadLettered = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "go_init_manager_dead_letter_total",
+		Help: "Consumed messages moved to the dead-letter topic, by reason (unprocessable, retries_exhausted).",
+	}, []string{"reason"})
+	processingRetries = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_consumer_retries_total",
+		Help: "Repeated attempts to process a consumed message after a transient error.",
+	})
+	failedPublishes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_dead_letter_publish_failures_total",
+		Help: "Failed attempts to publish to the dead-letter topic; consumption waits until it succeeds.",
+	})
+	fetchErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_kafka_consume_errors_total",
+		Help: "Errors returned by Kafka when fetching messages of the consumed topic.",
+	})
+)
@@ -0,0 +1,171 @@
+package deadletter
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"gitlab.com/go-init/go-init-common/default/kafka"
+)
+
+// Store reads the dead-letter topic and replays its entries to their source topics.
+// Kafka has no per-message delete, so replayed entries stay in the topic until retention removes them.
+type Store struct {
+	cfg     *Config
+	brokers []string
+	client  *kgo.Client
+}
+
+// NewStore connects to the brokers of kafkaCfg; the caller must Close the store
+func NewStore(cfg *Config, kafkaCfg *kafka.Config) (*Store, error) {
+	client, err := kgo.NewClient(kgo.SeedBrokers(kafkaCfg.Address...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	return &Store{cfg: cfg, brokers: kafkaCfg.Address, client: client}, nil
+}
+
+func (s *Store) Close() {
+	s.client.Close()
+}
+
+// offsetRange is the part of a partition that is still stored: [start, end)
+type offsetRange struct {
+	start, end int64
+}
+
+// List reads every entry currently stored in the dead-letter topic, ordered by partition and offset
+func (s *Store) List(ctx context.Context) ([]Entry, error) {
+	ranges, err := s.offsetRanges(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make(map[int32]kgo.Offset)
+	remaining := make(map[int32]int64)
+	for partition, r := range ranges {
+		if r.start < r.end {
+			partitions[partition] = kgo.NewOffset().At(r.start)
+			remaining[partition] = r.end
+		}
+	}
+	if len(partitions) == 0 {
+		return nil, nil
+	}
+
+	reader, err := kgo.NewClient(
+		kgo.SeedBrokers(s.brokers...),
+		kgo.ConsumePartitions(map[string]map[int32]kgo.Offset{s.cfg.Topic: partitions}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	defer reader.Close()
+
+	var entries []Entry
+	// Читаем до конца, зафиксированного в начале, чтобы не ждать записей, поступающих во время чтения
+	for len(remaining) > 0 {
+		fetches := reader.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := fetches.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read dead-letter topic %s: %w", s.cfg.Topic, err)
+		}
+		fetches.EachRecord(func(record *kgo.Record) {
+			end, ok := remaining[record.Partition]
+			if !ok || record.Offset >= end {
+				return
+			}
+			entries = append(entries, entryFromRecord(record))
+			if record.Offset >= end-1 {
+				delete(remaining, record.Partition)
+			}
+		})
+	}
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Or(cmp.Compare(a.Partition, b.Partition), cmp.Compare(a.Offset, b.Offset))
+	})
+	return entries, nil
+}
+
+// Replay publishes the original messages of entries back to their source topics,
+// where the consumer processes them again
+func (s *Store) Replay(ctx context.Context, entries []Entry) error {
+	records := make([]*kgo.Record, 0, len(entries))
+	for i := range entries {
+		if entries[i].SourceTopic == "" {
+			return fmt.Errorf("entry %s has no %s header", entries[i].Position(), HeaderSourceTopic)
+		}
+		records = append(records, replayRecord(s.cfg.Topic, &entries[i]))
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, s.cfg.PublishTimeout)
+	defer cancel()
+	for i, result := range s.client.ProduceSync(publishCtx, records...) {
+		if result.Err != nil {
+			return fmt.Errorf("failed to replay entry %s (%d of %d replayed): %w", entries[i].Position(), i, len(entries), result.Err)
+		}
+	}
+	return nil
+}
+
+// offsetRanges returns the stored offsets of every partition of the dead-letter topic
+func (s *Store) offsetRanges(ctx context.Context) (map[int32]offsetRange, error) {
+	metadata := kmsg.NewPtrMetadataRequest()
+	metadataTopic := kmsg.NewMetadataRequestTopic()
+	metadataTopic.Topic = kmsg.StringPtr(s.cfg.Topic)
+	metadata.Topics = append(metadata.Topics, metadataTopic)
+
+	metadataResp, err := metadata.RequestWith(ctx, s.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata of dead-letter topic %s: %w", s.cfg.Topic, err)
+	}
+	if len(metadataResp.Topics) != 1 {
+		return nil, fmt.Errorf("dead-letter topic %s not found in metadata", s.cfg.Topic)
+	}
+	if err := kerr.ErrorForCode(metadataResp.Topics[0].ErrorCode); err != nil {
+		return nil, fmt.Errorf("dead-letter topic %s: %w", s.cfg.Topic, err)
+	}
+
+	ranges := make(map[int32]offsetRange)
+	// Timestamp -2 запрашивает первый хранимый offset партиции, -1 следующий за последним
+	for _, timestamp := range []int64{-2, -1} {
+		listTopic := kmsg.NewListOffsetsRequestTopic()
+		listTopic.Topic = s.cfg.Topic
+		for _, partition := range metadataResp.Topics[0].Partitions {
+			listPartition := kmsg.NewListOffsetsRequestTopicPartition()
+			listPartition.Partition = partition.Partition
+			listPartition.Timestamp = timestamp
+			listTopic.Partitions = append(listTopic.Partitions, listPartition)
+		}
+		list := kmsg.NewPtrListOffsetsRequest()
+		list.Topics = append(list.Topics, listTopic)
+
+		listResp, err := list.RequestWith(ctx, s.client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list offsets of dead-letter topic %s: %w", s.cfg.Topic, err)
+		}
+		for _, topic := range listResp.Topics {
+			for _, partition := range topic.Partitions {
+				if err := kerr.ErrorForCode(partition.ErrorCode); err != nil {
+					return nil, fmt.Errorf("failed to list offsets of dead-letter topic %s partition %d: %w",
+						s.cfg.Topic, partition.Partition, err)
+				}
+				r := ranges[partition.Partition]
+				if timestamp == -2 {
+					r.start = partition.Offset
+				} else {
+					r.end = partition.Offset
+				}
+				ranges[partition.Partition] = r
+			}
+		}
+	}
+	return ranges, nil
+}
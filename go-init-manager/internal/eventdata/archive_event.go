@@ -2,6 +2,8 @@ package eventdata
 
 import (
 	"encoding/json"
+
+	"go-init-observability/tracing"
 )
 
 const (
@@ -22,6 +24,8 @@ type CloudEvent struct {
 	DataSchema      string          `json:"dataschema"`
 	Time            string          `json:"time"`
 	Data            json.RawMessage `json:"data"`
+	// Контекст трассировки отправителя из расширений traceparent и tracestate
+	tracing.EventContext
 }
 
 // ArchiveMetadata представляет метаданные архива, полученные от издателя
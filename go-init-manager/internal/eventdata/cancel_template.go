@@ -0,0 +1,20 @@
+package eventdata
+
+const (
+	// CancelTemplateEventType тип события отмены генерации шаблона
+	CancelTemplateEventType = "cancel-template"
+
+	// CancelTemplateSchema схема события отмены генерации
+	CancelTemplateSchema = "go-init-cancel-template-schema"
+)
+
+// CancelTemplate просит генератор прервать генерацию шаблона.
+// Публикуется в топик обработки с тем же ключом, что и process-template.
+type CancelTemplate struct {
+	// ID архива текущей ревизии шаблона (совпадает с ProcessTemplate.ID)
+	ID string `json:"id"`
+
+	// CancelledAt время отмены в формате RFC3339Nano. Генератор игнорирует отмену
+	// для событий process-template, созданных позже (повторный запуск после отмены).
+	CancelledAt string `json:"cancelledAt"`
+}
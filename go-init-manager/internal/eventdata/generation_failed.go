@@ -0,0 +1,21 @@
+package eventdata
+
+const (
+	// GenerationFailedEventType тип события о неудачной генерации шаблона
+	GenerationFailedEventType = "generation-failed"
+)
+
+// GenerationFailed представляет ошибку генерации, полученную от генератора
+type GenerationFailed struct {
+	// ID идентификатор архива ревизии шаблона (ProcessTemplate.ID)
+	ID string `json:"id"`
+
+	// Stage этап генерации, на котором произошла ошибка ("generate", "stream")
+	Stage string `json:"stage"`
+
+	// Error текст ошибки
+	Error string `json:"error"`
+
+	// FailedAt время ошибки в формате RFC3339
+	FailedAt string `json:"failedAt"`
+}
@@ -0,0 +1,15 @@
+package eventdata
+
+const (
+	// GenerationStartedEventType тип события о начале генерации шаблона
+	GenerationStartedEventType = "generation-started"
+)
+
+// GenerationStarted сообщает, что генератор взял шаблон в работу
+type GenerationStarted struct {
+	// ID идентификатор архива ревизии шаблона (ProcessTemplate.ID)
+	ID string `json:"id"`
+
+	// StartedAt время начала генерации в формате RFC3339
+	StartedAt string `json:"startedAt"`
+}
@@ -7,7 +7,7 @@ const (
 	// ProcessingTopicID ID топика для обработки шаблонов
 	ProcessingTopicID = "go-init-processing"
 
-	// JsonSchema схема JSON для событий шаблона
+	// JsonSchema схема JSON для событий шаблона, должна совпадать с eventdata.JsonSchema генератора
 	JsonSchema = `
 	{
 		"type": "object",
@@ -19,7 +19,11 @@ const (
 					"type": "object",
 					"properties": {
 						"protocol": {"type": "string"},
-						"role": {"type": "string"}
+						"role": {"type": "string"},
+						"config": {
+							"type": "object",
+							"additionalProperties": {"type": "string"}
+						}
 					}
 				}
 			},
@@ -27,7 +31,9 @@ const (
 				"type": "object",
 				"properties": {
 					"type": {"type": "string"},
-					"ddl": {"type": "string"}
+					"ddl": {"type": "string"},
+					"migrations": {"type": "boolean"},
+					"models": {"type": "boolean"}
 				}
 			},
 			"docker": {
@@ -41,7 +47,11 @@ const (
 				"type": "object",
 				"properties": {
 					"enableAuthentication": {"type": "boolean"},
-					"generateSwaggerDocs": {"type": "boolean"}
+					"generateSwaggerDocs": {"type": "boolean"},
+					"modulePath": {"type": "string"},
+					"serviceDescription": {"type": "string"},
+					"enableGraphQL": {"type": "boolean"},
+					"enableGRPC": {"type": "boolean"}
 				}
 			}
 		}
@@ -58,13 +68,16 @@ type TemplateEventData struct {
 }
 
 type EndpointEventData struct {
-	Protocol string `json:"protocol"`
-	Role     string `json:"role"`
+	Protocol string            `json:"protocol"`
+	Role     string            `json:"role"`
+	Config   map[string]string `json:"config,omitempty"`
 }
 
 type DatabaseEventData struct {
-	Type string `json:"type"`
-	DDL  string `json:"ddl,omitempty"`
+	Type       string `json:"type"`
+	DDL        string `json:"ddl,omitempty"`
+	Migrations bool   `json:"migrations,omitempty"`
+	Models     bool   `json:"models,omitempty"`
 }
 
 type DockerEventData struct {
@@ -73,11 +86,17 @@ type DockerEventData struct {
 }
 
 type AdvancedEventData struct {
-	EnableAuthentication bool `json:"enableAuthentication,omitempty"`
-	GenerateSwaggerDocs  bool `json:"generateSwaggerDocs,omitempty"`
+	EnableAuthentication bool   `json:"enableAuthentication,omitempty"`
+	GenerateSwaggerDocs  bool   `json:"generateSwaggerDocs,omitempty"`
+	ModulePath           string `json:"modulePath,omitempty"`
+	ServiceDescription   string `json:"serviceDescription,omitempty"`
+	EnableGraphQL        bool   `json:"enableGraphQL,omitempty"`
+	EnableGRPC           bool   `json:"enableGRPC,omitempty"`
 }
 
 type ProcessTemplate struct {
+	// ID архива ревизии: UUID шаблона для первой ревизии, новый UUID для каждой следующей.
+	// Генератор и publisher возвращают его во всех событиях генерации
 	ID     string            `json:"id"`
 	Status string            `json:"status"`
 	Data   TemplateEventData `json:"data"`
@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dbRepo "go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// CancelTemplate stops generation of a pending or processing template.
+// The template becomes CANCELLED right away; the generator is told to abort through a cancel-template
+// event, and results it still reports for this template are rejected by the status lifecycle.
+func (s *Service) CancelTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
+	template, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return templateLookupFailure(err), nil
+	}
+
+	if err := s.cancelTemplateWithEvent(ctx, template); err != nil {
+		var transitionErr *dbRepo.StatusTransitionError
+		message := "Failed to cancel template: " + err.Error()
+		if errors.As(err, &transitionErr) {
+			message = fmt.Sprintf("Template in status %s cannot be cancelled", transitionErr.From)
+		}
+		return &model.TemplateResponse{
+			Success: false,
+			Message: &message,
+		}, nil
+	}
+
+	s.logger.Info(fmt.Sprintf("Template %s cancelled", template.ServiceTemplateUuid))
+
+	template, err = s.dbManagerRepo.GetTemplateByID(ctx, *template.ServiceTemplateId)
+	if err != nil {
+		return templateLookupFailure(err), nil
+	}
+
+	response := createSuccessResponse(template)
+	response.Message = strPtr("Template generation cancelled")
+	return response, nil
+}
+
+// cancelTemplateWithEvent marks the template CANCELLED and stores the cancel-template event in one transaction
+func (s *Service) cancelTemplateWithEvent(ctx context.Context, template *dbModel.ServiceTemplate) error {
+	return s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		if err := s.dbManagerRepo.CancelTemplate(ctx, *template.ServiceTemplateId, s.serviceName, tx); err != nil {
+			return err
+		}
+		return s.produceCancelEvent(ctx, tx, converter.ArchiveID(template).String(), time.Now())
+	})
+}
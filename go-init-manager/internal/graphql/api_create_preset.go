@@ -0,0 +1,85 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-init/internal/auth"
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/internal/graphql/converter"
+	"go-init/internal/validation"
+	"go-init/pkg/api/graphql/model"
+)
+
+// errForeignTeam is returned when a preset is shared with a team the caller is not a member of
+var errForeignTeam = errors.New("presets can only be shared with your own teams")
+
+// CreatePreset saves the template parameters as the first version of a new preset owned by the caller
+func (s *Service) CreatePreset(ctx context.Context, input model.CreatePresetInput) (*model.PresetResponse, error) {
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.CreatePresetInput(input); err != nil {
+		return nil, validationFailed(ctx, err)
+	}
+
+	visibility := model.PresetVisibilityPrivate
+	if input.Visibility != nil {
+		visibility = *input.Visibility
+	}
+	team := normalizeTeam(input.Team)
+	if err := checkPresetTeam(identity, visibility, team); err != nil {
+		return presetFailure(err), nil
+	}
+
+	templateInput, err := converter.TemplateInputToJSON(input.Template)
+	if err != nil {
+		return presetFailure(err), nil
+	}
+
+	name := strings.TrimSpace(input.Name)
+	visibilityValue := visibility.String()
+	preset := &dbModel.Preset{
+		PresetName:  &name,
+		Description: input.Description,
+		OwnerId:     &identity.UserID,
+		Team:        team,
+		Visibility:  &visibilityValue,
+	}
+	if err := s.presetRepo.CreatePreset(ctx, preset, templateInput); err != nil {
+		return presetFailure(err), nil
+	}
+
+	// UUID и время создания заполняются базой и возвращаются GORM после вставки
+	presetVersion := &dbModel.PresetVersion{PresetId: *preset.PresetId, Version: preset.Version, Input: templateInput}
+
+	s.logger.Info(fmt.Sprintf("Preset %s created by %s", preset.PresetUuid, identity.UserID))
+	return presetResponse(preset, presetVersion, "Preset created successfully"), nil
+}
+
+// checkPresetTeam: делиться пресетом можно только со своей командой; администратору - с любой
+func checkPresetTeam(identity *auth.Identity, visibility model.PresetVisibility, team *string) error {
+	if visibility != model.PresetVisibilityTeam || identity.Admin {
+		return nil
+	}
+	if team == nil || !identity.InTeam(*team) {
+		return errForeignTeam
+	}
+	return nil
+}
+
+// normalizeTeam trims the team name; an empty name means no team
+func normalizeTeam(team *string) *string {
+	if team == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*team)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
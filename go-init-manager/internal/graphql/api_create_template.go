@@ -2,63 +2,108 @@ package graphql
 
 import (
 	"context"
+
+	"go-init/internal/auth"
+	dbModel "go-init/internal/database/request_repo/models"
 	"go-init/internal/graphql/converter"
+	"go-init/internal/validation"
 	"go-init/pkg/api/graphql/model"
 
 	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
 )
 
-const PendingStatus = "pending"
+const PendingStatus = StatusPending
 
 func (s *Service) CreateTemplate(ctx context.Context, input model.CreateTemplateInput) (*model.TemplateResponse, error) {
-	tx, err := s.agent.BeginTx(ctx)
+	identity, err := auth.FromContext(ctx)
 	if err != nil {
-		return &model.TemplateResponse{
-			Success: false,
-			Message: strPtr("Failed to begin transaction: " + err.Error()),
-		}, nil
+		return nil, err
 	}
-	defer tx.Enfold(ctx, &err)
 
-	template, err := converter.FromInputToDbServiceTemplate(ctx, input, s.logger)
+	// Некорректный ввод отклоняется до сохранения, иначе он всплывет только при генерации
+	if err := validation.CreateTemplateInput(input); err != nil {
+		return nil, validationFailed(ctx, err)
+	}
+
+	return s.createTemplate(ctx, identity, input)
+}
+
+// createTemplate сохраняет проверенные входные данные как новый шаблон caller'а и ставит его в очередь генерации.
+// Лимит проверяется после валидации, чтобы некорректные запросы не расходовали квоту,
+// а несохраненный шаблон возвращает засчитанное создание
+func (s *Service) createTemplate(ctx context.Context, identity *auth.Identity, input model.CreateTemplateInput) (*model.TemplateResponse, error) {
+	refund, err := s.limiter.AllowTemplateCreation(ctx, identity.UserID)
+	if err != nil {
+		return nil, rateLimited(ctx, err)
+	}
+
+	response := s.saveTemplate(ctx, identity, input)
+	if !response.Success {
+		refund()
+	}
+	return response, nil
+}
+
+// saveTemplate сохраняет шаблон вместе с событием process-template
+func (s *Service) saveTemplate(ctx context.Context, identity *auth.Identity, input model.CreateTemplateInput) *model.TemplateResponse {
+	template, err := converter.FromInputToDbServiceTemplate(ctx, input, identity.UserID, s.logger)
 	if err != nil {
 		return &model.TemplateResponse{
 			Success: false,
 			Message: strPtr("Failed to convert input: " + err.Error()),
-		}, nil
+			Reason:  FailureInternal,
+		}
 	}
 
-	err = s.dbManagerRepo.CreateNewTemplate(ctx, template, tx)
-	if err != nil {
+	if err := s.createTemplateWithEvent(ctx, template, input); err != nil {
 		return &model.TemplateResponse{
 			Success: false,
 			Message: strPtr("Failed to create template: " + err.Error()),
-		}, nil
-	}
-
-	var templateUUID uuid.UUID
-	if template == nil || template.ServiceTemplateUuid == nil {
-		templateUUID = uuid.New()
-		s.logger.Warn("Template UUID is nil, using generated UUID")
-	} else {
-		templateUUID = *template.ServiceTemplateUuid
+			Reason:  FailureInternal,
+		}
 	}
 
-	// Публикуем событие, используя UUID шаблона
-	ev := converter.FromInputToEvent(input, templateUUID)
-	s.ProduceEvent(ctx, &ev)
-
 	graphqlTemplate := converter.DbTemplateToGraphqlTemplate(template)
 	if graphqlTemplate == nil {
 		return &model.TemplateResponse{
 			Success: false,
 			Message: strPtr("Failed to convert template to GraphQL model"),
-		}, nil
+			Reason:  FailureInternal,
+		}
 	}
 
 	return &model.TemplateResponse{
 		Success:  true,
 		Message:  strPtr("Template created successfully"),
 		Template: graphqlTemplate,
-	}, nil
+	}
+}
+
+// createTemplateWithEvent сохраняет шаблон и событие process-template в одной транзакции.
+// Ошибка коммита возвращается вызывающему, чтобы клиент не получил успех для несохраненного шаблона.
+func (s *Service) createTemplateWithEvent(ctx context.Context, template *dbModel.ServiceTemplate, input model.CreateTemplateInput) error {
+	return s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		// Первая запись истории статусов создается вместе с шаблоном
+		initialStatus := StatusPending
+		template.StatusHistory = []*dbModel.TemplateStatusHistory{
+			{ToStatus: &initialStatus, Source: &s.serviceName},
+		}
+
+		if err := s.dbManagerRepo.CreateNewTemplate(ctx, template, tx); err != nil {
+			return err
+		}
+
+		var templateUUID uuid.UUID
+		if template.ServiceTemplateUuid == nil {
+			templateUUID = uuid.New()
+			s.logger.Warn("Template UUID is nil, using generated UUID")
+		} else {
+			templateUUID = *template.ServiceTemplateUuid
+		}
+
+		// Событие публикуется relay после коммита, используя UUID шаблона
+		ev := converter.FromInputToEvent(input, templateUUID)
+		return s.ProduceEvent(ctx, tx, &ev)
+	})
 }
@@ -0,0 +1,50 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"go-init/internal/auth"
+	"go-init/internal/graphql/converter"
+	"go-init/internal/validation"
+	"go-init/pkg/api/graphql/model"
+)
+
+// CreateTemplateFromPreset creates a template from a preset version (the current one by default).
+// Overrides are deep-merged into the stored parameters: objects are merged field by field,
+// lists and scalar values replace the preset value and null removes it.
+// The merged input is validated the same way as createTemplate input, errors point into overrides.
+func (s *Service) CreateTemplateFromPreset(ctx context.Context, presetID string, version *int, overrides map[string]any) (*model.TemplateResponse, error) {
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	preset, err := s.findPreset(ctx, presetID)
+	if err != nil {
+		return presetTemplateFailure(err), nil
+	}
+	presetVersion, err := s.presetRepo.GetPresetVersion(ctx, *preset.PresetId, intValue(version))
+	if err != nil {
+		return presetTemplateFailure(err), nil
+	}
+
+	input, err := converter.MergePresetInput(presetVersion.Input, overrides)
+	if err != nil {
+		return nil, validationFailed(ctx, err)
+	}
+	if err := validation.CreateTemplateInput(input, "overrides"); err != nil {
+		return nil, validationFailed(ctx, err)
+	}
+
+	s.logger.Info(fmt.Sprintf("Creating template from preset %s version %d", preset.PresetUuid, presetVersion.Version))
+	return s.createTemplate(ctx, identity, input)
+}
+
+// presetTemplateFailure reports a preset lookup error in a template response
+func presetTemplateFailure(err error) *model.TemplateResponse {
+	return &model.TemplateResponse{
+		Success: false,
+		Message: presetFailure(err).Message,
+	}
+}
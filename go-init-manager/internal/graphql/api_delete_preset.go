@@ -0,0 +1,30 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+)
+
+// DeletePreset removes a preset with all its versions. Templates created from it are not affected.
+// The response carries the deleted preset with its last version.
+func (s *Service) DeletePreset(ctx context.Context, id string) (*model.PresetResponse, error) {
+	preset, identity, err := s.findManagedPreset(ctx, id)
+	if err != nil {
+		return presetFailure(err), nil
+	}
+
+	presetVersion, err := s.presetRepo.GetPresetVersion(ctx, *preset.PresetId, preset.Version)
+	if err != nil {
+		return presetFailure(err), nil
+	}
+	if err := s.presetRepo.DeletePreset(ctx, *preset.PresetId); err != nil {
+		return presetFailure(err), nil
+	}
+
+	s.logger.Info(fmt.Sprintf("Preset %s (%s) deleted by %s",
+		preset.PresetUuid, converter.StringValue(preset.PresetName, ""), identity.UserID))
+	return presetResponse(preset, presetVersion, "Preset deleted"), nil
+}
@@ -0,0 +1,56 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dbRepo "go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/pkg/api/graphql/model"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// DeleteTemplate removes a template with its configs, revisions and status history.
+// The archives of all its revisions are queued for deletion in the same transaction and removed from the publisher storage
+// by cleanup.Cleaner, which retries until the publisher confirms. The response carries the deleted template.
+func (s *Service) DeleteTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
+	template, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return templateLookupFailure(err), nil
+	}
+
+	if err := s.deleteTemplateWithArchive(ctx, template); err != nil {
+		message := "Failed to delete template: " + err.Error()
+		if errors.Is(err, dbRepo.ErrTemplateActive) {
+			message = "Template cannot be deleted while it is being generated, cancel it first"
+		}
+		return &model.TemplateResponse{
+			Success: false,
+			Message: &message,
+		}, nil
+	}
+
+	s.logger.Info(fmt.Sprintf("Template %s deleted, archives queued for removal", template.ServiceTemplateUuid))
+
+	response := createSuccessResponse(template)
+	response.Message = strPtr("Template deleted")
+	return response, nil
+}
+
+// deleteTemplateWithArchive deletes the template rows and queues the archives of its revisions in one transaction
+func (s *Service) deleteTemplateWithArchive(ctx context.Context, template *dbModel.ServiceTemplate) error {
+	return s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		archiveIDs, err := s.dbManagerRepo.DeleteTemplate(ctx, *template.ServiceTemplateId, tx)
+		if err != nil {
+			return err
+		}
+		for _, archiveID := range archiveIDs {
+			if err := s.archiveDeletionRepo.AddArchiveDeletion(ctx, archiveID, tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-init/internal/auth"
+	dbRepo "go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// errInvalidPresetID is returned when a preset ID is not a UUID
+	errInvalidPresetID = errors.New("invalid preset ID format")
+	// errPresetNotAccessible hides presets the caller cannot see; it reads the same as a missing preset
+	errPresetNotAccessible = errors.New("preset not found")
+	// errPresetReadOnly is returned when the caller can use a preset but not change it
+	errPresetReadOnly = errors.New("preset can only be changed by its owner or team")
+)
+
+// GetPreset returns a preset with the parameters of the requested version (the current one by default)
+func (s *Service) GetPreset(ctx context.Context, id string, version *int) (*model.PresetResponse, error) {
+	preset, err := s.findPreset(ctx, id)
+	if err != nil {
+		return presetFailure(err), nil
+	}
+
+	presetVersion, err := s.presetRepo.GetPresetVersion(ctx, *preset.PresetId, intValue(version))
+	if err != nil {
+		return presetFailure(err), nil
+	}
+	return presetResponse(preset, presetVersion, "Preset retrieved successfully"), nil
+}
+
+// findPreset loads a preset by UUID; presets the caller cannot see are reported as missing
+func (s *Service) findPreset(ctx context.Context, id string) (*dbModel.Preset, error) {
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	presetUUID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errInvalidPresetID
+	}
+
+	preset, err := s.presetRepo.GetPresetByUUID(ctx, presetUUID)
+	if err != nil {
+		return nil, err
+	}
+	if !canViewPreset(identity, preset) {
+		return nil, errPresetNotAccessible
+	}
+	return preset, nil
+}
+
+// findManagedPreset loads a preset the caller may change or delete
+func (s *Service) findManagedPreset(ctx context.Context, id string) (*dbModel.Preset, *auth.Identity, error) {
+	preset, err := s.findPreset(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !canManagePreset(identity, preset) {
+		return nil, nil, errPresetReadOnly
+	}
+	return preset, identity, nil
+}
+
+// canViewPreset: свои пресеты, публичные и пресеты своих команд; администратору - все
+func canViewPreset(identity *auth.Identity, preset *dbModel.Preset) bool {
+	if canManagePreset(identity, preset) {
+		return true
+	}
+	return converter.StringValue(preset.Visibility, "") == dbRepo.PresetVisibilityPublic
+}
+
+// canManagePreset: менять пресет могут владелец, администратор и члены команды командного пресета
+func canManagePreset(identity *auth.Identity, preset *dbModel.Preset) bool {
+	if identity.CanAccess(preset.OwnerId) {
+		return true
+	}
+	return converter.StringValue(preset.Visibility, "") == dbRepo.PresetVisibilityTeam &&
+		identity.InTeam(converter.StringValue(preset.Team, ""))
+}
+
+// presetFailure converts a preset lookup or write error to an unsuccessful response
+func presetFailure(err error) *model.PresetResponse {
+	message := err.Error()
+	switch {
+	case errors.Is(err, dbRepo.ErrPresetNotFound), errors.Is(err, errPresetNotAccessible):
+		message = fmt.Sprintf("Preset not found: %v", err)
+	case errors.Is(err, dbRepo.ErrPresetNameTaken):
+		message = "A preset with this name already exists"
+	}
+
+	return &model.PresetResponse{
+		Success: false,
+		Message: &message,
+	}
+}
+
+// presetResponse builds a successful response with the preset and the parameters of presetVersion
+func presetResponse(preset *dbModel.Preset, presetVersion *dbModel.PresetVersion, message string) *model.PresetResponse {
+	graphqlPreset, err := converter.DbPresetToGraphqlPreset(preset, presetVersion)
+	if err != nil {
+		return presetFailure(err)
+	}
+	return &model.PresetResponse{
+		Success: true,
+		Message: &message,
+		Preset:  graphqlPreset,
+	}
+}
+
+func intValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
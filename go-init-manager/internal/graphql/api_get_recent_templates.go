@@ -3,8 +3,11 @@ package graphql
 import (
 	"context"
 
+	"go-init/internal/auth"
 	"go-init/internal/graphql/converter"
 	"go-init/pkg/api/graphql/model"
+
+	"github.com/google/uuid"
 )
 
 // GetRecentTemplates retrieves a list of recently created templates
@@ -15,8 +18,16 @@ func (s *Service) GetRecentTemplates(ctx context.Context, limit *int) (*model.Te
 		limitValue = *limit
 	}
 
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return &model.TemplatesResponse{
+			Success: false,
+			Message: strPtr(err.Error()),
+		}, nil
+	}
+
 	// Get templates from repository
-	templates, err := s.dbManagerRepo.GetRecentTemplates(ctx, limitValue)
+	templates, err := s.dbManagerRepo.GetRecentTemplates(ctx, limitValue, ownerFilter(identity))
 	if err != nil {
 		return &model.TemplatesResponse{
 			Success: false,
@@ -39,3 +50,11 @@ func (s *Service) GetRecentTemplates(ctx context.Context, limit *int) (*model.Te
 		Templates: graphqlTemplates,
 	}, nil
 }
+
+// ownerFilter limits listings to the caller's own templates unless the caller is an admin
+func ownerFilter(identity *auth.Identity) *uuid.UUID {
+	if identity.Admin {
+		return nil
+	}
+	return &identity.UserID
+}
@@ -2,9 +2,11 @@ package graphql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
+	"go-init/internal/auth"
 	dbModel "go-init/internal/database/request_repo/models"
 	"go-init/internal/graphql/converter"
 	"go-init/pkg/api/graphql/model"
@@ -14,39 +16,69 @@ import (
 
 const statusDone = "Done"
 
+var (
+	// errInvalidTemplateID is returned when an ID is neither a template UUID nor a numeric ID
+	errInvalidTemplateID = errors.New("invalid template ID format")
+	// errTemplateNotAccessible hides templates of other users; it reads the same as a missing template
+	errTemplateNotAccessible = errors.New("template not found")
+)
+
 func (s *Service) GetTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
 	s.logger.Info("Getting template by ID: " + id)
 
-	// Сначала пробуем как UUID
-	if templateUUID, err := uuid.Parse(id); err == nil {
-		template, err := s.dbManagerRepo.GetTemplateByUUID(ctx, templateUUID)
-		if err != nil {
-			return &model.TemplateResponse{
-				Success: false,
-				Message: strPtr(fmt.Sprintf("Template not found: %v", err)),
-			}, nil
-		}
-		return createSuccessResponse(template), nil
+	template, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return templateLookupFailure(err), nil
 	}
 
-	// Иначе — пытаемся считать как int
-	templateID, err := strconv.Atoi(id)
+	return createSuccessResponse(template), nil
+}
+
+// findTemplate loads a template by UUID or, failing that, by numeric ID.
+// Templates of other users are reported as missing unless the caller is an admin.
+func (s *Service) findTemplate(ctx context.Context, id string) (*dbModel.ServiceTemplate, error) {
+	identity, err := auth.FromContext(ctx)
 	if err != nil {
-		return &model.TemplateResponse{
-			Success: false,
-			Message: strPtr("Invalid template ID format"),
-		}, nil
+		return nil, err
 	}
 
-	template, err := s.dbManagerRepo.GetTemplateByID(ctx, templateID)
+	var template *dbModel.ServiceTemplate
+	// Сначала пробуем как UUID
+	if templateUUID, parseErr := uuid.Parse(id); parseErr == nil {
+		template, err = s.dbManagerRepo.GetTemplateByUUID(ctx, templateUUID)
+	} else {
+		// Иначе — пытаемся считать как int
+		templateID, convErr := strconv.Atoi(id)
+		if convErr != nil {
+			return nil, errInvalidTemplateID
+		}
+		template, err = s.dbManagerRepo.GetTemplateByID(ctx, templateID)
+	}
 	if err != nil {
-		return &model.TemplateResponse{
-			Success: false,
-			Message: strPtr(fmt.Sprintf("Template not found: %v", err)),
-		}, nil
+		return nil, err
 	}
 
-	return createSuccessResponse(template), nil
+	if !identity.CanAccess(template.UserId) {
+		return nil, errTemplateNotAccessible
+	}
+	return template, nil
+}
+
+// templateLookupFailure converts a findTemplate error to an unsuccessful response
+func templateLookupFailure(err error) *model.TemplateResponse {
+	message, reason := fmt.Sprintf("Template not found: %v", err), FailureNotFound
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		message, reason = err.Error(), FailureUnauthenticated
+	case errors.Is(err, errInvalidTemplateID):
+		message, reason = "Invalid template ID format", FailureInvalidID
+	}
+
+	return &model.TemplateResponse{
+		Success: false,
+		Message: &message,
+		Reason:  reason,
+	}
 }
 
 // Helper function to create a pointer to a string
@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"go-init/internal/auth"
+	dbRepo "go-init/internal/database"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+)
+
+const (
+	defaultPresetsPageSize = 50
+	maxPresetsPageSize     = 200
+)
+
+// ListPresets returns presets visible to the caller with the parameters of their current versions
+func (s *Service) ListPresets(ctx context.Context, filter *model.PresetFilter, first *int) (*model.PresetsResponse, error) {
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := defaultPresetsPageSize
+	if first != nil {
+		limit = *first
+	}
+	if limit < 0 || limit > maxPresetsPageSize {
+		return nil, fmt.Errorf("first must be between 0 and %d", maxPresetsPageSize)
+	}
+
+	params := dbRepo.PresetListParams{
+		ViewerID:    identity.UserID,
+		ViewerTeams: identity.Teams,
+		All:         identity.Admin,
+		Limit:       limit,
+	}
+	if filter != nil {
+		if filter.Mine != nil && *filter.Mine {
+			params.OwnerID = &identity.UserID
+		}
+		params.Team = filter.Team
+		params.NameContains = filter.NameContains
+		if filter.Visibility != nil {
+			visibility := filter.Visibility.String()
+			params.Visibility = &visibility
+		}
+	}
+
+	presets, err := s.presetRepo.ListPresets(ctx, params)
+	if err != nil {
+		return &model.PresetsResponse{
+			Success: false,
+			Message: strPtr(err.Error()),
+		}, nil
+	}
+
+	graphqlPresets := make([]*model.Preset, 0, len(presets))
+	for _, preset := range presets {
+		if len(preset.Versions) == 0 {
+			s.logger.Warn(fmt.Sprintf("Preset %s has no version %d, skipping", preset.PresetUuid, preset.Version))
+			continue
+		}
+
+		graphqlPreset, err := converter.DbPresetToGraphqlPreset(preset, preset.Versions[0])
+		if err != nil {
+			return &model.PresetsResponse{
+				Success: false,
+				Message: strPtr(err.Error()),
+			}, nil
+		}
+		graphqlPresets = append(graphqlPresets, graphqlPreset)
+	}
+
+	return &model.PresetsResponse{
+		Success: true,
+		Message: strPtr("Presets retrieved successfully"),
+		Presets: graphqlPresets,
+	}, nil
+}
@@ -0,0 +1,86 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"go-init/internal/auth"
+	dbRepo "go-init/internal/database"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+)
+
+const (
+	defaultTemplatesPageSize = 20
+	maxTemplatesPageSize     = 100
+)
+
+// ListTemplates returns a Relay connection over templates matching the filter
+func (s *Service) ListTemplates(
+	ctx context.Context,
+	first *int,
+	after *string,
+	filter *model.TemplateFilter,
+	orderBy *model.TemplateOrder,
+) (*model.TemplateConnection, error) {
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := defaultTemplatesPageSize
+	if first != nil {
+		pageSize = *first
+	}
+	if pageSize < 0 || pageSize > maxTemplatesPageSize {
+		return nil, invalidArgument(fmt.Errorf("first must be between 0 and %d", maxTemplatesPageSize))
+	}
+
+	repoFilter, err := converter.FromGraphqlTemplateFilter(filter)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	repoFilter.OwnerID = ownerFilter(identity)
+
+	orderField, descending := converter.FromGraphqlTemplateOrder(orderBy)
+	params := dbRepo.TemplateListParams{
+		Filter:     repoFilter,
+		OrderBy:    orderField,
+		Descending: descending,
+		First:      pageSize,
+	}
+	if after != nil && *after != "" {
+		params.After, err = dbRepo.DecodeTemplateCursor(*after)
+		if err != nil {
+			return nil, invalidArgument(err)
+		}
+	}
+
+	page, err := s.dbManagerRepo.ListTemplates(ctx, params)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to list templates: %v", err))
+		return nil, err
+	}
+
+	connection := &model.TemplateConnection{
+		Edges: make([]*model.TemplateEdge, 0, len(page.Templates)),
+		PageInfo: &model.PageInfo{
+			HasNextPage: page.HasNextPage,
+			// Only forward pagination is supported, so any cursor means there are earlier pages
+			HasPreviousPage: params.After != nil,
+		},
+		TotalCount: int(page.TotalCount),
+	}
+	for _, template := range page.Templates {
+		connection.Edges = append(connection.Edges, &model.TemplateEdge{
+			Cursor: dbRepo.NewTemplateCursor(template, orderField).Encode(),
+			Node:   converter.DbTemplateToGraphqlTemplate(template),
+		})
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+
+	return connection, nil
+}
@@ -0,0 +1,47 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+)
+
+// ResolveRevisions returns all revisions of a template, oldest first. Templates created before
+// revisions were recorded and never changed since get their current configuration as revision 1.
+func (s *Service) ResolveRevisions(ctx context.Context, template *model.ServiceTemplate) ([]*model.TemplateRevision, error) {
+	templateID, err := strconv.Atoi(template.ID)
+	if err != nil {
+		return nil, errInvalidTemplateID
+	}
+
+	revisions, err := s.dbManagerRepo.ListTemplateRevisions(ctx, templateID)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to list revisions of template %d: %v", templateID, err))
+		return nil, fmt.Errorf("template revisions are temporarily unavailable")
+	}
+	if len(revisions) == 0 {
+		stored, err := s.dbManagerRepo.GetTemplateByID(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+		current, err := converter.CurrentTemplateRevision(stored)
+		if err != nil {
+			return nil, err
+		}
+		revisions = []*dbModel.TemplateRevision{current}
+	}
+
+	result := make([]*model.TemplateRevision, 0, len(revisions))
+	for _, revision := range revisions {
+		graphqlRevision, err := converter.DbRevisionToGraphqlRevision(revision)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, graphqlRevision)
+	}
+	return result, nil
+}
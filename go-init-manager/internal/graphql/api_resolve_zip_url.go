@@ -0,0 +1,43 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+// ResolveZipURL returns the download URL of a template archive. Archives stored by the publisher
+// get a presigned URL that is cached until shortly before it expires; templates without
+// a stored object keep the URL they were given (legacy rows and URLs reported over gRPC).
+func (s *Service) ResolveZipURL(ctx context.Context, template *model.ServiceTemplate) (*string, error) {
+	if template.ArchiveObject == "" {
+		return template.ZipURL, nil
+	}
+	return s.archiveURL(ctx, template.ArchiveBucket, template.ArchiveObject, template.ZipURL,
+		fmt.Sprintf("template %s", template.ID))
+}
+
+// ResolveRevisionZipURL returns the download URL of the archive generated for a template revision
+func (s *Service) ResolveRevisionZipURL(ctx context.Context, revision *model.TemplateRevision) (*string, error) {
+	if revision.ArchiveObject == "" {
+		return nil, nil
+	}
+	return s.archiveURL(ctx, revision.ArchiveBucket, revision.ArchiveObject, nil,
+		fmt.Sprintf("revision %d (%s)", revision.Revision, revision.ArchiveObject))
+}
+
+// archiveURL requests a presigned URL of a stored archive; fallback is returned when the publisher is not configured
+func (s *Service) archiveURL(ctx context.Context, bucket, object string, fallback *string, owner string) (*string, error) {
+	if s.downloadURLs == nil {
+		s.logger.Warn(fmt.Sprintf("Publisher client is not configured, no download URL for %s", owner))
+		return fallback, nil
+	}
+
+	url, err := s.downloadURLs.DownloadURL(ctx, bucket, object)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Failed to resolve download URL for %s: %v", owner, err))
+		return nil, fmt.Errorf("download URL is temporarily unavailable")
+	}
+	return &url, nil
+}
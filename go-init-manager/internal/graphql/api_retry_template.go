@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dbRepo "go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// retryEventStatus marks process-template events sent by retryTemplate
+const retryEventStatus = "retry"
+
+// RetryTemplate re-enqueues generation of a failed or cancelled template under a new archive ID.
+// The process-template event is rebuilt from the stored configs and published through the outbox.
+func (s *Service) RetryTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
+	template, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return templateLookupFailure(err), nil
+	}
+
+	retried, err := s.retryTemplateWithEvent(ctx, *template.ServiceTemplateId)
+	if err != nil {
+		var transitionErr *dbRepo.StatusTransitionError
+		message, reason := "Failed to retry template: "+err.Error(), FailureInternal
+		switch {
+		case errors.As(err, &transitionErr):
+			message, reason = fmt.Sprintf("Template in status %s cannot be retried", transitionErr.From), FailureConflict
+		case errors.Is(err, dbRepo.ErrMaxAttemptsReached):
+			message, reason = "Template cannot be retried: "+err.Error(), FailureConflict
+		}
+		return &model.TemplateResponse{
+			Success: false,
+			Message: &message,
+			Reason:  reason,
+		}, nil
+	}
+
+	s.logger.Info(fmt.Sprintf("Template %s re-enqueued, attempt %d of %d, archive %s",
+		template.ServiceTemplateUuid, retried.Attempts, s.cfg.MaxAttempts, converter.ArchiveID(retried)))
+
+	template, err = s.dbManagerRepo.GetTemplateByID(ctx, *template.ServiceTemplateId)
+	if err != nil {
+		return templateLookupFailure(err), nil
+	}
+
+	response := createSuccessResponse(template)
+	response.Message = strPtr(fmt.Sprintf("Template retry enqueued (attempt %d of %d)", retried.Attempts, s.cfg.MaxAttempts))
+	return response, nil
+}
+
+// retryTemplateWithEvent resets the template and stores the new process-template event in one transaction.
+// The event is built from the template as locked by the reset, so a concurrent update cannot change it in between.
+func (s *Service) retryTemplateWithEvent(ctx context.Context, templateID int) (*dbModel.ServiceTemplate, error) {
+	var retried *dbModel.ServiceTemplate
+	err := s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		var err error
+		retried, err = s.dbManagerRepo.ResetTemplateForRetry(ctx, templateID, dbRepo.RetryableStatuses, s.cfg.MaxAttempts, s.serviceName, tx)
+		if err != nil {
+			return err
+		}
+
+		ev := converter.FromDbTemplateToEvent(retried, retryEventStatus)
+		return s.ProduceEvent(ctx, tx, &ev)
+	})
+	return retried, err
+}
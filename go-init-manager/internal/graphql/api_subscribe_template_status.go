@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	dbRepo "go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+)
+
+// SubscribeTemplateStatus streams the template every time its status, archive URL or error changes.
+// The current state is sent first, so a change made between createTemplate and subscribing is not lost.
+// The stream completes once the template reaches its final state.
+func (s *Service) SubscribeTemplateStatus(ctx context.Context, id string) (<-chan *model.ServiceTemplate, error) {
+	template, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+
+	templateID := *template.ServiceTemplateId
+	changes, unsubscribe := s.statusListener.Subscribe(templateID)
+
+	// Re-read after subscribing so that a change committed in between is not missed
+	template, err = s.dbManagerRepo.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		unsubscribe()
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+
+	updates := make(chan *model.ServiceTemplate, 1)
+
+	go func() {
+		defer close(updates)
+		defer unsubscribe()
+
+		lastState := ""
+		for {
+			if state := templateState(template); state != lastState {
+				lastState = state
+				select {
+				case updates <- converter.DbTemplateToGraphqlTemplate(template):
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if isFinalState(template) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+			}
+
+			// Notifications only say that something has changed, the state itself is read from the database
+			template, err = s.dbManagerRepo.GetTemplateByID(ctx, templateID)
+			if err != nil {
+				s.logger.Warn(fmt.Sprintf("Stopping status subscription for template %d: %v", templateID, err))
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
+
+// templateState returns the fields a subscriber is notified about
+func templateState(template *dbModel.ServiceTemplate) string {
+	return fmt.Sprintf("%s|%s|%s|%s",
+		dbRepo.NormalizeStatus(valueOrEmpty(template.Status)),
+		valueOrEmpty(template.ZipURL),
+		valueOrEmpty(template.ArchiveObject),
+		valueOrEmpty(template.Error),
+	)
+}
+
+// isFinalState reports whether no further updates are expected. The archive location of a COMPLETED
+// template and the error of a FAILED one are stored in the same transaction as the status,
+// so a subscriber that sees the final status already sees them too.
+func isFinalState(template *dbModel.ServiceTemplate) bool {
+	switch dbRepo.NormalizeStatus(valueOrEmpty(template.Status)) {
+	case dbRepo.StatusCompleted, dbRepo.StatusFailed, dbRepo.StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func valueOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
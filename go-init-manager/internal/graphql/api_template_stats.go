@@ -0,0 +1,39 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"go-init/internal/auth"
+	dbRepo "go-init/internal/database"
+	"go-init/internal/graphql/converter"
+	"go-init/pkg/api/graphql/model"
+)
+
+// templateStatsTop is the number of rows of each ranking in the template statistics
+const templateStatsTop = 10
+
+// GetTemplateStats aggregates the templates created in [from, to); non-admin users only see their own templates
+func (s *Service) GetTemplateStats(ctx context.Context, from *string, to *string) (*model.TemplateStats, error) {
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAfter, createdBefore, err := converter.FromGraphqlStatsPeriod(from, to)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	stats, err := s.dbManagerRepo.GetTemplateStats(ctx, dbRepo.TemplateStatsParams{
+		OwnerID:       ownerFilter(identity),
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		Top:           templateStatsTop,
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed to get template stats: %v", err))
+		return nil, err
+	}
+	return converter.DbTemplateStatsToGraphql(stats), nil
+}
@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	dbRepo "go-init/internal/database"
+	"go-init/internal/graphql/converter"
+	"go-init/internal/validation"
+	"go-init/pkg/api/graphql/model"
+)
+
+// UpdatePreset changes preset fields. A new template is stored as the next version of the preset,
+// earlier versions stay available through preset(id, version).
+func (s *Service) UpdatePreset(ctx context.Context, id string, input model.UpdatePresetInput) (*model.PresetResponse, error) {
+	preset, identity, err := s.findManagedPreset(ctx, id)
+	if err != nil {
+		return presetFailure(err), nil
+	}
+
+	// Видимость и команда проверяются в том виде, который пресет получит после изменения
+	visibility := model.PresetVisibility(converter.StringValue(preset.Visibility, dbRepo.PresetVisibilityPrivate))
+	if input.Visibility != nil {
+		visibility = *input.Visibility
+	}
+	team := preset.Team
+	if input.Team != nil {
+		team = normalizeTeam(input.Team)
+	}
+	if err := validation.UpdatePresetInput(input, visibility, team); err != nil {
+		return nil, validationFailed(ctx, err)
+	}
+	if input.Visibility != nil || input.Team != nil {
+		if err := checkPresetTeam(identity, visibility, team); err != nil {
+			return presetFailure(err), nil
+		}
+	}
+
+	update := dbRepo.PresetUpdate{
+		Description: input.Description,
+		UpdatedBy:   identity.UserID,
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		update.Name = &name
+	}
+	if input.Team != nil {
+		update.Team = strPtr(converter.StringValue(team, ""))
+	}
+	if input.Visibility != nil {
+		update.Visibility = strPtr(input.Visibility.String())
+	}
+	if input.Template != nil {
+		update.Input, err = converter.TemplateInputToJSON(input.Template)
+		if err != nil {
+			return presetFailure(err), nil
+		}
+	}
+
+	updated, err := s.presetRepo.UpdatePreset(ctx, *preset.PresetId, update)
+	if err != nil {
+		return presetFailure(err), nil
+	}
+	presetVersion, err := s.presetRepo.GetPresetVersion(ctx, *updated.PresetId, updated.Version)
+	if err != nil {
+		return presetFailure(err), nil
+	}
+
+	s.logger.Info(fmt.Sprintf("Preset %s updated by %s, current version %d", updated.PresetUuid, identity.UserID, updated.Version))
+	return presetResponse(updated, presetVersion, "Preset updated successfully"), nil
+}
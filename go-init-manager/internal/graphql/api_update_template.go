@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go-init/internal/auth"
+	dbRepo "go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+	"go-init/internal/graphql/converter"
+	"go-init/internal/validation"
+	"go-init/pkg/api/graphql/model"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// updateEventStatus marks process-template events sent by updateTemplate
+const updateEventStatus = "updated"
+
+// UpdateTemplate applies the patch to the configuration of a finished template and stores it as a new revision.
+// The template goes back to PENDING and is regenerated under a new archive ID, so archives of earlier
+// revisions stay in the publisher storage and remain downloadable through ServiceTemplate.revisions.
+// A regeneration counts against the template creation limits; a rejected update is refunded.
+func (s *Service) UpdateTemplate(ctx context.Context, id string, patch model.TemplatePatch) (*model.TemplateResponse, error) {
+	identity, err := auth.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := s.findTemplate(ctx, id)
+	if err != nil {
+		return templateLookupFailure(err), nil
+	}
+
+	input := converter.ApplyTemplatePatch(converter.DbTemplateToInput(template), patch)
+	if err := validation.CreateTemplateInput(input, "patch"); err != nil {
+		return nil, validationFailed(ctx, err)
+	}
+
+	// Регенерация нагружает генератор так же, как новый шаблон, и расходует ту же квоту
+	refund, err := s.limiter.AllowTemplateCreation(ctx, identity.UserID)
+	if err != nil {
+		return nil, rateLimited(ctx, err)
+	}
+
+	revision, err := s.reviseTemplateWithEvent(ctx, template, input, identity.UserID)
+	if err != nil {
+		refund()
+		message := "Failed to update template: " + err.Error()
+		switch {
+		case errors.Is(err, dbRepo.ErrTemplateActive):
+			message = "Template cannot be updated while it is being generated, cancel it first"
+		case errors.Is(err, dbRepo.ErrRevisionConflict):
+			message = "Template was changed by another request, reload it and try again"
+		}
+		return &model.TemplateResponse{
+			Success: false,
+			Message: &message,
+		}, nil
+	}
+
+	s.logger.Info(fmt.Sprintf("Template %s updated to revision %d, archive %s",
+		template.ServiceTemplateUuid, revision.Revision, revision.ArchiveUuid))
+
+	template, err = s.dbManagerRepo.GetTemplateByID(ctx, *template.ServiceTemplateId)
+	if err != nil {
+		return templateLookupFailure(err), nil
+	}
+
+	response := createSuccessResponse(template)
+	response.Message = strPtr(fmt.Sprintf("Template revision %d created, regeneration enqueued", revision.Revision))
+	return response, nil
+}
+
+// reviseTemplateWithEvent stores the new revision and its process-template event in one transaction
+func (s *Service) reviseTemplateWithEvent(
+	ctx context.Context,
+	template *dbModel.ServiceTemplate,
+	input model.CreateTemplateInput,
+	userID uuid.UUID,
+) (*dbModel.TemplateRevision, error) {
+	changed, err := converter.FromInputToDbServiceTemplate(ctx, input, userID, s.logger)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := converter.NewTemplateRevision(input, template.Revision+1, uuid.New(), &userID)
+	if err != nil {
+		return nil, err
+	}
+	initial, err := converter.CurrentTemplateRevision(template)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		change := dbRepo.TemplateRevisionChange{
+			TemplateID:   *template.ServiceTemplateId,
+			BaseRevision: template.Revision,
+			Template:     changed,
+			Revision:     revision,
+			Initial:      initial,
+			Source:       s.serviceName,
+		}
+		if err := s.dbManagerRepo.ReviseTemplate(ctx, change, tx); err != nil {
+			return err
+		}
+
+		ev := converter.FromInputToEvent(input, *revision.ArchiveUuid)
+		ev.Status = updateEventStatus
+		return s.ProduceEvent(ctx, tx, &ev)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revision, nil
+}
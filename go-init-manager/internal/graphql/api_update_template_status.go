@@ -4,25 +4,28 @@ import (
 	"context"
 	"fmt"
 
+	dbRepo "go-init/internal/database"
 	"go-init/internal/graphql/converter"
 	"go-init/pkg/api/graphql/model"
 
 	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
 	"gitlab.com/go-init/go-init-common/default/logger"
 )
 
 // Template status constants
 const (
-	StatusPending    = "PENDING"
-	StatusProcessing = "PROCESSING"
-	StatusCompleted  = "COMPLETED"
-	StatusFailed     = "FAILED"
+	StatusPending    = dbRepo.StatusPending
+	StatusProcessing = dbRepo.StatusProcessing
+	StatusCompleted  = dbRepo.StatusCompleted
+	StatusFailed     = dbRepo.StatusFailed
+	StatusCancelled  = dbRepo.StatusCancelled
 )
 
 // UpdateTemplateStatus updates the status of a template by UUID and returns a response
 func (s *Service) UpdateTemplateStatus(ctx context.Context, templateUUID uuid.UUID, newStatus string) (*model.TemplateResponse, error) {
 	// Validate status
-	if !isValidStatus(newStatus) {
+	if !IsValidStatus(newStatus) {
 		return &model.TemplateResponse{
 			Success: false,
 			Message: strPtr(fmt.Sprintf("Invalid status: %s", newStatus)),
@@ -30,7 +33,9 @@ func (s *Service) UpdateTemplateStatus(ctx context.Context, templateUUID uuid.UU
 	}
 
 	// Update status in the database
-	err := s.dbManagerRepo.UpdateTemplateStatusByUUID(ctx, templateUUID, newStatus)
+	err := s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		return s.dbManagerRepo.UpdateTemplateStatusByUUID(ctx, templateUUID, newStatus, s.serviceName, tx)
+	})
 	if err != nil {
 		return &model.TemplateResponse{
 			Success: false,
@@ -57,10 +62,10 @@ func (s *Service) UpdateTemplateStatus(ctx context.Context, templateUUID uuid.UU
 	}, nil
 }
 
-// isValidStatus checks if the provided status is valid according to the TemplateStatus enum
-func isValidStatus(status string) bool {
+// IsValidStatus checks if the provided status is valid according to the TemplateStatus enum
+func IsValidStatus(status string) bool {
 	switch status {
-	case StatusPending, StatusProcessing, StatusCompleted, StatusFailed:
+	case StatusPending, StatusProcessing, StatusCompleted, StatusFailed, StatusCancelled:
 		return true
 	default:
 		return false
@@ -81,15 +86,25 @@ func (s *Service) MarkTemplateAsCompleted(ctx context.Context, templateUUID uuid
 
 // MarkTemplateAsFailed sets a template's status to FAILED with an optional error message
 func (s *Service) MarkTemplateAsFailed(ctx context.Context, templateUUID uuid.UUID, errorMsg string) (*model.TemplateResponse, error) {
-	// First update the error message if provided
-	if errorMsg != "" {
-		if err := s.dbManagerRepo.UpdateTemplateErrorByUUID(ctx, templateUUID, errorMsg); err != nil {
-			s.logger.WarnContext(ctx, "Failed to update template error message, continuing with status update",
-				logger.String("template_uuid", templateUUID.String()),
-				logger.Error(err))
-		}
+	// First update the status so a rejected transition doesn't overwrite the error of a finished template
+	response, err := s.UpdateTemplateStatus(ctx, templateUUID, StatusFailed)
+	if err != nil || response == nil || !response.Success || errorMsg == "" {
+		return response, err
 	}
 
-	// Then update the status
-	return s.UpdateTemplateStatus(ctx, templateUUID, StatusFailed)
+	// Then store the error message
+	err = s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		return s.dbManagerRepo.UpdateTemplateErrorByUUID(ctx, templateUUID, errorMsg, tx)
+	})
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to update template error message",
+			logger.String("template_uuid", templateUUID.String()),
+			logger.Error(err))
+		return response, nil
+	}
+
+	if response.Template != nil {
+		response.Template.Error = &errorMsg
+	}
+	return response, nil
 }
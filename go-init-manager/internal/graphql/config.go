@@ -0,0 +1,11 @@
+package graphql
+
+import "time"
+
+// Config настройки работы с шаблонами
+type Config struct {
+	// MaxAttempts сколько раз шаблон может быть сгенерирован, включая первую попытку
+	MaxAttempts int `yaml:"max_attempts" default:"3"`
+	// DownloadURLRefreshBefore за сколько до истечения ссылки на архив запрашивается новая
+	DownloadURLRefreshBefore time.Duration `yaml:"download_url_refresh_before" default:"10m"`
+}
@@ -17,7 +17,8 @@ func DbTemplateToGraphqlTemplate(dbTemplate *dbModels.ServiceTemplate) *model.Se
 	// Convert ID to string
 	id := strconv.Itoa(*dbTemplate.ServiceTemplateId)
 
-	// The zipUrl might be optional now in the GraphQL schema
+	// The zipUrl might be optional now in the GraphQL schema.
+	// Archives kept by the publisher have no stored URL: it is issued on demand from ArchiveBucket/ArchiveObject.
 	var zipURL *string
 	if dbTemplate.ZipURL != nil && *dbTemplate.ZipURL != "" {
 		strVal := *dbTemplate.ZipURL
@@ -38,28 +39,23 @@ func DbTemplateToGraphqlTemplate(dbTemplate *dbModels.ServiceTemplate) *model.Se
 
 	// Initialize minimum required fields
 	template := &model.ServiceTemplate{
-		ID:      id,
-		Name:    name,
-		ZipURL:  zipURL,
-		Version: version,
+		ID:       id,
+		Name:     name,
+		ZipURL:   zipURL,
+		Version:  version,
+		Revision: dbTemplate.Revision,
+		Attempts: dbTemplate.Attempts,
+	}
+	if dbTemplate.ArchiveObject != nil {
+		template.ArchiveObject = *dbTemplate.ArchiveObject
+		if dbTemplate.ArchiveBucket != nil {
+			template.ArchiveBucket = *dbTemplate.ArchiveBucket
+		}
 	}
 
 	// Convert status field if present
 	if dbTemplate.Status != nil {
-		switch *dbTemplate.Status {
-		case "PENDING", "pending":
-			pending := model.TemplateStatusPending
-			template.Status = &pending
-		case "PROCESSING", "processing":
-			processing := model.TemplateStatusProcessing
-			template.Status = &processing
-		case "COMPLETED", "completed":
-			completed := model.TemplateStatusCompleted
-			template.Status = &completed
-		case "FAILED", "failed":
-			failed := model.TemplateStatusFailed
-			template.Status = &failed
-		}
+		template.Status = DbStatusToGraphqlStatus(*dbTemplate.Status)
 	} else {
 		// Default status is PENDING if none specified
 		pending := model.TemplateStatusPending
@@ -111,6 +107,9 @@ func DbTemplateToGraphqlTemplate(dbTemplate *dbModels.ServiceTemplate) *model.Se
 					}
 				}
 
+				// Convert Config
+				endpointConfig.Config = configToEntries(decodeEndpointConfig(endpoint.Config))
+
 				template.Endpoints = append(template.Endpoints, endpointConfig)
 			}
 		}
@@ -138,6 +137,10 @@ func DbTemplateToGraphqlTemplate(dbTemplate *dbModels.ServiceTemplate) *model.Se
 			databaseConfig.Ddl = dbConfig.DDL
 		}
 
+		// Convert generation flags
+		databaseConfig.Migrations = dbConfig.Migrations
+		databaseConfig.Models = dbConfig.Models
+
 		template.Database = databaseConfig
 	}
 
@@ -174,8 +177,71 @@ func DbTemplateToGraphqlTemplate(dbTemplate *dbModels.ServiceTemplate) *model.Se
 			advancedConfig.GenerateSwaggerDocs = dbAdvancedConfig.GenerateSwaggerDocs
 		}
 
+		// Convert generator options
+		advancedConfig.ModulePath = dbAdvancedConfig.ModulePath
+		advancedConfig.ServiceDescription = dbAdvancedConfig.ServiceDescription
+		advancedConfig.EnableGraphQL = dbAdvancedConfig.EnableGraphQL
+		advancedConfig.EnableGRPC = dbAdvancedConfig.EnableGRPC
+
 		template.Advanced = advancedConfig
 	}
 
+	// Convert status history - ordered by the repository
+	if len(dbTemplate.StatusHistory) > 0 {
+		template.StatusHistory = make([]*model.TemplateStatusChange, 0, len(dbTemplate.StatusHistory))
+		for _, entry := range dbTemplate.StatusHistory {
+			if change := dbStatusHistoryToGraphql(entry); change != nil {
+				template.StatusHistory = append(template.StatusHistory, change)
+			}
+		}
+	}
+
 	return template
 }
+
+// DbStatusToGraphqlStatus converts a stored template status to the GraphQL enum.
+// Returns nil for unknown statuses.
+func DbStatusToGraphqlStatus(status string) *model.TemplateStatus {
+	var result model.TemplateStatus
+	switch status {
+	case "PENDING", "pending":
+		result = model.TemplateStatusPending
+	case "PROCESSING", "processing":
+		result = model.TemplateStatusProcessing
+	case "COMPLETED", "completed":
+		result = model.TemplateStatusCompleted
+	case "FAILED", "failed":
+		result = model.TemplateStatusFailed
+	case "CANCELLED", "cancelled":
+		result = model.TemplateStatusCancelled
+	default:
+		return nil
+	}
+	return &result
+}
+
+// dbStatusHistoryToGraphql converts a status history row to a GraphQL status change
+func dbStatusHistoryToGraphql(entry *dbModels.TemplateStatusHistory) *model.TemplateStatusChange {
+	if entry == nil || entry.ToStatus == nil {
+		return nil
+	}
+
+	toStatus := DbStatusToGraphqlStatus(*entry.ToStatus)
+	if toStatus == nil {
+		return nil
+	}
+
+	change := &model.TemplateStatusChange{
+		ToStatus: *toStatus,
+	}
+	if entry.FromStatus != nil {
+		change.FromStatus = DbStatusToGraphqlStatus(*entry.FromStatus)
+	}
+	if entry.Source != nil {
+		change.Source = *entry.Source
+	}
+	if entry.CreatedAt != nil {
+		change.ChangedAt = entry.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return change
+}
@@ -0,0 +1,57 @@
+package converter
+
+import (
+	"encoding/json"
+	"sort"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+// ConfigEntriesToMap converts endpoint config entries to the key-value map sent to the generator
+func ConfigEntriesToMap(entries []*model.ConfigEntryInput) map[string]string {
+	if len(entries) == 0 {
+		return nil
+	}
+	config := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry != nil {
+			config[entry.Key] = entry.Value
+		}
+	}
+	return config
+}
+
+// configToEntries converts an endpoint config map to GraphQL entries sorted by key
+func configToEntries(config map[string]string) []*model.ConfigEntry {
+	if len(config) == 0 {
+		return nil
+	}
+	entries := make([]*model.ConfigEntry, 0, len(config))
+	for key, value := range config {
+		entries = append(entries, &model.ConfigEntry{Key: key, Value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries
+}
+
+// encodeEndpointConfig serializes an endpoint config for the jsonb column; an empty config is stored as NULL
+func encodeEndpointConfig(config map[string]string) []byte {
+	if len(config) == 0 {
+		return nil
+	}
+	// map[string]string всегда сериализуется без ошибок
+	data, _ := json.Marshal(config)
+	return data
+}
+
+// decodeEndpointConfig reads an endpoint config from the jsonb column
+func decodeEndpointConfig(data []byte) map[string]string {
+	if len(data) == 0 {
+		return nil
+	}
+	var config map[string]string
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil
+	}
+	return config
+}
@@ -1,6 +1,7 @@
 package converter
 
 import (
+	dbModels "go-init/internal/database/request_repo/models"
 	"go-init/internal/eventdata"
 	"go-init/pkg/api/graphql/model"
 
@@ -14,6 +15,7 @@ func ConvertToEndpointEventData(inputs []*model.EndpointInput) []*eventdata.Endp
 		eventData = append(eventData, &eventdata.EndpointEventData{
 			Protocol: input.Protocol.String(),
 			Role:     input.Role.String(),
+			Config:   ConfigEntriesToMap(input.Config),
 		})
 	}
 	return eventData
@@ -41,8 +43,10 @@ func FromInputToEvent(input model.CreateTemplateInput, requestUUID uuid.UUID) ev
 	if input.Database != nil {
 		dbType := input.Database.Type.String()
 		event.Data.Database = eventdata.DatabaseEventData{
-			Type: dbType,
-			DDL:  StringValue(input.Database.Ddl, ""),
+			Type:       dbType,
+			DDL:        StringValue(input.Database.Ddl, ""),
+			Migrations: BoolValue(input.Database.Migrations, false),
+			Models:     BoolValue(input.Database.Models, false),
 		}
 	} else {
 		// Default database type if not provided
@@ -71,6 +75,10 @@ func FromInputToEvent(input model.CreateTemplateInput, requestUUID uuid.UUID) ev
 		event.Data.Advanced = &eventdata.AdvancedEventData{
 			EnableAuthentication: BoolValue(input.Advanced.EnableAuthentication, false),
 			GenerateSwaggerDocs:  BoolValue(input.Advanced.GenerateSwaggerDocs, false),
+			ModulePath:           StringValue(input.Advanced.ModulePath, ""),
+			ServiceDescription:   StringValue(input.Advanced.ServiceDescription, ""),
+			EnableGraphQL:        BoolValue(input.Advanced.EnableGraphQL, false),
+			EnableGRPC:           BoolValue(input.Advanced.EnableGRPC, false),
 		}
 	} else {
 		// Default advanced config if not provided
@@ -83,6 +91,62 @@ func FromInputToEvent(input model.CreateTemplateInput, requestUUID uuid.UUID) ev
 	return event
 }
 
+// FromDbTemplateToEvent rebuilds the process-template event from a stored template and its configs,
+// so that a generation can be repeated without the original input. The event ID is the archive ID of the current revision.
+func FromDbTemplateToEvent(template *dbModels.ServiceTemplate, status string) eventdata.ProcessTemplate {
+	event := eventdata.ProcessTemplate{
+		ID:     ArchiveID(template).String(),
+		Status: status,
+		Data: eventdata.TemplateEventData{
+			Name: StringValue(template.ServiceTemplateName, ""),
+			Database: eventdata.DatabaseEventData{
+				Type: "NONE",
+			},
+			Advanced: &eventdata.AdvancedEventData{},
+		},
+	}
+
+	for _, endpoint := range template.Endpoints {
+		event.Data.Endpoints = append(event.Data.Endpoints, &eventdata.EndpointEventData{
+			Protocol: StringValue(endpoint.Protocol, ""),
+			Role:     StringValue(endpoint.Role, ""),
+			Config:   decodeEndpointConfig(endpoint.Config),
+		})
+	}
+
+	if len(template.DatabaseConfigs) > 0 {
+		database := template.DatabaseConfigs[0]
+		event.Data.Database = eventdata.DatabaseEventData{
+			Type:       StringValue(database.Type, "NONE"),
+			DDL:        StringValue(database.DDL, ""),
+			Migrations: BoolValue(database.Migrations, false),
+			Models:     BoolValue(database.Models, false),
+		}
+	}
+
+	if len(template.DockerConfigs) > 0 {
+		docker := template.DockerConfigs[0]
+		event.Data.Docker = eventdata.DockerEventData{
+			Registry:  StringValue(docker.Registry, ""),
+			ImageName: StringValue(docker.ImageName, ""),
+		}
+	}
+
+	if len(template.AdvancedConfigs) > 0 {
+		advanced := template.AdvancedConfigs[0]
+		event.Data.Advanced = &eventdata.AdvancedEventData{
+			EnableAuthentication: BoolValue(advanced.EnableAuthentication, false),
+			GenerateSwaggerDocs:  BoolValue(advanced.GenerateSwaggerDocs, false),
+			ModulePath:           StringValue(advanced.ModulePath, ""),
+			ServiceDescription:   StringValue(advanced.ServiceDescription, ""),
+			EnableGraphQL:        BoolValue(advanced.EnableGraphQL, false),
+			EnableGRPC:           BoolValue(advanced.EnableGRPC, false),
+		}
+	}
+
+	return event
+}
+
 // StringValue returns the value of the string pointer or a default value if nil.
 func StringValue(ptr *string, defaultValue string) string {
 	if ptr != nil {
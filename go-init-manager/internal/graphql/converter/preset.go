@@ -0,0 +1,120 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	dbModels "go-init/internal/database/request_repo/models"
+	"go-init/internal/validation"
+	"go-init/pkg/api/graphql/model"
+)
+
+// TemplateInputToJSON serializes the template parameters of a preset for storage
+func TemplateInputToJSON(input *model.CreateTemplateInput) ([]byte, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preset template: %w", err)
+	}
+	return data, nil
+}
+
+// MergePresetInput applies overrides to the stored preset parameters as a JSON merge patch (RFC 7386):
+// objects are merged recursively, arrays and scalar values are replaced and null removes a field.
+// Overrides that do not fit CreateTemplateInput are reported as validation errors under "overrides".
+func MergePresetInput(stored []byte, overrides map[string]any) (model.CreateTemplateInput, error) {
+	var input model.CreateTemplateInput
+
+	var base map[string]any
+	if err := json.Unmarshal(stored, &base); err != nil {
+		return input, fmt.Errorf("failed to decode preset template: %w", err)
+	}
+
+	merged, err := json.Marshal(mergePatch(base, overrides))
+	if err != nil {
+		return input, fmt.Errorf("failed to encode merged template: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		return input, overridesError(err)
+	}
+	return input, nil
+}
+
+// mergePatch merges patch into target following RFC 7386
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any, len(patchObject))
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// overridesError converts a decoding error of the merged template to a field error
+func overridesError(err error) error {
+	path := []any{"overrides"}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		for _, field := range strings.Split(typeErr.Field, ".") {
+			path = append(path, field)
+		}
+		return validation.Errors{{
+			Path:    path,
+			Code:    validation.CodeInvalidValue,
+			Message: fmt.Sprintf("expected %s, got %s", typeErr.Type, typeErr.Value),
+		}}
+	}
+
+	return validation.Errors{{
+		Path:    path,
+		Code:    validation.CodeInvalidValue,
+		Message: strings.TrimPrefix(err.Error(), "json: "),
+	}}
+}
+
+// DbPresetToGraphqlPreset converts a stored preset and one of its versions to the GraphQL model
+func DbPresetToGraphqlPreset(preset *dbModels.Preset, version *dbModels.PresetVersion) (*model.Preset, error) {
+	var template map[string]any
+	if err := json.Unmarshal(version.Input, &template); err != nil {
+		return nil, fmt.Errorf("failed to decode preset template: %w", err)
+	}
+
+	result := &model.Preset{
+		ID:            preset.PresetUuid.String(),
+		Name:          StringValue(preset.PresetName, ""),
+		Description:   preset.Description,
+		Team:          preset.Team,
+		Visibility:    model.PresetVisibility(StringValue(preset.Visibility, string(model.PresetVisibilityPrivate))),
+		Version:       version.Version,
+		LatestVersion: preset.Version,
+		Template:      template,
+	}
+	if preset.OwnerId != nil {
+		result.OwnerID = preset.OwnerId.String()
+	}
+	if preset.CreatedAt != nil {
+		result.CreatedAt = preset.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	if preset.UpdatedAt != nil {
+		updatedAt := preset.UpdatedAt.Format("2006-01-02T15:04:05Z")
+		result.UpdatedAt = &updatedAt
+	}
+	return result, nil
+}
@@ -0,0 +1,226 @@
+package converter
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-init/internal/validation"
+	"go-init/pkg/api/graphql/model"
+)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// presetTemplate is the stored preset the merge tests start from
+func presetTemplate() *model.CreateTemplateInput {
+	return &model.CreateTemplateInput{
+		Name: "orders",
+		Endpoints: []*model.EndpointInput{
+			{Protocol: model.ServiceProtocolGrpc, Role: model.ServiceRoleServer},
+			{
+				Protocol: model.ServiceProtocolRest,
+				Role:     model.ServiceRoleClient,
+				Config:   []*model.ConfigEntryInput{{Key: "baseUrl", Value: "http://billing"}},
+			},
+		},
+		Database: &model.DatabaseInput{
+			Type:       model.DatabaseTypePostgresql,
+			Ddl:        ptr("CREATE TABLE orders (id BIGINT);"),
+			Migrations: ptr(true),
+		},
+		Docker: &model.DockerInput{Registry: ptr("registry.local"), ImageName: "orders"},
+		Advanced: &model.AdvancedInput{
+			EnableAuthentication: ptr(true),
+			ModulePath:           ptr("github.com/acme/orders"),
+		},
+	}
+}
+
+func TestMergePresetInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		overrides map[string]any
+		// want modifies the stored preset into the expected result
+		want func(input *model.CreateTemplateInput)
+	}{
+		{
+			name:      "no overrides",
+			overrides: nil,
+			want:      func(*model.CreateTemplateInput) {},
+		},
+		{
+			name:      "scalar is replaced",
+			overrides: map[string]any{"name": "payments"},
+			want: func(input *model.CreateTemplateInput) {
+				input.Name = "payments"
+			},
+		},
+		{
+			name:      "objects are merged",
+			overrides: map[string]any{"docker": map[string]any{"registry": "ghcr.io/acme"}},
+			want: func(input *model.CreateTemplateInput) {
+				input.Docker.Registry = ptr("ghcr.io/acme")
+			},
+		},
+		{
+			name: "nested objects are merged field by field",
+			overrides: map[string]any{
+				"database": map[string]any{"type": "MYSQL", "models": true},
+				"advanced": map[string]any{"enableGRPC": true},
+			},
+			want: func(input *model.CreateTemplateInput) {
+				input.Database.Type = model.DatabaseTypeMysql
+				input.Database.Models = ptr(true)
+				input.Advanced.EnableGRPC = ptr(true)
+			},
+		},
+		{
+			name: "lists are replaced",
+			overrides: map[string]any{"endpoints": []any{
+				map[string]any{"protocol": "GRAPHQL", "role": "SERVER"},
+			}},
+			want: func(input *model.CreateTemplateInput) {
+				input.Endpoints = []*model.EndpointInput{
+					{Protocol: model.ServiceProtocolGraphql, Role: model.ServiceRoleServer},
+				}
+			},
+		},
+		{
+			name:      "empty list clears the list",
+			overrides: map[string]any{"endpoints": []any{}},
+			want: func(input *model.CreateTemplateInput) {
+				input.Endpoints = []*model.EndpointInput{}
+			},
+		},
+		{
+			name:      "null removes a field",
+			overrides: map[string]any{"advanced": map[string]any{"modulePath": nil}},
+			want: func(input *model.CreateTemplateInput) {
+				input.Advanced.ModulePath = nil
+			},
+		},
+		{
+			name:      "null removes an object",
+			overrides: map[string]any{"database": nil},
+			want: func(input *model.CreateTemplateInput) {
+				input.Database = nil
+			},
+		},
+		{
+			name:      "null of a missing field is a no-op",
+			overrides: map[string]any{"docker": map[string]any{"tag": nil}},
+			want:      func(*model.CreateTemplateInput) {},
+		},
+		{
+			name:      "null and values in one object",
+			overrides: map[string]any{"database": map[string]any{"ddl": nil, "type": "NONE"}},
+			want: func(input *model.CreateTemplateInput) {
+				input.Database.Type = model.DatabaseTypeNone
+				input.Database.Ddl = nil
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stored, err := TemplateInputToJSON(presetTemplate())
+			if err != nil {
+				t.Fatalf("TemplateInputToJSON failed: %v", err)
+			}
+
+			got, err := MergePresetInput(stored, tc.overrides)
+			if err != nil {
+				t.Fatalf("MergePresetInput failed: %v", err)
+			}
+
+			want := presetTemplate()
+			tc.want(want)
+			if !reflect.DeepEqual(got, *want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(want)
+				t.Fatalf("MergePresetInput =\n%s\nwant\n%s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestMergePresetInputMissingObject(t *testing.T) {
+	stored, err := TemplateInputToJSON(&model.CreateTemplateInput{Name: "bare"})
+	if err != nil {
+		t.Fatalf("TemplateInputToJSON failed: %v", err)
+	}
+
+	// An override of an object the preset does not have creates it
+	got, err := MergePresetInput(stored, map[string]any{"docker": map[string]any{"imageName": "bare"}})
+	if err != nil {
+		t.Fatalf("MergePresetInput failed: %v", err)
+	}
+	want := model.CreateTemplateInput{Name: "bare", Docker: &model.DockerInput{ImageName: "bare"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MergePresetInput = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergePresetInputInvalidOverrides(t *testing.T) {
+	testCases := []struct {
+		name      string
+		overrides map[string]any
+		wantPath  []any
+	}{
+		{
+			name:      "unknown field",
+			overrides: map[string]any{"docker": map[string]any{"tag": "latest"}},
+			wantPath:  []any{"overrides"},
+		},
+		{
+			name:      "wrong type",
+			overrides: map[string]any{"name": 42},
+			wantPath:  []any{"overrides", "name"},
+		},
+		{
+			name:      "wrong nested type",
+			overrides: map[string]any{"advanced": map[string]any{"enableGRPC": "yes"}},
+			wantPath:  []any{"overrides", "advanced", "enableGRPC"},
+		},
+		{
+			name:      "object where a list is expected",
+			overrides: map[string]any{"endpoints": map[string]any{"protocol": "GRPC"}},
+			wantPath:  []any{"overrides", "endpoints"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stored, err := TemplateInputToJSON(presetTemplate())
+			if err != nil {
+				t.Fatalf("TemplateInputToJSON failed: %v", err)
+			}
+
+			_, err = MergePresetInput(stored, tc.overrides)
+			var fieldErrs validation.Errors
+			if !errors.As(err, &fieldErrs) || len(fieldErrs) != 1 {
+				t.Fatalf("MergePresetInput error = %v, want one validation error", err)
+			}
+			if fieldErrs[0].Code != validation.CodeInvalidValue {
+				t.Fatalf("Error code = %s, want %s", fieldErrs[0].Code, validation.CodeInvalidValue)
+			}
+			if !reflect.DeepEqual(fieldErrs[0].Path, tc.wantPath) {
+				t.Fatalf("Error path = %v, want %v", fieldErrs[0].Path, tc.wantPath)
+			}
+		})
+	}
+}
+
+func TestMergePresetInputCorruptPreset(t *testing.T) {
+	_, err := MergePresetInput([]byte("{not json"), nil)
+	if err == nil {
+		t.Fatal("MergePresetInput accepted a corrupt preset")
+	}
+	var fieldErrs validation.Errors
+	if errors.As(err, &fieldErrs) {
+		t.Fatalf("Corrupt preset was reported as invalid overrides: %v", err)
+	}
+}
@@ -2,9 +2,11 @@ package converter
 
 import (
 	"context"
+	"strconv"
 
 	"gitlab.com/go-init/go-init-common/default/logger"
 
+	dbRepo "go-init/internal/database"
 	dbModel "go-init/internal/database/request_repo/models"
 
 	"go-init/pkg/api/graphql/model"
@@ -15,6 +17,7 @@ import (
 func FromInputToDbServiceTemplate(
 	ctx context.Context,
 	input model.CreateTemplateInput,
+	userID uuid.UUID,
 	log *logger.Logger,
 ) (*dbModel.ServiceTemplate, error) {
 	// Инициализируем пустой URL для архива
@@ -23,25 +26,28 @@ func FromInputToDbServiceTemplate(
 	// Create a new UUID for the template
 	templateUUID := uuid.New()
 
-	// TODO: In a production environment, you should extract the user ID from
-	// the authentication context. This is a temporary solution.
-	defaultUserID := uuid.New()
-	status := "pending"
+	status := dbRepo.StatusPending
 
 	// Name is required by schema, so we can directly use it
 	name := input.Name
 
-	// API версия - если поддерживается в GraphQL модели
-	version := "1.0" // Default version
+	// Шаблон создается первой ревизией; ее архив хранится под UUID шаблона
+	revision, err := NewTemplateRevision(input, 1, templateUUID, &userID)
+	if err != nil {
+		return nil, err
+	}
+	version := strconv.Itoa(revision.Revision)
 
 	// Create template with required fields
 	template := &dbModel.ServiceTemplate{
 		ServiceTemplateUuid: &templateUUID,
 		ServiceTemplateName: &name,
 		ZipURL:              &emptyURL,
-		UserId:              &defaultUserID,
+		UserId:              &userID,
 		Status:              &status,
+		Revision:            revision.Revision,
 		Version:             &version,
+		Revisions:           []*dbModel.TemplateRevision{revision},
 	}
 
 	// Conditionally add optional fields if they are provided
@@ -79,6 +85,7 @@ func convertEndpoints(inputs []*model.EndpointInput) []*dbModel.Endpoint {
 			endpoints = append(endpoints, &dbModel.Endpoint{
 				Protocol: &protocol,
 				Role:     &role,
+				Config:   encodeEndpointConfig(ConfigEntriesToMap(input.Config)),
 			})
 		}
 	}
@@ -92,8 +99,10 @@ func convertDatabase(input *model.DatabaseInput) []*dbModel.DatabaseConfig {
 	typeStr := input.Type.String()
 	return []*dbModel.DatabaseConfig{
 		{
-			Type: &typeStr,
-			DDL:  input.Ddl,
+			Type:       &typeStr,
+			DDL:        input.Ddl,
+			Migrations: input.Migrations,
+			Models:     input.Models,
 		},
 	}
 }
@@ -124,6 +133,10 @@ func convertAdvanced(input *model.AdvancedInput) []*dbModel.AdvancedConfig {
 		{
 			EnableAuthentication: input.EnableAuthentication,
 			GenerateSwaggerDocs:  input.GenerateSwaggerDocs,
+			ModulePath:           input.ModulePath,
+			ServiceDescription:   input.ServiceDescription,
+			EnableGraphQL:        input.EnableGraphQL,
+			EnableGRPC:           input.EnableGRPC,
 		},
 	}
 }
@@ -135,6 +148,7 @@ func ConvertToEndpointConfig(inputs []*model.EndpointInput) []*model.EndpointCon
 		configs = append(configs, &model.EndpointConfig{
 			Protocol: input.Protocol,
 			Role:     input.Role,
+			Config:   configToEntries(ConfigEntriesToMap(input.Config)),
 		})
 	}
 	return configs
@@ -146,8 +160,10 @@ func ConvertToDatabaseConfig(input *model.DatabaseInput) *model.DatabaseConfig {
 		return nil
 	}
 	return &model.DatabaseConfig{
-		Type: input.Type,
-		Ddl:  input.Ddl,
+		Type:       input.Type,
+		Ddl:        input.Ddl,
+		Migrations: input.Migrations,
+		Models:     input.Models,
 	}
 }
 
@@ -170,5 +186,9 @@ func ConvertToAdvancedConfig(input *model.AdvancedInput) *model.AdvancedConfig {
 	return &model.AdvancedConfig{
 		EnableAuthentication: input.EnableAuthentication,
 		GenerateSwaggerDocs:  input.GenerateSwaggerDocs,
+		ModulePath:           input.ModulePath,
+		ServiceDescription:   input.ServiceDescription,
+		EnableGraphQL:        input.EnableGraphQL,
+		EnableGRPC:           input.EnableGRPC,
 	}
 }
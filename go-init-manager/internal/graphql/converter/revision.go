@@ -0,0 +1,141 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	dbModels "go-init/internal/database/request_repo/models"
+	"go-init/pkg/api/graphql/model"
+
+	"github.com/google/uuid"
+)
+
+// ArchiveID returns the ID under which the current revision of the template is generated and archived.
+// Until the template is changed it is the template UUID.
+func ArchiveID(template *dbModels.ServiceTemplate) uuid.UUID {
+	if template.ArchiveUuid != nil {
+		return *template.ArchiveUuid
+	}
+	return *template.ServiceTemplateUuid
+}
+
+// DbTemplateToInput rebuilds the CreateTemplateInput of a stored template from its configs
+func DbTemplateToInput(template *dbModels.ServiceTemplate) model.CreateTemplateInput {
+	input := model.CreateTemplateInput{
+		Name: StringValue(template.ServiceTemplateName, ""),
+	}
+
+	for _, endpoint := range template.Endpoints {
+		endpointInput := &model.EndpointInput{
+			Protocol: model.ServiceProtocol(StringValue(endpoint.Protocol, "")),
+			Role:     model.ServiceRole(StringValue(endpoint.Role, "")),
+		}
+		for _, entry := range configToEntries(decodeEndpointConfig(endpoint.Config)) {
+			endpointInput.Config = append(endpointInput.Config, &model.ConfigEntryInput{Key: entry.Key, Value: entry.Value})
+		}
+		input.Endpoints = append(input.Endpoints, endpointInput)
+	}
+
+	if len(template.DatabaseConfigs) > 0 {
+		database := template.DatabaseConfigs[0]
+		input.Database = &model.DatabaseInput{
+			Type:       model.DatabaseType(StringValue(database.Type, string(model.DatabaseTypeNone))),
+			Ddl:        database.DDL,
+			Migrations: database.Migrations,
+			Models:     database.Models,
+		}
+	}
+
+	if len(template.DockerConfigs) > 0 {
+		docker := template.DockerConfigs[0]
+		input.Docker = &model.DockerInput{
+			Registry:  docker.Registry,
+			ImageName: StringValue(docker.ImageName, ""),
+		}
+	}
+
+	if len(template.AdvancedConfigs) > 0 {
+		advanced := template.AdvancedConfigs[0]
+		input.Advanced = &model.AdvancedInput{
+			EnableAuthentication: advanced.EnableAuthentication,
+			GenerateSwaggerDocs:  advanced.GenerateSwaggerDocs,
+			ModulePath:           advanced.ModulePath,
+			ServiceDescription:   advanced.ServiceDescription,
+			EnableGraphQL:        advanced.EnableGraphQL,
+			EnableGRPC:           advanced.EnableGRPC,
+		}
+	}
+
+	return input
+}
+
+// ApplyTemplatePatch replaces the sections of input that are set in patch
+func ApplyTemplatePatch(input model.CreateTemplateInput, patch model.TemplatePatch) model.CreateTemplateInput {
+	if patch.Name != nil {
+		input.Name = *patch.Name
+	}
+	if patch.Endpoints != nil {
+		input.Endpoints = patch.Endpoints
+	}
+	if patch.Database != nil {
+		input.Database = patch.Database
+	}
+	if patch.Docker != nil {
+		input.Docker = patch.Docker
+	}
+	if patch.Advanced != nil {
+		input.Advanced = patch.Advanced
+	}
+	return input
+}
+
+// NewTemplateRevision builds a revision snapshot of the input
+func NewTemplateRevision(input model.CreateTemplateInput, revision int, archiveID uuid.UUID, createdBy *uuid.UUID) (*dbModels.TemplateRevision, error) {
+	config, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template revision: %w", err)
+	}
+	return &dbModels.TemplateRevision{
+		Revision:    revision,
+		ArchiveUuid: &archiveID,
+		Config:      config,
+		CreatedBy:   createdBy,
+	}, nil
+}
+
+// CurrentTemplateRevision snapshots the current revision of a stored template with its archive.
+// It stands in for the revision row of templates created before revisions were recorded.
+func CurrentTemplateRevision(template *dbModels.ServiceTemplate) (*dbModels.TemplateRevision, error) {
+	revision, err := NewTemplateRevision(DbTemplateToInput(template), template.Revision, ArchiveID(template), nil)
+	if err != nil {
+		return nil, err
+	}
+	revision.TemplateId = *template.ServiceTemplateId
+	revision.ArchiveBucket = template.ArchiveBucket
+	revision.ArchiveObject = template.ArchiveObject
+	revision.CreatedAt = template.CreatedAt
+	return revision, nil
+}
+
+// DbRevisionToGraphqlRevision converts a stored revision to the GraphQL model
+func DbRevisionToGraphqlRevision(revision *dbModels.TemplateRevision) (*model.TemplateRevision, error) {
+	var config map[string]any
+	if err := json.Unmarshal(revision.Config, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode template revision %d: %w", revision.Revision, err)
+	}
+
+	result := &model.TemplateRevision{
+		Revision:      revision.Revision,
+		Config:        config,
+		ArchiveBucket: StringValue(revision.ArchiveBucket, ""),
+		ArchiveObject: StringValue(revision.ArchiveObject, ""),
+	}
+	if revision.CreatedBy != nil {
+		createdBy := revision.CreatedBy.String()
+		result.CreatedBy = &createdBy
+	}
+	if revision.CreatedAt != nil {
+		result.CreatedAt = revision.CreatedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return result, nil
+}
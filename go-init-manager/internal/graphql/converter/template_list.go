@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"fmt"
+	"time"
+
+	dbRepo "go-init/internal/database"
+	"go-init/pkg/api/graphql/model"
+)
+
+// FromGraphqlTemplateFilter converts the GraphQL listing filter to the repository filter
+func FromGraphqlTemplateFilter(filter *model.TemplateFilter) (dbRepo.TemplateListFilter, error) {
+	var result dbRepo.TemplateListFilter
+	if filter == nil {
+		return result, nil
+	}
+
+	if filter.Status != nil {
+		status := filter.Status.String()
+		result.Status = &status
+	}
+	result.NameContains = filter.NameContains
+	if filter.Protocol != nil {
+		protocol := filter.Protocol.String()
+		result.Protocol = &protocol
+	}
+	if filter.DatabaseType != nil {
+		databaseType := filter.DatabaseType.String()
+		result.DatabaseType = &databaseType
+	}
+
+	var err error
+	if result.CreatedAfter, err = parseFilterTime("createdAfter", filter.CreatedAfter); err != nil {
+		return result, err
+	}
+	if result.CreatedBefore, err = parseFilterTime("createdBefore", filter.CreatedBefore); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// FromGraphqlTemplateOrder converts the GraphQL ordering; templates are listed newest first by default
+func FromGraphqlTemplateOrder(order *model.TemplateOrder) (dbRepo.TemplateOrderField, bool) {
+	if order == nil {
+		return dbRepo.TemplateOrderByCreatedAt, true
+	}
+
+	field := dbRepo.TemplateOrderByCreatedAt
+	if order.Field == model.TemplateOrderFieldName {
+		field = dbRepo.TemplateOrderByName
+	}
+	return field, order.Direction == model.OrderDirectionDesc
+}
+
+func parseFilterTime(field string, value *string) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC3339 timestamp: %w", field, err)
+	}
+	return &parsed, nil
+}
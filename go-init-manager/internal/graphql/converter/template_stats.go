@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"errors"
+	"time"
+
+	dbRepo "go-init/internal/database"
+	"go-init/pkg/api/graphql/model"
+)
+
+// FromGraphqlStatsPeriod parses the [from, to) period of the template statistics
+func FromGraphqlStatsPeriod(from, to *string) (*time.Time, *time.Time, error) {
+	createdAfter, err := parseFilterTime("from", from)
+	if err != nil {
+		return nil, nil, err
+	}
+	createdBefore, err := parseFilterTime("to", to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if createdAfter != nil && createdBefore != nil && !createdAfter.Before(*createdBefore) {
+		return nil, nil, errors.New("from must be before to")
+	}
+	return createdAfter, createdBefore, nil
+}
+
+// DbTemplateStatsToGraphql converts the repository statistics to the GraphQL type.
+// Statuses are listed in lifecycle order, including the ones no template is in.
+func DbTemplateStatsToGraphql(stats *dbRepo.TemplateStats) *model.TemplateStats {
+	result := &model.TemplateStats{
+		StatusCounts:         make([]*model.TemplateStatusCount, 0, len(model.AllTemplateStatus)),
+		TopEndpointDatabases: make([]*model.EndpointDatabaseUsage, 0, len(stats.EndpointDatabase)),
+		DockerRegistries:     make([]*model.DockerRegistryUsage, 0, len(stats.DockerRegistries)),
+		TopErrors:            make([]*model.TemplateErrorCount, 0, len(stats.TopErrors)),
+	}
+
+	for _, status := range model.AllTemplateStatus {
+		count := int(stats.StatusCounts[status.String()])
+		result.StatusCounts = append(result.StatusCounts, &model.TemplateStatusCount{Status: status, Count: count})
+		result.Total += count
+	}
+	completed := stats.StatusCounts[dbRepo.StatusCompleted]
+	failed := stats.StatusCounts[dbRepo.StatusFailed]
+	if completed+failed > 0 {
+		result.FailureRate = float64(failed) / float64(completed+failed)
+	}
+
+	for _, row := range stats.EndpointDatabase {
+		usage := &model.EndpointDatabaseUsage{
+			Protocol:     model.ServiceProtocol(row.Protocol),
+			Role:         model.ServiceRole(row.Role),
+			DatabaseType: model.DatabaseType(row.DatabaseType),
+			Count:        int(row.Templates),
+		}
+		// Значения, которых нет в схеме, не могут быть сериализованы в enum
+		if !usage.Protocol.IsValid() || !usage.Role.IsValid() || !usage.DatabaseType.IsValid() {
+			continue
+		}
+		result.TopEndpointDatabases = append(result.TopEndpointDatabases, usage)
+	}
+
+	for _, row := range stats.DockerRegistries {
+		result.DockerRegistries = append(result.DockerRegistries, &model.DockerRegistryUsage{
+			Registry: row.Registry,
+			Count:    int(row.Templates),
+		})
+	}
+
+	if stats.Completion != nil {
+		average := stats.Completion.Average.Seconds()
+		p95 := stats.Completion.P95.Seconds()
+		result.AvgCompletionSeconds = &average
+		result.P95CompletionSeconds = &p95
+	}
+
+	for _, row := range stats.TopErrors {
+		result.TopErrors = append(result.TopErrors, &model.TemplateErrorCount{
+			Message: row.Message,
+			Count:   int(row.Templates),
+		})
+	}
+	return result
+}
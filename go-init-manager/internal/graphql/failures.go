@@ -0,0 +1,33 @@
+package graphql
+
+import "errors"
+
+// Причины неуспешных TemplateResponse (поле Reason); REST API переводит их в HTTP-статусы
+const (
+	FailureUnauthenticated = "UNAUTHENTICATED"
+	FailureInvalidID       = "INVALID_ID"
+	FailureNotFound        = "NOT_FOUND"
+	// Шаблон в состоянии, в котором операция невозможна
+	FailureConflict = "CONFLICT"
+	FailureInternal = "INTERNAL"
+)
+
+// ErrInvalidArgument matches errors caused by query arguments, such as a malformed cursor or filter
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// invalidArgumentError keeps the message of the wrapped error and matches ErrInvalidArgument
+type invalidArgumentError struct {
+	err error
+}
+
+func (e invalidArgumentError) Error() string {
+	return e.err.Error()
+}
+
+func (e invalidArgumentError) Unwrap() []error {
+	return []error{e.err, ErrInvalidArgument}
+}
+
+func invalidArgument(err error) error {
+	return invalidArgumentError{err: err}
+}
@@ -2,25 +2,40 @@ package graphql
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"go-init/internal/eventdata"
+	"go-init/internal/outbox"
 
 	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
 	common "gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/logger"
 )
 
-// ProduceEvent публикует событие обработки шаблона в Kafka
-func (s *Service) ProduceEvent(ctx context.Context, data *eventdata.ProcessTemplate) error {
-	if !s.KafkaProducer.IsEnabled() {
-		s.logger.InfoContext(ctx, "Kafka продюсер отключен, событие не будет отправлено")
-		return nil
-	}
+// ProduceEvent записывает событие обработки шаблона в outbox в рамках транзакции создания шаблона.
+// В Kafka его отправит outbox.Relay после коммита, поэтому событие не теряется при недоступной Kafka
+// и не уходит для откатившейся записи.
+func (s *Service) ProduceEvent(ctx context.Context, tx *orm.Transaction, data *eventdata.ProcessTemplate) error {
+	return s.addProcessingEvent(ctx, tx, eventdata.ProcessTemplateEventType, eventdata.JsonSchema, data.ID, data)
+}
+
+// produceCancelEvent записывает в outbox событие отмены генерации шаблона
+func (s *Service) produceCancelEvent(ctx context.Context, tx *orm.Transaction, templateUUID string, cancelledAt time.Time) error {
+	return s.addProcessingEvent(ctx, tx, eventdata.CancelTemplateEventType, eventdata.CancelTemplateSchema, templateUUID,
+		&eventdata.CancelTemplate{
+			ID:          templateUUID,
+			CancelledAt: cancelledAt.UTC().Format(time.RFC3339Nano),
+		})
+}
 
-	// Создаем событие для публикации
+// addProcessingEvent добавляет событие для генератора в outbox.
+// Ключом служит UUID шаблона, поэтому события одного шаблона доставляются по порядку.
+func (s *Service) addProcessingEvent(ctx context.Context, tx *orm.Transaction, eventType, schema, templateUUID string, data any) error {
 	event := common.ProduceEvent{
-		Type:    eventdata.ProcessTemplateEventType,
-		Schema:  eventdata.JsonSchema,
+		Type:    eventType,
+		Schema:  schema,
 		Source:  s.serviceName,
 		TopicID: eventdata.ProcessingTopicID,
 	}
@@ -30,13 +45,19 @@ func (s *Service) ProduceEvent(ctx context.Context, data *eventdata.ProcessTempl
 	event.SetCorrelationID(correlationID)
 	event.SetData(data)
 
-	// Публикуем событие в Kafka
-	s.logger.InfoContext(ctx, "Публикация события обработки шаблона",
-		logger.String("template_id", data.ID),
+	outboxEvent, err := outbox.NewEvent(ctx, &event, templateUUID)
+	if err != nil {
+		return fmt.Errorf("failed to build %s event: %w", eventType, err)
+	}
+	if err := s.outboxRepo.AddOutboxEvent(ctx, outboxEvent, tx); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Событие для генератора добавлено в outbox",
+		logger.String("type", eventType),
+		logger.String("template_id", templateUUID),
 		logger.String("correlation_id", correlationID),
 		logger.String("topic", eventdata.ProcessingTopicID))
 
-	s.KafkaProducer.Produce(ctx, &event)
-
 	return nil
 }
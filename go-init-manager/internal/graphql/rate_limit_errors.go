@@ -0,0 +1,37 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"go-init/internal/ratelimit"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// CodeRateLimited is extensions.code of errors returned when a rate limit or quota is exhausted
+const CodeRateLimited = "RATE_LIMITED"
+
+// rateLimited превращает *ratelimit.LimitError в ошибку GraphQL с подсказкой, когда повторить запрос.
+// Вне GraphQL-запроса (REST API) ошибка возвращается как есть.
+func rateLimited(ctx context.Context, err error) error {
+	var limitErr *ratelimit.LimitError
+	if !errors.As(err, &limitErr) || !graphql.HasOperationContext(ctx) {
+		return err
+	}
+	return RateLimitError(limitErr)
+}
+
+// RateLimitError describes an exhausted limit: extensions.retryAfter is the number of seconds
+// until the limit window ends, the same value as in the Retry-After header
+func RateLimitError(limitErr *ratelimit.LimitError) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message: limitErr.Error(),
+		Extensions: map[string]any{
+			"code":       CodeRateLimited,
+			"limit":      limitErr.Limit,
+			"retryAfter": limitErr.RetryAfterSeconds(),
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dbRepo "go-init/internal/database"
+	"go-init/internal/graphql/converter"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// ReapResult describes one sweep over stale templates
+type ReapResult struct {
+	// Locked is false when another replica held the sweep lock and nothing was done
+	Locked  bool
+	Failed  int
+	Retried int
+}
+
+// ReapStaleTemplates handles up to limit templates stuck in PENDING or PROCESSING longer than their timeout,
+// e.g. after a lost Kafka message or a generator crash. The stuck attempt is cancelled, then the template is
+// re-enqueued like retryTemplate under a new archive ID while its attempt number is below retryAttempts
+// and templates.max_attempts, otherwise it is marked FAILED.
+// Only one replica sweeps at a time; the whole batch is handled in one transaction.
+func (s *Service) ReapStaleTemplates(ctx context.Context, timeouts dbRepo.StaleTemplateTimeouts, retryAttempts, limit int) (ReapResult, error) {
+	var result ReapResult
+	source := s.serviceName + "/reaper"
+	maxAttempts := min(retryAttempts, s.cfg.MaxAttempts)
+
+	err := s.withTransaction(ctx, func(tx *orm.Transaction) error {
+		result = ReapResult{}
+		locked, err := s.dbManagerRepo.TryLockStaleTemplates(ctx, tx)
+		if err != nil || !locked {
+			return err
+		}
+		result.Locked = true
+
+		templates, err := s.dbManagerRepo.LockStaleTemplates(ctx, timeouts, limit, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, template := range templates {
+			status := dbRepo.NormalizeStatus(converter.StringValue(template.Status, ""))
+			timeout := timeouts[status]
+
+			// Генератор, который все еще работает над зависшей попыткой, прерывает ее
+			if err := s.produceCancelEvent(ctx, tx, converter.ArchiveID(template).String(), time.Now()); err != nil {
+				return err
+			}
+
+			if template.Attempts < maxAttempts {
+				retried, err := s.dbManagerRepo.ResetTemplateForRetry(ctx, *template.ServiceTemplateId, dbRepo.ActiveStatuses, maxAttempts, source, tx)
+				if err != nil {
+					return fmt.Errorf("failed to retry stale template %s: %w", template.ServiceTemplateUuid, err)
+				}
+				ev := converter.FromDbTemplateToEvent(retried, retryEventStatus)
+				if err := s.ProduceEvent(ctx, tx, &ev); err != nil {
+					return err
+				}
+				s.logger.Warn(fmt.Sprintf("Template %s timed out in %s after %s, re-enqueued (attempt %d of %d)",
+					template.ServiceTemplateUuid, status, timeout, retried.Attempts, maxAttempts))
+				result.Retried++
+				continue
+			}
+
+			message := fmt.Sprintf("Generation timed out: no progress in %s for %s", status, timeout)
+			if err := s.dbManagerRepo.FailTemplate(ctx, *template.ServiceTemplateId, message, source, tx); err != nil {
+				return fmt.Errorf("failed to fail stale template %s: %w", template.ServiceTemplateUuid, err)
+			}
+			s.logger.Warn(fmt.Sprintf("Template %s timed out in %s after %s, marked FAILED after %d attempts",
+				template.ServiceTemplateUuid, status, timeout, template.Attempts))
+			result.Failed++
+		}
+		return nil
+	})
+	if err != nil {
+		return ReapResult{}, err
+	}
+	return result, nil
+}
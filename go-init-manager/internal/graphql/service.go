@@ -2,32 +2,56 @@ package graphql
 
 import (
 	dbRepo "go-init/internal/database"
+	"go-init/internal/publisher"
+	"go-init/internal/ratelimit"
 
 	database "gitlab.com/go-init/go-init-common/default/db/pg/orm"
 
-	"gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/logger"
 )
 
 type Service struct {
+	cfg           *Config
 	logger        *logger.Logger
 	serviceName   string
 	agent         *database.AgentImpl
 	dbManagerRepo dbRepo.GoInitManagerRepository
-	KafkaProducer *kafka.ClientConfig
+	outboxRepo    dbRepo.OutboxRepository
+	// archiveDeletionRepo очередь удаления архивов удаленных шаблонов
+	archiveDeletionRepo dbRepo.ArchiveDeletionRepository
+	// presetRepo хранит пресеты шаблонов и их версии
+	presetRepo dbRepo.PresetRepository
+	// downloadURLs выдает ссылки на архивы; nil, если publisher не настроен
+	downloadURLs *publisher.DownloadURLs
+	// statusListener доставляет изменения шаблонов подписчикам GraphQL
+	statusListener *dbRepo.StatusListener
+	// limiter ограничивает создание шаблонов по пользователю и в целом
+	limiter *ratelimit.Limiter
 }
 
-func New(log *logger.Logger,
+func New(cfg *Config,
+	log *logger.Logger,
 	name string,
 	dbManagerRepo dbRepo.GoInitManagerRepository,
 	agent *database.AgentImpl,
-	KafkaProducer *kafka.ClientConfig,
+	outboxRepo dbRepo.OutboxRepository,
+	archiveDeletionRepo dbRepo.ArchiveDeletionRepository,
+	presetRepo dbRepo.PresetRepository,
+	downloadURLs *publisher.DownloadURLs,
+	statusListener *dbRepo.StatusListener,
+	limiter *ratelimit.Limiter,
 ) *Service {
 	return &Service{
-		logger:        log,
-		serviceName:   name,
-		dbManagerRepo: dbManagerRepo,
-		agent:         agent,
-		KafkaProducer: KafkaProducer,
+		cfg:                 cfg,
+		logger:              log,
+		serviceName:         name,
+		dbManagerRepo:       dbManagerRepo,
+		agent:               agent,
+		outboxRepo:          outboxRepo,
+		archiveDeletionRepo: archiveDeletionRepo,
+		presetRepo:          presetRepo,
+		downloadURLs:        downloadURLs,
+		statusListener:      statusListener,
+		limiter:             limiter,
 	}
 }
@@ -0,0 +1,14 @@
+package graphql
+
+import (
+	"context"
+
+	dbRepo "go-init/internal/database"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+)
+
+// withTransaction runs fn in a transaction, see dbRepo.WithTransaction
+func (s *Service) withTransaction(ctx context.Context, fn func(tx *orm.Transaction) error) error {
+	return dbRepo.WithTransaction(ctx, s.agent, fn)
+}
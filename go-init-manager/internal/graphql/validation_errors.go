@@ -0,0 +1,37 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+
+	"go-init/internal/validation"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// validationFailed превращает ошибки валидации в отдельные ошибки GraphQL с extensions.code
+// и extensions.path (путь к полю в аргументах). Все ошибки, кроме последней, добавляются в ответ
+// напрямую, последняя возвращается резолверу, поэтому клиент получает их все.
+// Вне GraphQL-запроса (REST API) ошибки возвращаются как есть.
+func validationFailed(ctx context.Context, err error) error {
+	var fieldErrs validation.Errors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) == 0 || !graphql.HasOperationContext(ctx) {
+		return err
+	}
+
+	for _, fieldErr := range fieldErrs[:len(fieldErrs)-1] {
+		graphql.AddError(ctx, fieldError(fieldErr))
+	}
+	return fieldError(fieldErrs[len(fieldErrs)-1])
+}
+
+func fieldError(fieldErr validation.FieldError) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message: fieldErr.Error(),
+		Extensions: map[string]any{
+			"code": fieldErr.Code,
+			"path": fieldErr.Path,
+		},
+	}
+}
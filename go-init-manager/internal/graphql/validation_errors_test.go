@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-init/internal/validation"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestValidationFailed(t *testing.T) {
+	fieldErrs := validation.Errors{
+		{Path: []any{"input", "name"}, Code: validation.CodeRequired, Message: "service name must not be empty"},
+		{Path: []any{"input", "endpoints", 1, "protocol"}, Code: validation.CodeDuplicateEndpoint, Message: "GRPC server is already declared by endpoints[0]"},
+	}
+
+	t.Run("every field error becomes a GraphQL error", func(t *testing.T) {
+		ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{})
+		ctx = graphql.WithResponseContext(ctx, graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+
+		err := validationFailed(ctx, fieldErrs)
+
+		var last *gqlerror.Error
+		if !errors.As(err, &last) {
+			t.Fatalf("validationFailed returned %T, want *gqlerror.Error", err)
+		}
+		added := graphql.GetErrors(ctx)
+		if len(added) != 1 {
+			t.Fatalf("Errors added to the response = %v, want the first field error", added)
+		}
+
+		want := []struct {
+			message    string
+			extensions map[string]any
+		}{
+			{
+				message:    "input.name: service name must not be empty",
+				extensions: map[string]any{"code": "REQUIRED", "path": []any{"input", "name"}},
+			},
+			{
+				message:    "input.endpoints[1].protocol: GRPC server is already declared by endpoints[0]",
+				extensions: map[string]any{"code": "DUPLICATE_ENDPOINT", "path": []any{"input", "endpoints", 1, "protocol"}},
+			},
+		}
+		for i, got := range []*gqlerror.Error{added[0], last} {
+			if got.Message != want[i].message {
+				t.Fatalf("Error %d message = %q, want %q", i, got.Message, want[i].message)
+			}
+			if !reflect.DeepEqual(got.Extensions, want[i].extensions) {
+				t.Fatalf("Error %d extensions = %v, want %v", i, got.Extensions, want[i].extensions)
+			}
+		}
+	})
+
+	t.Run("outside of a GraphQL request the error is returned as is", func(t *testing.T) {
+		if err := validationFailed(context.Background(), fieldErrs); !reflect.DeepEqual(err, error(fieldErrs)) {
+			t.Fatalf("validationFailed = %v, want the validation errors unchanged", err)
+		}
+	})
+
+	t.Run("other errors are returned as is", func(t *testing.T) {
+		ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{})
+		cause := errors.New("connection refused")
+		if err := validationFailed(ctx, cause); err != cause {
+			t.Fatalf("validationFailed = %v, want %v", err, cause)
+		}
+	})
+}
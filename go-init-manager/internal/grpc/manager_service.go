@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	dbRepo "go-init/internal/database"
+	"go-init/internal/graphql"
+	pb "go-init/pkg/api/grpc"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// defaultStatusSource используется, если клиент не указал источник обновления
+const defaultStatusSource = "grpc"
+
+// ManagerService реализует gRPC сервис ManagerService для синхронного
+// обновления статуса генерации шаблона из generator и publisher
+type ManagerService struct {
+	pb.UnimplementedManagerServiceServer
+	log        *logger.Logger
+	repository dbRepo.GoInitManagerRepository
+	agent      *orm.AgentImpl
+}
+
+// NewManagerService создает новый gRPC сервис менеджера
+func NewManagerService(log *logger.Logger, repository dbRepo.GoInitManagerRepository, agent *orm.AgentImpl) *ManagerService {
+	return &ManagerService{
+		log:        log,
+		repository: repository,
+		agent:      agent,
+	}
+}
+
+// UpdateGenerationStatus обновляет статус, URL архива и текст ошибки шаблона
+func (s *ManagerService) UpdateGenerationStatus(ctx context.Context, req *pb.UpdateGenerationStatusRequest) (*pb.UpdateGenerationStatusResponse, error) {
+	archiveID, err := uuid.Parse(req.GetRequestId())
+	if err != nil {
+		s.log.WarnContext(ctx, "Недействительный request_id в запросе обновления статуса",
+			logger.String("request_id", req.GetRequestId()),
+			logger.Error(err))
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request_id: %v", err)
+	}
+
+	// request_id - ID архива ревизии; прежние ревизии и попытки статус шаблона не меняют
+	ref, err := s.repository.ResolveArchiveID(ctx, archiveID)
+	if err != nil {
+		return nil, s.toStatusError(ctx, archiveID, "failed to find template", err)
+	}
+	switch {
+	case ref.Superseded:
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"archive %s of template %s belongs to an abandoned generation attempt", archiveID, ref.TemplateUUID)
+	case !ref.Current:
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"revision %d of template %s is no longer current", ref.Revision, ref.TemplateUUID)
+	}
+	templateUUID := ref.TemplateUUID
+
+	newStatus := strings.ToUpper(strings.TrimSpace(req.GetStatus()))
+	if !graphql.IsValidStatus(newStatus) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid status: %s", req.GetStatus())
+	}
+
+	s.log.InfoContext(ctx, "Обновление статуса генерации шаблона",
+		logger.String("template_uuid", templateUUID.String()),
+		logger.String("status", newStatus))
+
+	source := req.GetSource()
+	if source == "" {
+		source = defaultStatusSource
+	}
+
+	// Статус меняем первым и в одной транзакции с остальным:
+	// если переход запрещен, URL и текст ошибки не должны измениться
+	err = dbRepo.WithTransaction(ctx, s.agent, func(tx *orm.Transaction) error {
+		if err := s.repository.UpdateTemplateStatusByUUID(ctx, templateUUID, newStatus, source, tx); err != nil {
+			return s.toStatusError(ctx, templateUUID, "failed to update template status", err)
+		}
+
+		if req.GetZipUrl() != "" {
+			if err := s.repository.UpdateZipUrl(ctx, templateUUID, req.GetZipUrl(), tx); err != nil {
+				return s.toStatusError(ctx, templateUUID, "failed to update zip URL", err)
+			}
+		}
+
+		if req.GetError() != "" {
+			if err := s.repository.UpdateTemplateErrorByUUID(ctx, templateUUID, req.GetError(), tx); err != nil {
+				return s.toStatusError(ctx, templateUUID, "failed to update template error", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, s.toStatusError(ctx, templateUUID, "failed to commit template update", err)
+	}
+
+	return &pb.UpdateGenerationStatusResponse{
+		Success: true,
+		Message: "Template status updated to " + newStatus,
+	}, nil
+}
+
+// toStatusError логирует ошибку репозитория и преобразует ее в gRPC статус
+func (s *ManagerService) toStatusError(ctx context.Context, templateUUID uuid.UUID, msg string, err error) error {
+	s.log.ErrorContext(ctx, msg,
+		logger.String("template_uuid", templateUUID.String()),
+		logger.Error(err))
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Errorf(codes.NotFound, "template %s not found", templateUUID)
+	}
+	if errors.Is(err, dbRepo.ErrInvalidStatusTransition) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
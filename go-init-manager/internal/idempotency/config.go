@@ -0,0 +1,11 @@
+package idempotency
+
+import "time"
+
+// Config настройки учета обработанных событий Kafka
+type Config struct {
+	// Retention сколько хранится запись об обработанном событии; повтор события позже обрабатывается заново
+	Retention time.Duration `yaml:"retention" default:"168h"`
+	// CleanupInterval период удаления устаревших записей
+	CleanupInterval time.Duration `yaml:"cleanup_interval" default:"1h"`
+}
@@ -0,0 +1,11 @@
+package idempotency
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var duplicateEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "go_init_manager_duplicate_events_total",
+	Help: "Redelivered Kafka events skipped because they were already processed, by CloudEvent type.",
+}, []string{"type"})
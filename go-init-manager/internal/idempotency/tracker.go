@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-init/internal/database"
+
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// Tracker skips redelivered CloudEvents. An event is recorded in the transaction of the changes it causes:
+// a redelivery after that transaction commits is skipped, one after a rollback or a crash is processed again.
+// Records are kept for Config.Retention, which must exceed the longest redelivery delay of the consumer.
+type Tracker struct {
+	cfg  *Config
+	log  *logger.Logger
+	repo database.ProcessedEventRepository
+}
+
+// NewTracker creates a tracker over the processed_events table
+func NewTracker(cfg *Config, log *logger.Logger, repo database.ProcessedEventRepository) *Tracker {
+	return &Tracker{
+		cfg:  cfg,
+		log:  log,
+		repo: repo,
+	}
+}
+
+// Claim records the event within tx and reports whether it is processed for the first time
+func (t *Tracker) Claim(ctx context.Context, event database.ProcessedEvent, tx *orm.Transaction) (bool, error) {
+	claimed, err := t.repo.MarkEventProcessed(ctx, event, tx)
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		duplicateEvents.WithLabelValues(event.Type).Inc()
+	}
+	return claimed, nil
+}
+
+// Run removes records older than the retention until ctx is cancelled
+func (t *Tracker) Run(ctx context.Context) {
+	t.log.Info(fmt.Sprintf("Processed events cleanup started, keeping events for %s", t.cfg.Retention))
+
+	cleanup := time.NewTicker(t.cfg.CleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.log.Info("Processed events cleanup stopped")
+			return
+		case <-cleanup.C:
+			t.cleanup(ctx)
+		}
+	}
+}
+
+func (t *Tracker) cleanup(ctx context.Context) {
+	deleted, err := t.repo.DeleteProcessedEvents(ctx, time.Now().Add(-t.cfg.Retention))
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			t.log.Warn(fmt.Sprintf("Failed to clean up processed events: %v", err))
+		}
+		return
+	}
+	if deleted > 0 {
+		t.log.Debug(fmt.Sprintf("Removed %d processed event record(s)", deleted))
+	}
+}
@@ -3,33 +3,57 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
+	"go-init-observability/tracing"
 	dbRepo "go-init/internal/database"
+	"go-init/internal/deadletter"
 	"go-init/internal/eventdata"
 	"go-init/internal/graphql"
+	"go-init/internal/idempotency"
 
 	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/db/pg/orm"
 	"gitlab.com/go-init/go-init-common/default/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// ArchiveConsumerService обрабатывает сообщения Kafka для событий archive-ready
+// ArchiveConsumerService обрабатывает сообщения Kafka для событий archive-ready,
+// generation-started и generation-failed
 type ArchiveConsumerService struct {
 	log        *logger.Logger
 	repository dbRepo.GoInitManagerRepository
+	// deletions принимает архивы прежних попыток генерации, загруженные после повторной попытки
+	deletions dbRepo.ArchiveDeletionRepository
+	agent     *orm.AgentImpl
+	// tracker пропускает повторные доставки уже обработанных событий
+	tracker *idempotency.Tracker
 }
 
 // NewArchiveConsumerService создает новый сервис потребителя архивов
-func NewArchiveConsumerService(log *logger.Logger, repository dbRepo.GoInitManagerRepository) *ArchiveConsumerService {
+func NewArchiveConsumerService(
+	log *logger.Logger,
+	repository dbRepo.GoInitManagerRepository,
+	deletions dbRepo.ArchiveDeletionRepository,
+	agent *orm.AgentImpl,
+	tracker *idempotency.Tracker,
+) *ArchiveConsumerService {
 	return &ArchiveConsumerService{
 		log:        log,
 		repository: repository,
+		deletions:  deletions,
+		agent:      agent,
+		tracker:    tracker,
 	}
 }
 
-// Work реализует интерфейс ConsumerWorker для обработки сообщений из Kafka
-func (s *ArchiveConsumerService) Work(ctx context.Context, value []byte) error {
+// Work реализует интерфейс ConsumerWorker для обработки сообщений из Kafka.
+// Сообщения, которые невозможно разобрать, помечаются deadletter.ErrUnprocessable и не повторяются.
+// Обработка записывается в спан, продолжающий трассировку отправителя события
+func (s *ArchiveConsumerService) Work(ctx context.Context, value []byte) (err error) {
 	s.log.InfoContext(ctx, "Processing Kafka message",
 		logger.String("value", string(value)))
 
@@ -39,16 +63,148 @@ func (s *ArchiveConsumerService) Work(ctx context.Context, value []byte) error {
 		s.log.ErrorContext(ctx, "Ошибка парсинга CloudEvent",
 			logger.Error(err),
 			logger.String("raw_message", string(value)))
-		return fmt.Errorf("failed to parse CloudEvent: %w", err)
+		return deadletter.Unprocessable(fmt.Errorf("failed to parse CloudEvent: %w", err))
 	}
 
+	ctx, span := tracing.Start(cloudEvent.Extract(ctx), "manager.consume "+cloudEvent.Type,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("cloudevents.event_id", cloudEvent.ID),
+			attribute.String("cloudevents.event_source", cloudEvent.Source),
+		))
+	defer func() { tracing.End(span, err) }()
+
+	// Событие отмечается обработанным в той же транзакции, что и изменения шаблона
+	return dbRepo.WithTransaction(ctx, s.agent, func(tx *orm.Transaction) error {
+		if cloudEvent.ID == "" {
+			s.log.WarnContext(ctx, "CloudEvent без id, повторные доставки не отслеживаются",
+				logger.String("event_type", cloudEvent.Type),
+				logger.String("source", cloudEvent.Source))
+		} else {
+			claimed, err := s.tracker.Claim(ctx, dbRepo.ProcessedEvent{
+				Source: cloudEvent.Source,
+				ID:     cloudEvent.ID,
+				Type:   cloudEvent.Type,
+			}, tx)
+			if err != nil {
+				return err
+			}
+			if !claimed {
+				s.log.InfoContext(ctx, "Событие уже обработано, повторная доставка пропущена",
+					logger.String("event_id", cloudEvent.ID),
+					logger.String("event_type", cloudEvent.Type),
+					logger.String("source", cloudEvent.Source))
+				return nil
+			}
+		}
+
+		switch cloudEvent.Type {
+		case eventdata.GenerationStartedEventType:
+			return s.handleGenerationStarted(ctx, cloudEvent, tx)
+		case eventdata.GenerationFailedEventType:
+			return s.handleGenerationFailed(ctx, cloudEvent, tx)
+		default:
+			return s.handleArchiveReady(ctx, cloudEvent, tx)
+		}
+	})
+}
+
+// updateStatus применяет переход статуса шаблона.
+// Запрещенный переход (например, событие, пришедшее не по порядку) не считается ошибкой:
+// он логируется, а applied возвращается false.
+func (s *ArchiveConsumerService) updateStatus(
+	ctx context.Context,
+	templateUUID uuid.UUID,
+	newStatus string,
+	source string,
+	tx *orm.Transaction,
+) (bool, error) {
+	err := s.repository.UpdateTemplateStatusByUUID(ctx, templateUUID, newStatus, source, tx)
+	if err != nil {
+		if errors.Is(err, dbRepo.ErrInvalidStatusTransition) {
+			s.log.WarnContext(ctx, "Переход статуса шаблона отклонен, событие пропущено",
+				logger.String("template_uuid", templateUUID.String()),
+				logger.String("source", source),
+				logger.Error(err))
+			return false, nil
+		}
+		s.log.ErrorContext(ctx, "Не удалось обновить статус шаблона",
+			logger.Error(err),
+			logger.String("template_uuid", templateUUID.String()),
+			logger.String("status", newStatus))
+		return false, err
+	}
+
+	s.log.InfoContext(ctx, "Статус шаблона обновлен",
+		logger.String("template_uuid", templateUUID.String()),
+		logger.String("status", newStatus),
+		logger.String("source", source))
+	return true, nil
+}
+
+// handleGenerationStarted обрабатывает событие generation-started от генератора
+func (s *ArchiveConsumerService) handleGenerationStarted(ctx context.Context, cloudEvent eventdata.CloudEvent, tx *orm.Transaction) error {
+	var started eventdata.GenerationStarted
+	if err := json.Unmarshal(cloudEvent.Data, &started); err != nil {
+		s.log.ErrorContext(ctx, "Ошибка парсинга события generation-started",
+			logger.Error(err),
+			logger.String("data_field", string(cloudEvent.Data)))
+		return deadletter.Unprocessable(fmt.Errorf("failed to parse generation start: %w", err))
+	}
+
+	archiveID, err := uuid.Parse(started.ID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Недействительный UUID шаблона в событии generation-started",
+			logger.String("id", started.ID),
+			logger.Error(err))
+		return deadletter.Unprocessable(fmt.Errorf("invalid template UUID in generation start: %w", err))
+	}
+
+	ref, err := s.resolveArchive(ctx, archiveID, cloudEvent.Type)
+	if err != nil || !ref.Current {
+		return err
+	}
+
+	_, err = s.updateStatus(ctx, ref.TemplateUUID, graphql.StatusProcessing, cloudEvent.Source, tx)
+	return err
+}
+
+// resolveArchive находит ревизию шаблона по ID архива из события генерации.
+// События прежних ревизий и попыток не меняют статус шаблона: они логируются, а Current остается false.
+func (s *ArchiveConsumerService) resolveArchive(ctx context.Context, archiveID uuid.UUID, eventType string) (*dbRepo.ArchiveRef, error) {
+	ref, err := s.repository.ResolveArchiveID(ctx, archiveID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Не удалось найти шаблон по ID архива",
+			logger.String("archive_id", archiveID.String()),
+			logger.Error(err))
+		return nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(tracing.TemplateID(ref.TemplateUUID.String()))
+
+	switch {
+	case ref.Superseded:
+		s.log.WarnContext(ctx, "Событие относится к прежней попытке генерации шаблона, статус не меняется",
+			logger.String("event_type", eventType),
+			logger.String("template_uuid", ref.TemplateUUID.String()),
+			logger.String("archive_id", archiveID.String()))
+	case !ref.Current:
+		s.log.WarnContext(ctx, "Событие относится к прежней ревизии шаблона, статус не меняется",
+			logger.String("event_type", eventType),
+			logger.String("template_uuid", ref.TemplateUUID.String()),
+			logger.Int("revision", ref.Revision))
+	}
+	return ref, nil
+}
+
+// handleArchiveReady обрабатывает событие archive-ready от publisher
+func (s *ArchiveConsumerService) handleArchiveReady(ctx context.Context, cloudEvent eventdata.CloudEvent, tx *orm.Transaction) error {
 	// Парсим поле data, которое содержит метаданные архива
 	var metadata eventdata.ArchiveMetadata
 	if err := json.Unmarshal(cloudEvent.Data, &metadata); err != nil {
 		s.log.ErrorContext(ctx, "Ошибка парсинга метаданных архива",
 			logger.Error(err),
 			logger.String("data_field", string(cloudEvent.Data)))
-		return fmt.Errorf("failed to parse archive metadata: %w", err)
+		return deadletter.Unprocessable(fmt.Errorf("failed to parse archive metadata: %w", err))
 	}
 
 	s.log.InfoContext(ctx, "Обработка метаданных архива",
@@ -82,7 +238,7 @@ func (s *ArchiveConsumerService) Work(ctx context.Context, value []byte) error {
 			s.log.ErrorContext(ctx, "Недействительный UUID в имени объекта",
 				logger.String("object_name", metadata.ObjectName),
 				logger.Error(err))
-			return fmt.Errorf("invalid UUID in object name: %w", err)
+			return deadletter.Unprocessable(fmt.Errorf("invalid UUID in object name: %w", err))
 		}
 		s.log.InfoContext(ctx, "Успешно использован UUID из ObjectName",
 			logger.String("uuid", requestUUID.String()))
@@ -91,34 +247,115 @@ func (s *ArchiveConsumerService) Work(ctx context.Context, value []byte) error {
 	// Проверяем, что у нас есть действительный UUID
 	if requestUUID == uuid.Nil {
 		s.log.ErrorContext(ctx, "Не удалось получить UUID запроса ни из ID, ни из ObjectName")
-		return fmt.Errorf("failed to extract request UUID from message")
+		return deadletter.Unprocessable(errors.New("failed to extract request UUID from message"))
+	}
+
+	ref, err := s.resolveArchive(ctx, requestUUID, cloudEvent.Type)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ref.Superseded:
+		// Архив брошенной попытки никому не доступен и удаляется из хранилища publisher
+		if metadata.ObjectName == "" {
+			return nil
+		}
+		return s.deletions.AddArchiveDeletion(ctx, requestUUID, tx)
+	case !ref.Current:
+		// Архив прежней ревизии остается доступным через ServiceTemplate.revisions
+		if metadata.ObjectName == "" {
+			return nil
+		}
+		return s.repository.UpdateRevisionArchiveLocation(ctx, requestUUID, metadata.BucketName, metadata.ObjectName, tx)
 	}
+	templateUUID := ref.TemplateUUID
 
 	// Обновление статуса на COMPLETED
-	if err := s.repository.UpdateTemplateStatusByUUID(ctx, requestUUID, graphql.StatusCompleted); err != nil {
-		s.log.ErrorContext(ctx, "Не удалось обновить статус шаблона на COMPLETED",
-			logger.Error(err),
-			logger.String("template_uuid", requestUUID.String()))
+	applied, err := s.updateStatus(ctx, templateUUID, graphql.StatusCompleted, cloudEvent.Source, tx)
+	if err != nil || !applied {
 		return err
 	}
-	s.log.InfoContext(ctx, "Статус шаблона обновлен на COMPLETED",
-		logger.String("template_uuid", requestUUID.String()))
 
-	// Обновление URL архива
-	if metadata.PresignedURL != "" {
-		if err := s.repository.UpdateZipUrl(ctx, requestUUID, metadata.PresignedURL); err != nil {
+	// Сохраняем расположение архива: пресигнированная ссылка из события истекает,
+	// поэтому zipUrl выдается по запросу через publisher
+	switch {
+	case metadata.ObjectName != "":
+		if err := s.repository.UpdateArchiveLocation(ctx, templateUUID, metadata.BucketName, metadata.ObjectName, tx); err != nil {
+			s.log.ErrorContext(ctx, "Не удалось сохранить расположение архива шаблона",
+				logger.Error(err),
+				logger.String("template_uuid", templateUUID.String()),
+				logger.String("bucket", metadata.BucketName),
+				logger.String("object_name", metadata.ObjectName))
+			return err
+		}
+		s.log.InfoContext(ctx, "Расположение архива шаблона сохранено",
+			logger.String("template_uuid", templateUUID.String()),
+			logger.String("bucket", metadata.BucketName),
+			logger.String("object_name", metadata.ObjectName))
+	case metadata.PresignedURL != "":
+		// Событие без имени объекта: остается только сохранить ссылку как есть
+		if err := s.repository.UpdateZipUrl(ctx, templateUUID, metadata.PresignedURL, tx); err != nil {
 			s.log.ErrorContext(ctx, "Не удалось обновить URL архива шаблона",
 				logger.Error(err),
-				logger.String("template_uuid", requestUUID.String()),
+				logger.String("template_uuid", templateUUID.String()),
 				logger.String("presigned_url", metadata.PresignedURL))
 			return err
 		}
 		s.log.InfoContext(ctx, "URL архива шаблона обновлен",
-			logger.String("template_uuid", requestUUID.String()),
+			logger.String("template_uuid", templateUUID.String()),
 			logger.String("presigned_url", metadata.PresignedURL))
-	} else {
-		s.log.WarnContext(ctx, "Отсутствует presignedURL в метаданных архива",
-			logger.String("template_uuid", requestUUID.String()))
+	default:
+		s.log.WarnContext(ctx, "Отсутствует расположение архива в метаданных",
+			logger.String("template_uuid", templateUUID.String()))
+	}
+
+	return nil
+}
+
+// handleGenerationFailed обрабатывает событие generation-failed от генератора
+func (s *ArchiveConsumerService) handleGenerationFailed(ctx context.Context, cloudEvent eventdata.CloudEvent, tx *orm.Transaction) error {
+	var failure eventdata.GenerationFailed
+	if err := json.Unmarshal(cloudEvent.Data, &failure); err != nil {
+		s.log.ErrorContext(ctx, "Ошибка парсинга события generation-failed",
+			logger.Error(err),
+			logger.String("data_field", string(cloudEvent.Data)))
+		return deadletter.Unprocessable(fmt.Errorf("failed to parse generation failure: %w", err))
+	}
+
+	archiveID, err := uuid.Parse(failure.ID)
+	if err != nil {
+		s.log.ErrorContext(ctx, "Недействительный UUID шаблона в событии generation-failed",
+			logger.String("id", failure.ID),
+			logger.Error(err))
+		return deadletter.Unprocessable(fmt.Errorf("invalid template UUID in generation failure: %w", err))
+	}
+
+	ref, err := s.resolveArchive(ctx, archiveID, cloudEvent.Type)
+	if err != nil || !ref.Current {
+		return err
+	}
+	templateUUID := ref.TemplateUUID
+
+	errorMessage := failure.Error
+	if failure.Stage != "" {
+		errorMessage = fmt.Sprintf("%s: %s", failure.Stage, failure.Error)
+	}
+
+	s.log.WarnContext(ctx, "Генерация шаблона завершилась ошибкой",
+		logger.String("template_uuid", templateUUID.String()),
+		logger.String("stage", failure.Stage),
+		logger.String("error", failure.Error))
+
+	applied, err := s.updateStatus(ctx, templateUUID, graphql.StatusFailed, cloudEvent.Source, tx)
+	if err != nil || !applied {
+		return err
+	}
+
+	if err := s.repository.UpdateTemplateErrorByUUID(ctx, templateUUID, errorMessage, tx); err != nil {
+		s.log.ErrorContext(ctx, "Не удалось сохранить ошибку генерации шаблона",
+			logger.Error(err),
+			logger.String("template_uuid", templateUUID.String()))
+		return err
 	}
 
 	return nil
@@ -0,0 +1,18 @@
+package outbox
+
+import "time"
+
+// Config настройки relay, публикующего события из outbox в Kafka
+type Config struct {
+	// PollInterval период опроса таблицы outbox
+	PollInterval time.Duration `yaml:"poll_interval" default:"200ms"`
+	// BatchSize сколько событий забирается и публикуется за один проход
+	BatchSize int `yaml:"batch_size" default:"100"`
+	// PublishTimeout ожидание подтверждения от брокера
+	PublishTimeout time.Duration `yaml:"publish_timeout" default:"10s"`
+	// MinBackoff и MaxBackoff границы экспоненциальной задержки повторной отправки
+	MinBackoff time.Duration `yaml:"min_backoff" default:"1s"`
+	MaxBackoff time.Duration `yaml:"max_backoff" default:"1m"`
+	// Retention сколько хранить уже отправленные события
+	Retention time.Duration `yaml:"retention" default:"24h"`
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-init-observability/tracing"
+	dbModel "go-init/internal/database/request_repo/models"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/kafka"
+)
+
+// NewEvent serializes ev into the CloudEvent that kafka.ClientConfig.Produce would send
+// and wraps it in an outbox row. The event ID is fixed here, so retries deliver the same event.
+// The trace context of ctx travels in the event, so the consumer continues the trace of the request.
+func NewEvent(ctx context.Context, ev *kafka.ProduceEvent, aggregateID string) (*dbModel.OutboxEvent, error) {
+	eventID := uuid.New()
+
+	event := ce.NewEvent()
+	event.SetType(ev.Type)
+	event.SetExtension("correlation-id", ev.CorrelationID)
+	event.SetSource(ev.Source)
+	event.SetDataSchema(ev.Schema)
+	event.SetTime(time.Now())
+	event.SetID(eventID.String())
+	tracing.InjectEvent(ctx, &event)
+
+	if err := event.SetData(ce.ApplicationJSON, ev.Data); err != nil {
+		return nil, fmt.Errorf("failed to set event data: %w", err)
+	}
+	payload, err := event.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return &dbModel.OutboxEvent{
+		EventId:     &eventID,
+		AggregateId: &aggregateID,
+		TopicId:     &ev.TopicID,
+		EventType:   &ev.Type,
+		Payload:     payload,
+	}, nil
+}
@@ -0,0 +1,25 @@
+package outbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	pendingEvents = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "go_init_manager_outbox_pending_events",
+		Help: "Number of outbox events not yet published to Kafka.",
+	})
+	lagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "go_init_manager_outbox_lag_seconds",
+		Help: "Age of the oldest unpublished outbox event.",
+	})
+	publishedEvents = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_outbox_published_total",
+		Help: "Outbox events successfully published to Kafka.",
+	})
+	failedPublishes = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_outbox_publish_failures_total",
+		Help: "Failed attempts to publish outbox events; the events are retried.",
+	})
+)
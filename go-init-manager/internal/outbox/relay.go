@@ -0,0 +1,169 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-init/internal/backoff"
+	"go-init/internal/database"
+	dbModel "go-init/internal/database/request_repo/models"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"gitlab.com/go-init/go-init-common/default/kafka"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// statsInterval период обновления метрик отставания и очистки отправленных событий
+const statsInterval = 15 * time.Second
+
+// claimMargin запас аренды событий сверх publish_timeout на запись результата публикации
+const claimMargin = 30 * time.Second
+
+// Relay publishes outbox events to Kafka. Every manager replica may run one:
+// rows are claimed with SKIP LOCKED and leased for the time of publishing, so an event is published by a single replica at a time
+// and no transaction is held open while Kafka acknowledges the batch.
+// Delivery is at-least-once; the CloudEvent ID stays the same across retries.
+type Relay struct {
+	cfg    *Config
+	log    *logger.Logger
+	repo   database.OutboxRepository
+	client *kgo.Client
+	// topics maps topic IDs used in events to enabled topic names on the broker
+	topics map[string]string
+	// backoff delays republishing of failed events
+	backoff backoff.Exponential
+}
+
+// NewRelay creates a relay publishing through the producer of the shared Kafka client
+func NewRelay(cfg *Config, log *logger.Logger, repo database.OutboxRepository, kafkaCfg *kafka.Config, producer *kafka.ClientConfig) *Relay {
+	topics := make(map[string]string)
+	for _, topic := range kafkaCfg.ProducerConfig.Topic {
+		if topic.IsEnabled {
+			topics[topic.Id] = topic.Name
+		}
+	}
+
+	return &Relay{
+		cfg:     cfg,
+		log:     log,
+		repo:    repo,
+		client:  producer.Client,
+		topics:  topics,
+		backoff: backoff.Exponential{Min: cfg.MinBackoff, Max: cfg.MaxBackoff},
+	}
+}
+
+// Run publishes due events until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	r.log.Info(fmt.Sprintf("Outbox relay started, polling every %s", r.cfg.PollInterval))
+
+	poll := time.NewTicker(r.cfg.PollInterval)
+	defer poll.Stop()
+	stats := time.NewTicker(statsInterval)
+	defer stats.Stop()
+
+	r.updateStats(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Info("Outbox relay stopped")
+			return
+		case <-poll.C:
+			r.drain(ctx)
+		case <-stats.C:
+			r.updateStats(ctx)
+			r.cleanup(ctx)
+		}
+	}
+}
+
+// drain publishes batches until the backlog of due events is empty
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		processed, err := r.repo.PublishPending(ctx, r.cfg.BatchSize, r.cfg.PublishTimeout+claimMargin, r.publish, r.backoff.Delay)
+		if err != nil {
+			r.log.Error(fmt.Sprintf("Failed to publish outbox events: %v", err))
+			return
+		}
+		if processed < r.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// publish sends a batch synchronously and reports the outcome of every event
+func (r *Relay) publish(ctx context.Context, events []*dbModel.OutboxEvent) []error {
+	results := make([]error, len(events))
+	records := make([]*kgo.Record, 0, len(events))
+	indexes := make([]int, 0, len(events))
+
+	for i, event := range events {
+		topic, ok := r.topics[*event.TopicId]
+		if !ok {
+			results[i] = fmt.Errorf("topic %q is not configured or disabled", *event.TopicId)
+			continue
+		}
+		records = append(records, &kgo.Record{
+			Topic: topic,
+			// Events of one template stay in one partition and keep their order
+			Key:   []byte(*event.AggregateId),
+			Value: event.Payload,
+		})
+		indexes = append(indexes, i)
+	}
+
+	if len(records) > 0 {
+		publishCtx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+		defer cancel()
+
+		produced := r.client.ProduceSync(publishCtx, records...)
+		for j, result := range produced {
+			results[indexes[j]] = result.Err
+		}
+	}
+
+	for i, err := range results {
+		if err == nil {
+			publishedEvents.Inc()
+			continue
+		}
+		failedPublishes.Inc()
+		r.log.Warn(fmt.Sprintf("Failed to publish outbox event %s (%s), will retry: %v",
+			events[i].EventId, *events[i].EventType, err))
+	}
+	return results
+}
+
+func (r *Relay) updateStats(ctx context.Context) {
+	stats, err := r.repo.GetOutboxStats(ctx)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			r.log.Warn(fmt.Sprintf("Failed to collect outbox stats: %v", err))
+		}
+		return
+	}
+
+	pendingEvents.Set(float64(stats.Pending))
+	lag := time.Duration(0)
+	if stats.OldestPendingAt != nil {
+		lag = time.Since(*stats.OldestPendingAt)
+	}
+	lagSeconds.Set(lag.Seconds())
+
+	if stats.Pending > 0 {
+		r.log.Debug(fmt.Sprintf("Outbox backlog: %d event(s), lag %s", stats.Pending, lag.Round(time.Millisecond)))
+	}
+}
+
+func (r *Relay) cleanup(ctx context.Context) {
+	deleted, err := r.repo.DeleteSentOutboxEvents(ctx, time.Now().Add(-r.cfg.Retention))
+	if err != nil {
+		r.log.Warn(fmt.Sprintf("Failed to clean up outbox: %v", err))
+		return
+	}
+	if deleted > 0 {
+		r.log.Debug(fmt.Sprintf("Removed %d published outbox event(s)", deleted))
+	}
+}
@@ -0,0 +1,69 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	externalgrpc "go-init/pkg/api/grpc/external"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/grpcpkg"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// Client клиент gRPC сервиса publisher
+type Client struct {
+	client           *grpcpkg.GRPCClient
+	archivePublisher externalgrpc.ArchivePublisherClient
+	logger           *logger.Logger
+}
+
+// NewClient создает клиент; соединение устанавливается при первом запросе
+func NewClient(config grpcpkg.ClientConfig, log *logger.Logger) (*Client, error) {
+	client, err := grpcpkg.NewGRPCClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	return &Client{
+		client:           client,
+		archivePublisher: externalgrpc.NewArchivePublisherClient(client.GetConnection()),
+		logger:           log,
+	}, nil
+}
+
+// DeleteArchive удаляет архив шаблона из хранилища publisher. Отсутствующий архив ошибкой не считается.
+func (c *Client) DeleteArchive(ctx context.Context, archiveID uuid.UUID) error {
+	resp, err := c.archivePublisher.DeleteArchive(ctx, &externalgrpc.DeleteArchiveRequest{
+		ArchiveId: archiveID.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete archive %s: %w", archiveID, err)
+	}
+
+	c.logger.Info(fmt.Sprintf("Archive %s deleted by publisher (%s)", archiveID, resp.GetObjectName()))
+	return nil
+}
+
+// GetDownloadURL запрашивает пресигнированную ссылку на архив и время ее истечения
+func (c *Client) GetDownloadURL(ctx context.Context, bucket, object string) (string, time.Time, error) {
+	resp, err := c.archivePublisher.GetDownloadURL(ctx, &externalgrpc.GetDownloadURLRequest{
+		BucketName: bucket,
+		ObjectName: object,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to get download URL for %s/%s: %w", bucket, object, err)
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, resp.GetExpiresAt())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid download URL expiry %q: %w", resp.GetExpiresAt(), err)
+	}
+	return resp.GetUrl(), expiresAt, nil
+}
+
+// Close закрывает соединение с publisher
+func (c *Client) Close() error {
+	return c.client.GetConnection().Close()
+}
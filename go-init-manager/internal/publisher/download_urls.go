@@ -0,0 +1,65 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// downloadURLSource issues presigned download URLs; implemented by Client
+type downloadURLSource interface {
+	GetDownloadURL(ctx context.Context, bucket, object string) (string, time.Time, error)
+}
+
+type cachedURL struct {
+	url       string
+	expiresAt time.Time
+}
+
+// DownloadURLs caches presigned archive URLs issued by the publisher.
+// A URL is reused until RefreshBefore ahead of its expiry, so clients never get a link that is about to die.
+type DownloadURLs struct {
+	source        downloadURLSource
+	refreshBefore time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedURL
+}
+
+// NewDownloadURLs creates a cache in front of the publisher client
+func NewDownloadURLs(client *Client, refreshBefore time.Duration) *DownloadURLs {
+	return &DownloadURLs{
+		source:        client,
+		refreshBefore: refreshBefore,
+		cache:         make(map[string]cachedURL),
+	}
+}
+
+// DownloadURL returns a valid download URL of the archive stored as bucket/object
+func (d *DownloadURLs) DownloadURL(ctx context.Context, bucket, object string) (string, error) {
+	key := bucket + "/" + object
+
+	d.mu.Lock()
+	cached, ok := d.cache[key]
+	d.mu.Unlock()
+	if ok && time.Until(cached.expiresAt) > d.refreshBefore {
+		return cached.url, nil
+	}
+
+	url, expiresAt, err := d.source.GetDownloadURL(ctx, bucket, object)
+	if err != nil {
+		return "", err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Expired entries of deleted or no longer requested archives are dropped on every refresh
+	now := time.Now()
+	for k, entry := range d.cache {
+		if !entry.expiresAt.After(now) {
+			delete(d.cache, k)
+		}
+	}
+	d.cache[key] = cachedURL{url: url, expiresAt: expiresAt}
+	return url, nil
+}
@@ -0,0 +1,23 @@
+package ratelimit
+
+import "time"
+
+// Config лимиты запросов к API и созданий шаблонов. Счетчики хранятся в Postgres и общие для всех реплик.
+// Лимит 0 не применяется
+type Config struct {
+	// UserRequestsPerMinute запросы одного пользователя к GraphQL и REST API в минуту
+	UserRequestsPerMinute int `yaml:"user_requests_per_minute"`
+	// GlobalRequestsPerMinute запросы всех пользователей к API в минуту
+	GlobalRequestsPerMinute int `yaml:"global_requests_per_minute"`
+	// UserCreatesPerMinute шаблоны, создаваемые одним пользователем в минуту
+	UserCreatesPerMinute int `yaml:"user_creates_per_minute"`
+	// UserCreatesPerDay дневная квота шаблонов пользователя, сбрасывается в полночь UTC
+	UserCreatesPerDay int `yaml:"user_creates_per_day"`
+	// GlobalCreatesPerMinute шаблоны, создаваемые всеми пользователями в минуту
+	GlobalCreatesPerMinute int `yaml:"global_creates_per_minute"`
+	// GlobalCounterShards число строк, по которым разнесены глобальные счетчики, чтобы запросы
+	// разных реплик не ждали блокировку одной строки
+	GlobalCounterShards int `yaml:"global_counter_shards" default:"16"`
+	// CleanupInterval период удаления счетчиков истекших окон
+	CleanupInterval time.Duration `yaml:"cleanup_interval" default:"10m"`
+}
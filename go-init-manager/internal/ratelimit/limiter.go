@@ -0,0 +1,202 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"go-init/internal/database"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// Имена лимитов, они же ключи конфигурации и значения extensions.limit
+const (
+	LimitUserRequestsPerMinute   = "user_requests_per_minute"
+	LimitGlobalRequestsPerMinute = "global_requests_per_minute"
+	LimitUserCreatesPerMinute    = "user_creates_per_minute"
+	LimitUserCreatesPerDay       = "user_creates_per_day"
+	LimitGlobalCreatesPerMinute  = "global_creates_per_minute"
+)
+
+// LimitError is returned when a rate limit or quota is exhausted
+type LimitError struct {
+	Limit string
+	// RetryAfter is the time left until the window of the limit ends
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("rate limit %s exceeded, retry in %ds", e.Limit, e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds rounds RetryAfter up to whole seconds, as in the Retry-After header
+func (e *LimitError) RetryAfterSeconds() int {
+	return max(1, int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// limit is one fixed-window limit; subjectless limits are global
+type limit struct {
+	name    string
+	window  time.Duration
+	max     int
+	perUser bool
+}
+
+// Limiter enforces the limits of Config with fixed-window counters in Postgres.
+// Every check is one short transaction, so the limits hold across replicas.
+// When the counters cannot be updated the request is let through: limits protect the service, not replace it.
+type Limiter struct {
+	cfg  *Config
+	log  *logger.Logger
+	repo database.RateLimitRepository
+
+	requestLimits []limit
+	createLimits  []limit
+}
+
+// NewLimiter creates a limiter; limits set to 0 in cfg are not checked
+func NewLimiter(cfg *Config, log *logger.Logger, repo database.RateLimitRepository) *Limiter {
+	return &Limiter{
+		cfg:  cfg,
+		log:  log,
+		repo: repo,
+		requestLimits: activeLimits(
+			limit{LimitUserRequestsPerMinute, time.Minute, cfg.UserRequestsPerMinute, true},
+			limit{LimitGlobalRequestsPerMinute, time.Minute, cfg.GlobalRequestsPerMinute, false},
+		),
+		createLimits: activeLimits(
+			limit{LimitUserCreatesPerMinute, time.Minute, cfg.UserCreatesPerMinute, true},
+			limit{LimitUserCreatesPerDay, 24 * time.Hour, cfg.UserCreatesPerDay, true},
+			limit{LimitGlobalCreatesPerMinute, time.Minute, cfg.GlobalCreatesPerMinute, false},
+		),
+	}
+}
+
+func activeLimits(limits ...limit) []limit {
+	var active []limit
+	for _, l := range limits {
+		if l.max > 0 {
+			active = append(active, l)
+		}
+	}
+	return active
+}
+
+// AllowRequest counts an API request of subject (a user or a client address)
+func (l *Limiter) AllowRequest(ctx context.Context, subject string) error {
+	_, err := l.allow(ctx, subject, l.requestLimits)
+	return err
+}
+
+// AllowTemplateCreation counts a template created by the user. The caller runs refund when the template
+// is not created after all, so failed creations do not use up the quotas.
+func (l *Limiter) AllowTemplateCreation(ctx context.Context, userID uuid.UUID) (refund func(), err error) {
+	counted, err := l.allow(ctx, "user:"+userID.String(), l.createLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		if len(counted) == 0 {
+			return
+		}
+		if err := l.repo.RefundRateLimits(context.WithoutCancel(ctx), counted); err != nil {
+			counterFailures.Inc()
+			l.log.Warn(fmt.Sprintf("Failed to refund template creation limits: %v", err))
+		}
+	}, nil
+}
+
+// allow counts a hit in every limit or returns *LimitError for the first exhausted one.
+// It returns the counted counters, none when the check failed and the request was let through.
+func (l *Limiter) allow(ctx context.Context, subject string, limits []limit) ([]database.RateLimitCounter, error) {
+	if len(limits) == 0 {
+		return nil, nil
+	}
+
+	// Окна выровнены по UTC, поэтому дневная квота сбрасывается в полночь UTC
+	now := time.Now().UTC()
+	counters := make([]database.RateLimitCounter, 0, len(limits))
+	for _, lim := range limits {
+		key := lim.name
+		if lim.perUser {
+			key += ":" + subject
+		}
+		start := now.Truncate(lim.window)
+		counter := database.RateLimitCounter{
+			Name:        lim.name,
+			Key:         key,
+			WindowStart: start,
+			WindowEnd:   start.Add(lim.window),
+			Limit:       lim.max,
+		}
+		if !lim.perUser {
+			counter.Key, counter.ShardKeys = l.shardKeys(key)
+		}
+		counters = append(counters, counter)
+	}
+
+	exhausted, err := l.repo.IncrementRateLimits(ctx, counters)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			counterFailures.Inc()
+			l.log.Warn(fmt.Sprintf("Rate limit check failed, request let through: %v", err))
+		}
+		return nil, nil
+	}
+	if exhausted == nil {
+		return counters, nil
+	}
+
+	rejectedRequests.WithLabelValues(exhausted.Name).Inc()
+	return nil, &LimitError{Limit: exhausted.Name, RetryAfter: exhausted.WindowEnd.Sub(now)}
+}
+
+// shardKeys spreads a global counter over GlobalCounterShards rows and picks the one the hit is counted in
+func (l *Limiter) shardKeys(key string) (string, []string) {
+	if l.cfg.GlobalCounterShards <= 1 {
+		return key, nil
+	}
+	keys := make([]string, l.cfg.GlobalCounterShards)
+	for i := range keys {
+		keys[i] = key + "#" + strconv.Itoa(i)
+	}
+	return keys[rand.IntN(len(keys))], keys
+}
+
+// Run removes counters of ended windows until ctx is cancelled
+func (l *Limiter) Run(ctx context.Context) {
+	if len(l.requestLimits)+len(l.createLimits) == 0 {
+		l.log.Info("Rate limits are not configured")
+		return
+	}
+	l.log.Info(fmt.Sprintf("Rate limits enabled: %d request limit(s), %d template creation limit(s)",
+		len(l.requestLimits), len(l.createLimits)))
+
+	cleanup := time.NewTicker(l.cfg.CleanupInterval)
+	defer cleanup.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cleanup.C:
+			deleted, err := l.repo.DeleteExpiredRateLimits(ctx, time.Now())
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					l.log.Warn(fmt.Sprintf("Failed to clean up rate limit counters: %v", err))
+				}
+				continue
+			}
+			if deleted > 0 {
+				l.log.Debug(fmt.Sprintf("Removed %d expired rate limit counter(s)", deleted))
+			}
+		}
+	}
+}
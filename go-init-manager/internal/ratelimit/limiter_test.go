@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"go-init/internal/database"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// fakeRateLimits records the counters passed to the repository
+type fakeRateLimits struct {
+	incremented [][]database.RateLimitCounter
+	refunded    [][]database.RateLimitCounter
+	// exhausted is the name of the limit reported as exhausted
+	exhausted string
+	err       error
+}
+
+func (f *fakeRateLimits) IncrementRateLimits(_ context.Context, counters []database.RateLimitCounter) (*database.RateLimitCounter, error) {
+	f.incremented = append(f.incremented, counters)
+	if f.err != nil {
+		return nil, f.err
+	}
+	for i := range counters {
+		if counters[i].Name == f.exhausted {
+			return &counters[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeRateLimits) RefundRateLimits(_ context.Context, counters []database.RateLimitCounter) error {
+	f.refunded = append(f.refunded, counters)
+	return nil
+}
+
+func (f *fakeRateLimits) DeleteExpiredRateLimits(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestLimiter(cfg *Config, repo database.RateLimitRepository) *Limiter {
+	return NewLimiter(cfg, &logger.Logger{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}, repo)
+}
+
+func TestLimiterShardsGlobalCounters(t *testing.T) {
+	repo := &fakeRateLimits{}
+	limiter := newTestLimiter(&Config{UserRequestsPerMinute: 10, GlobalRequestsPerMinute: 1000, GlobalCounterShards: 4}, repo)
+
+	if err := limiter.AllowRequest(context.Background(), "user:1"); err != nil {
+		t.Fatalf("AllowRequest failed: %v", err)
+	}
+	if len(repo.incremented) != 1 || len(repo.incremented[0]) != 2 {
+		t.Fatalf("Incremented counters = %+v, want one call with two counters", repo.incremented)
+	}
+
+	for _, counter := range repo.incremented[0] {
+		switch counter.Name {
+		case LimitUserRequestsPerMinute:
+			if counter.Key != "user_requests_per_minute:user:1" || counter.ShardKeys != nil {
+				t.Fatalf("Per-user counter = %+v, want a single row", counter)
+			}
+		case LimitGlobalRequestsPerMinute:
+			want := []string{
+				"global_requests_per_minute#0", "global_requests_per_minute#1",
+				"global_requests_per_minute#2", "global_requests_per_minute#3",
+			}
+			if !slices.Equal(counter.ShardKeys, want) {
+				t.Fatalf("Global counter shards = %v, want %v", counter.ShardKeys, want)
+			}
+			if !slices.Contains(want, counter.Key) {
+				t.Fatalf("Global counter key %q is not one of its shards", counter.Key)
+			}
+			if counter.Limit != 1000 {
+				t.Fatalf("Global counter limit = %d, want the limit of all shards together", counter.Limit)
+			}
+		default:
+			t.Fatalf("Unexpected counter %+v", counter)
+		}
+	}
+}
+
+func TestLimiterSingleShard(t *testing.T) {
+	repo := &fakeRateLimits{}
+	limiter := newTestLimiter(&Config{GlobalRequestsPerMinute: 1000, GlobalCounterShards: 1}, repo)
+
+	if err := limiter.AllowRequest(context.Background(), "ip:127.0.0.1"); err != nil {
+		t.Fatalf("AllowRequest failed: %v", err)
+	}
+	counter := repo.incremented[0][0]
+	if counter.Key != LimitGlobalRequestsPerMinute || counter.ShardKeys != nil {
+		t.Fatalf("Global counter = %+v, want a single row", counter)
+	}
+}
+
+func TestAllowTemplateCreationRefund(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("refund takes back the counted hits", func(t *testing.T) {
+		repo := &fakeRateLimits{}
+		limiter := newTestLimiter(&Config{UserCreatesPerDay: 10, GlobalCreatesPerMinute: 100, GlobalCounterShards: 4}, repo)
+
+		refund, err := limiter.AllowTemplateCreation(context.Background(), userID)
+		if err != nil {
+			t.Fatalf("AllowTemplateCreation failed: %v", err)
+		}
+		refund()
+		if len(repo.refunded) != 1 || !slices.EqualFunc(repo.refunded[0], repo.incremented[0], func(a, b database.RateLimitCounter) bool {
+			return a.Key == b.Key && a.WindowStart.Equal(b.WindowStart)
+		}) {
+			t.Fatalf("Refunded counters = %+v, want the incremented %+v", repo.refunded, repo.incremented)
+		}
+	})
+
+	t.Run("exhausted limit is rejected", func(t *testing.T) {
+		repo := &fakeRateLimits{exhausted: LimitUserCreatesPerDay}
+		limiter := newTestLimiter(&Config{UserCreatesPerDay: 10}, repo)
+
+		_, err := limiter.AllowTemplateCreation(context.Background(), userID)
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) || limitErr.Limit != LimitUserCreatesPerDay {
+			t.Fatalf("AllowTemplateCreation error = %v, want %s exceeded", err, LimitUserCreatesPerDay)
+		}
+	})
+
+	t.Run("nothing is refunded when the check failed", func(t *testing.T) {
+		repo := &fakeRateLimits{err: errors.New("connection refused")}
+		limiter := newTestLimiter(&Config{UserCreatesPerDay: 10}, repo)
+
+		refund, err := limiter.AllowTemplateCreation(context.Background(), userID)
+		if err != nil {
+			t.Fatalf("Failed check was not let through: %v", err)
+		}
+		refund()
+		if len(repo.refunded) != 0 {
+			t.Fatalf("Refunded counters = %+v, want none", repo.refunded)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	rejectedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "go_init_manager_rate_limited_total",
+		Help: "Requests and template creations rejected by a rate limit or quota, by limit.",
+	}, []string{"limit"})
+	counterFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_manager_rate_limit_errors_total",
+		Help: "Failed rate limit checks; the request is let through.",
+	})
+)
@@ -0,0 +1,43 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+
+	"go-init/internal/auth"
+)
+
+// RejectFunc writes the response to a request rejected by a limit, in the format of the API it belongs to.
+// The response should carry the Retry-After header.
+type RejectFunc func(w http.ResponseWriter, r *http.Request, limitErr *LimitError)
+
+// Middleware counts every request against the request limits and answers exhausted ones through reject.
+// It must run after auth.Authenticator.Middleware: authenticated callers are
+// limited per user, unauthenticated ones per client address.
+func (l *Limiter) Middleware(reject RejectFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(l.requestLimits) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := l.AllowRequest(r.Context(), requestSubject(r))
+			if limitErr, ok := err.(*LimitError); ok {
+				reject(w, r, limitErr)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requestSubject identifies the caller of a request for per-user limits
+func requestSubject(r *http.Request) string {
+	if identity, err := auth.FromContext(r.Context()); err == nil {
+		return "user:" + identity.UserID.String()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
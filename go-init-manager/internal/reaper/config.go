@@ -0,0 +1,20 @@
+package reaper
+
+import "time"
+
+// Config настройки поиска шаблонов, зависших в PENDING или PROCESSING
+type Config struct {
+	// Disabled отключает поиск зависших шаблонов
+	Disabled bool `yaml:"disabled"`
+	// PollInterval период поиска
+	PollInterval time.Duration `yaml:"poll_interval" default:"1m"`
+	// PendingTimeout сколько шаблон может ждать генератора в PENDING
+	PendingTimeout time.Duration `yaml:"pending_timeout" default:"15m"`
+	// ProcessingTimeout сколько может длиться генерация в PROCESSING
+	ProcessingTimeout time.Duration `yaml:"processing_timeout" default:"30m"`
+	// RetryAttempts номер попытки, до которого зависший шаблон перезапускается автоматически,
+	// дальше он помечается FAILED; 1 отключает автоматический перезапуск. Ограничен templates.max_attempts
+	RetryAttempts int `yaml:"retry_attempts" default:"2"`
+	// BatchSize сколько шаблонов обрабатывается за одну транзакцию
+	BatchSize int `yaml:"batch_size" default:"50"`
+}
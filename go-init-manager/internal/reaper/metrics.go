@@ -0,0 +1,11 @@
+package reaper
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var reapedTemplates = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "go_init_manager_stale_templates_total",
+	Help: "Templates stuck in PENDING or PROCESSING handled by the reaper, by action (retried, failed).",
+}, []string{"action"})
@@ -0,0 +1,78 @@
+package reaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go-init/internal/database"
+	"go-init/internal/graphql"
+
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// StaleTemplateHandler retries or fails templates stuck in an active status
+type StaleTemplateHandler interface {
+	ReapStaleTemplates(ctx context.Context, timeouts database.StaleTemplateTimeouts, retryAttempts, limit int) (graphql.ReapResult, error)
+}
+
+// Reaper periodically looks for templates whose Kafka message was lost or whose generator died mid-job.
+// Every manager replica may run one: a sweep is guarded by an advisory lock, so only one replica works at a time.
+type Reaper struct {
+	cfg      *Config
+	log      *logger.Logger
+	handler  StaleTemplateHandler
+	timeouts database.StaleTemplateTimeouts
+}
+
+// NewReaper creates a reaper with the per-status timeouts from cfg
+func NewReaper(cfg *Config, log *logger.Logger, handler StaleTemplateHandler) *Reaper {
+	return &Reaper{
+		cfg:     cfg,
+		log:     log,
+		handler: handler,
+		timeouts: database.StaleTemplateTimeouts{
+			database.StatusPending:    cfg.PendingTimeout,
+			database.StatusProcessing: cfg.ProcessingTimeout,
+		},
+	}
+}
+
+// Run sweeps stale templates until ctx is cancelled
+func (r *Reaper) Run(ctx context.Context) {
+	r.log.Info(fmt.Sprintf("Stale template reaper started, polling every %s (PENDING timeout %s, PROCESSING timeout %s)",
+		r.cfg.PollInterval, r.cfg.PendingTimeout, r.cfg.ProcessingTimeout))
+
+	poll := time.NewTicker(r.cfg.PollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.log.Info("Stale template reaper stopped")
+			return
+		case <-poll.C:
+			r.sweep(ctx)
+		}
+	}
+}
+
+// sweep handles batches until no stale templates are left or another replica holds the lock
+func (r *Reaper) sweep(ctx context.Context) {
+	for ctx.Err() == nil {
+		result, err := r.handler.ReapStaleTemplates(ctx, r.timeouts, r.cfg.RetryAttempts, r.cfg.BatchSize)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				r.log.Error(fmt.Sprintf("Failed to reap stale templates: %v", err))
+			}
+			return
+		}
+
+		reapedTemplates.WithLabelValues("retried").Add(float64(result.Retried))
+		reapedTemplates.WithLabelValues("failed").Add(float64(result.Failed))
+		if !result.Locked || result.Retried+result.Failed < r.cfg.BatchSize {
+			return
+		}
+	}
+}
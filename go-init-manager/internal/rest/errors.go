@@ -0,0 +1,89 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-init/internal/graphql"
+	"go-init/internal/ratelimit"
+	"go-init/internal/validation"
+)
+
+// Коды ошибок REST API в дополнение к причинам graphql.Failure*
+const (
+	codeInvalidBody      = "INVALID_BODY"
+	codeInvalidArgument  = "INVALID_ARGUMENT"
+	codeValidationFailed = "VALIDATION_FAILED"
+	codeNotFound         = "NOT_FOUND"
+	codeArchiveNotReady  = "ARCHIVE_NOT_READY"
+	codeUnavailable      = "UNAVAILABLE"
+)
+
+// ErrorResponse is the body of every unsuccessful response
+type ErrorResponse struct {
+	Error Error `json:"error"`
+}
+
+// Error describes why a request failed
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Invalid fields of the request body, only for VALIDATION_FAILED
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// FieldError describes an invalid field of the request body
+type FieldError struct {
+	// Dotted path of the field in the body, e.g. endpoints[1].role
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// failureStatus maps the reason of an unsuccessful graphql.Service response to an HTTP status
+func failureStatus(reason string) int {
+	switch reason {
+	case graphql.FailureUnauthenticated:
+		return http.StatusUnauthorized
+	case graphql.FailureInvalidID:
+		return http.StatusBadRequest
+	case graphql.FailureNotFound:
+		return http.StatusNotFound
+	case graphql.FailureConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, ErrorResponse{Error: Error{Code: code, Message: message}})
+}
+
+// writeValidationError reports field errors relative to the request body:
+// the service validates the body as the GraphQL argument "input"
+func writeValidationError(w http.ResponseWriter, fieldErrs validation.Errors) {
+	fields := make([]FieldError, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		if len(fieldErr.Path) > 0 {
+			fieldErr.Path = fieldErr.Path[1:]
+		}
+		fields = append(fields, FieldError{
+			Field:   fieldErr.Field(),
+			Code:    fieldErr.Code,
+			Message: fieldErr.Message,
+		})
+	}
+
+	writeJSON(w, http.StatusUnprocessableEntity, ErrorResponse{Error: Error{
+		Code:    codeValidationFailed,
+		Message: "request body is invalid",
+		Fields:  fields,
+	}})
+}
+
+// WriteRateLimited answers a request rejected by a rate limit or quota with 429 and a Retry-After header
+func WriteRateLimited(w http.ResponseWriter, _ *http.Request, limitErr *ratelimit.LimitError) {
+	w.Header().Set("Retry-After", strconv.Itoa(limitErr.RetryAfterSeconds()))
+	writeError(w, http.StatusTooManyRequests, graphql.CodeRateLimited, limitErr.Error())
+}
@@ -0,0 +1,214 @@
+// Package rest serves the versioned REST/JSON API of the manager for clients that do not speak GraphQL,
+// such as CI pipelines. Handlers call the same graphql.Service methods as the GraphQL resolvers.
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go-init/internal/auth"
+	"go-init/internal/graphql"
+	"go-init/internal/ratelimit"
+	"go-init/internal/validation"
+	"go-init/pkg/api/graphql/model"
+
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+// BasePath is the prefix of all REST API routes
+const BasePath = "/api/v1"
+
+// maxBodySize limits request bodies; template inputs with DDL stay well below it
+const maxBodySize = 1 << 20
+
+// Handler routes REST API requests to graphql.Service. It expects the caller identity
+// to be attached to the request context by auth.Authenticator.Middleware.
+type Handler struct {
+	service *graphql.Service
+	log     *logger.Logger
+	mux     *http.ServeMux
+	spec    []byte
+}
+
+// NewHandler registers the routes and renders the OpenAPI document for them
+func NewHandler(service *graphql.Service, log *logger.Logger) (*Handler, error) {
+	h := &Handler{
+		service: service,
+		log:     log,
+		mux:     http.NewServeMux(),
+	}
+
+	routes := h.routes()
+	for _, route := range routes {
+		h.mux.HandleFunc(route.method+" "+BasePath+route.path, route.handler)
+	}
+
+	spec, err := json.Marshal(openAPIDocument(routes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render OpenAPI document: %w", err)
+	}
+	h.spec = spec
+	h.mux.HandleFunc("GET "+BasePath+"/openapi.json", h.openAPI)
+	h.mux.HandleFunc(BasePath+"/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+	})
+	return h, nil
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) openAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(h.spec)
+}
+
+// createTemplate handles POST /templates
+func (h *Handler) createTemplate(w http.ResponseWriter, r *http.Request) {
+	var input model.CreateTemplateInput
+	if err := decodeBody(w, r, &input); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, err.Error())
+		return
+	}
+
+	response, err := h.service.CreateTemplate(r.Context(), input)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	h.templateResponse(w, response, http.StatusCreated)
+}
+
+// getTemplate handles GET /templates/{id}
+func (h *Handler) getTemplate(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.GetTemplate(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	h.templateResponse(w, response, http.StatusOK)
+}
+
+// listTemplates handles GET /templates
+func (h *Handler) listTemplates(w http.ResponseWriter, r *http.Request) {
+	query, err := parseListQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidArgument, err.Error())
+		return
+	}
+
+	connection, err := h.service.ListTemplates(r.Context(), query.first, query.after, query.filter, query.orderBy)
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newTemplateList(connection))
+}
+
+// retryTemplate handles POST /templates/{id}/retry
+func (h *Handler) retryTemplate(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.RetryTemplate(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	h.templateResponse(w, response, http.StatusAccepted)
+}
+
+// downloadTemplate handles GET /templates/{id}/download by redirecting to the archive URL
+func (h *Handler) downloadTemplate(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.GetTemplate(r.Context(), r.PathValue("id"))
+	if err != nil {
+		h.serviceError(w, r, err)
+		return
+	}
+	if !response.Success {
+		h.templateResponse(w, response, http.StatusOK)
+		return
+	}
+
+	url, err := h.service.ResolveZipURL(r.Context(), response.Template)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, codeUnavailable, err.Error())
+		return
+	}
+	if url == nil {
+		writeError(w, http.StatusNotFound, codeArchiveNotReady,
+			fmt.Sprintf("template %s has no archive yet", response.Template.ID))
+		return
+	}
+	http.Redirect(w, r, *url, http.StatusFound)
+}
+
+// templateResponse writes the template of a successful response or the failure it describes
+func (h *Handler) templateResponse(w http.ResponseWriter, response *model.TemplateResponse, status int) {
+	if response.Success {
+		writeJSON(w, status, newTemplate(response.Template))
+		return
+	}
+
+	message := ""
+	if response.Message != nil {
+		message = *response.Message
+	}
+	code := response.Reason
+	if code == "" {
+		code = graphql.FailureInternal
+	}
+	writeError(w, failureStatus(code), code, message)
+}
+
+// serviceError writes an error returned by graphql.Service
+func (h *Handler) serviceError(w http.ResponseWriter, r *http.Request, err error) {
+	var fieldErrs validation.Errors
+	var limitErr *ratelimit.LimitError
+	switch {
+	case errors.As(err, &fieldErrs):
+		writeValidationError(w, fieldErrs)
+	case errors.As(err, &limitErr):
+		WriteRateLimited(w, r, limitErr)
+	case errors.Is(err, auth.ErrUnauthenticated):
+		writeError(w, http.StatusUnauthorized, graphql.FailureUnauthenticated, err.Error())
+	case errors.Is(err, graphql.ErrInvalidArgument):
+		writeError(w, http.StatusBadRequest, codeInvalidArgument, err.Error())
+	default:
+		h.log.Error(fmt.Sprintf("REST %s %s failed: %v", r.Method, r.URL.Path, err))
+		writeError(w, http.StatusInternalServerError, graphql.FailureInternal, "internal error")
+	}
+}
+
+// decodeBody reads a JSON body into v, rejecting unknown fields so that typos in CI scripts surface early
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid JSON body: unexpected data after the object")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// intParam parses an optional integer query parameter
+func intParam(name, value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,204 @@
+package rest
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+// apiVersion is the version of the REST API in the OpenAPI document
+const apiVersion = "1.0.0"
+
+// enums lists the values of GraphQL enums used in REST bodies; the Go types carry no values themselves
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(model.TemplateStatus("")):  enumValues(model.AllTemplateStatus),
+	reflect.TypeOf(model.ServiceProtocol("")): enumValues(model.AllServiceProtocol),
+	reflect.TypeOf(model.ServiceRole("")):     enumValues(model.AllServiceRole),
+	reflect.TypeOf(model.DatabaseType("")):    enumValues(model.AllDatabaseType),
+}
+
+// openAPIDocument describes the routes as an OpenAPI 3 document.
+// Body schemas are generated from the Go types the handlers encode and decode.
+func openAPIDocument(routes []route) map[string]any {
+	schemas := schemaRegistry{components: map[string]any{}}
+
+	paths := map[string]any{}
+	for _, route := range routes {
+		operation := map[string]any{
+			"operationId": route.operationID,
+			"summary":     route.summary,
+		}
+
+		var parameters []any
+		for _, name := range pathParameters(route.path) {
+			parameters = append(parameters, map[string]any{
+				"name":        name,
+				"in":          "path",
+				"required":    true,
+				"description": "Numeric ID or UUID of the template",
+				"schema":      map[string]any{"type": "string"},
+			})
+		}
+		for _, param := range route.parameters {
+			parameters = append(parameters, map[string]any{
+				"name":        param.name,
+				"in":          "query",
+				"description": param.description,
+				"schema":      param.schema,
+			})
+		}
+		if len(parameters) > 0 {
+			operation["parameters"] = parameters
+		}
+
+		if route.body != nil {
+			operation["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(schemas.schema(reflect.TypeOf(route.body))),
+			}
+		}
+
+		responses := map[string]any{
+			"401": map[string]any{
+				"description": "Missing or invalid bearer token",
+				"content":     jsonContent(schemas.schema(reflect.TypeOf(ErrorResponse{}))),
+			},
+			"429": map[string]any{
+				"description": "Rate limit or template quota exhausted, retry after the Retry-After header",
+				"content":     jsonContent(schemas.schema(reflect.TypeOf(ErrorResponse{}))),
+			},
+		}
+		for _, resp := range route.responses {
+			description := map[string]any{"description": resp.description}
+			if resp.body != nil {
+				description["content"] = jsonContent(schemas.schema(reflect.TypeOf(resp.body)))
+			}
+			responses[strconv.Itoa(resp.status)] = description
+		}
+		operation["responses"] = responses
+
+		item, ok := paths[route.path].(map[string]any)
+		if !ok {
+			item = map[string]any{}
+			paths[route.path] = item
+		}
+		item[strings.ToLower(route.method)] = operation
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "go-init-manager REST API",
+			"description": "Create, list and download service templates. Mirrors the GraphQL API served at /graphql.",
+			"version":     apiVersion,
+		},
+		"servers":  []any{map[string]any{"url": BasePath}},
+		"security": []any{map[string]any{"bearerAuth": []any{}}},
+		"paths":    paths,
+		"components": map[string]any{
+			"schemas": schemas.components,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+// schemaRegistry turns Go types into JSON schemas; named structs and enums become components
+type schemaRegistry struct {
+	components map[string]any
+}
+
+func (s schemaRegistry) schema(t reflect.Type) map[string]any {
+	if values, ok := enums[t]; ok {
+		if _, ok := s.components[t.Name()]; !ok {
+			s.components[t.Name()] = map[string]any{"type": "string", "enum": values}
+		}
+		return componentRef(t.Name())
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.schema(t.Elem())
+	case reflect.Struct:
+		if _, ok := s.components[t.Name()]; !ok {
+			// Заглушка до заполнения защищает от бесконечной рекурсии на циклических типах
+			s.components[t.Name()] = map[string]any{}
+			s.components[t.Name()] = s.object(t)
+		}
+		return componentRef(t.Name())
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": true}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	default:
+		return map[string]any{}
+	}
+}
+
+// object describes the exported JSON fields of a struct; fields without omitempty are required
+func (s schemaRegistry) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = s.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	result := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	return result
+}
+
+// pathParameters returns the names of {parameters} in a route path
+func pathParameters(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, strings.Trim(segment, "{}"))
+		}
+	}
+	return names
+}
+
+func componentRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+func enumSchema[T ~string](values []T) map[string]any {
+	return map[string]any{"type": "string", "enum": enumValues(values)}
+}
+
+func enumValues[T ~string](values []T) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, string(value))
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package rest
+
+import (
+	"net/http"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+// route is one REST endpoint together with what the OpenAPI document says about it
+type route struct {
+	method string
+	// Path relative to BasePath, parameters in braces
+	path        string
+	operationID string
+	summary     string
+	parameters  []parameter
+	// Zero value of the JSON request body, nil for requests without a body
+	body      any
+	responses []response
+	handler   http.HandlerFunc
+}
+
+type parameter struct {
+	name        string
+	description string
+	schema      map[string]any
+}
+
+type response struct {
+	status      int
+	description string
+	// Zero value of the JSON response body, nil for responses without a body
+	body any
+}
+
+func (h *Handler) routes() []route {
+	templateNotFound := response{http.StatusNotFound, "Template not found or not accessible", ErrorResponse{}}
+	invalidID := response{http.StatusBadRequest, "Invalid template ID", ErrorResponse{}}
+
+	return []route{
+		{
+			method:      http.MethodPost,
+			path:        "/templates",
+			operationID: "createTemplate",
+			summary:     "Create a template and enqueue its generation",
+			body:        model.CreateTemplateInput{},
+			responses: []response{
+				{http.StatusCreated, "Template created, generation enqueued", Template{}},
+				{http.StatusBadRequest, "Malformed JSON body", ErrorResponse{}},
+				{http.StatusUnprocessableEntity, "Invalid template input, see error.fields", ErrorResponse{}},
+			},
+			handler: h.createTemplate,
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/templates",
+			operationID: "listTemplates",
+			summary:     "List templates of the caller, newest first by default",
+			parameters: []parameter{
+				{"limit", "Page size, 0..100 (default 20)", map[string]any{"type": "integer", "minimum": 0, "maximum": 100}},
+				{"cursor", "nextCursor of the previous page", map[string]any{"type": "string"}},
+				{"status", "Only templates in this status", enumSchema(model.AllTemplateStatus)},
+				{"nameContains", "Case-insensitive substring of the name", map[string]any{"type": "string"}},
+				{"protocol", "Only templates with an endpoint of this protocol", enumSchema(model.AllServiceProtocol)},
+				{"databaseType", "Only templates with this database", enumSchema(model.AllDatabaseType)},
+				{"createdAfter", "RFC3339 timestamp", map[string]any{"type": "string", "format": "date-time"}},
+				{"createdBefore", "RFC3339 timestamp", map[string]any{"type": "string", "format": "date-time"}},
+				{"orderBy", "Sort field (default CREATED_AT)", enumSchema(model.AllTemplateOrderField)},
+				{"direction", "Sort direction (default DESC)", enumSchema(model.AllOrderDirection)},
+			},
+			responses: []response{
+				{http.StatusOK, "One page of templates", TemplateList{}},
+				{http.StatusBadRequest, "Invalid query parameter or cursor", ErrorResponse{}},
+			},
+			handler: h.listTemplates,
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/templates/{id}",
+			operationID: "getTemplate",
+			summary:     "Get a template by numeric ID or UUID",
+			responses: []response{
+				{http.StatusOK, "Template", Template{}},
+				invalidID,
+				templateNotFound,
+			},
+			handler: h.getTemplate,
+		},
+		{
+			method:      http.MethodPost,
+			path:        "/templates/{id}/retry",
+			operationID: "retryTemplate",
+			summary:     "Re-enqueue generation of a failed or cancelled template",
+			responses: []response{
+				{http.StatusAccepted, "Retry enqueued", Template{}},
+				invalidID,
+				templateNotFound,
+				{http.StatusConflict, "Template cannot be retried in its status or has no attempts left", ErrorResponse{}},
+			},
+			handler: h.retryTemplate,
+		},
+		{
+			method:      http.MethodGet,
+			path:        "/templates/{id}/download",
+			operationID: "downloadTemplate",
+			summary:     "Redirect to the archive of the current revision",
+			responses: []response{
+				{http.StatusFound, "Redirect to the archive, see the Location header", nil},
+				invalidID,
+				{http.StatusNotFound, "Template not found or its archive is not ready (ARCHIVE_NOT_READY)", ErrorResponse{}},
+				{http.StatusServiceUnavailable, "Download URL cannot be issued right now", ErrorResponse{}},
+			},
+			handler: h.downloadTemplate,
+		},
+	}
+}
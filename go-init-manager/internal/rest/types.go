@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+// Template is a service template as returned by the REST API
+type Template struct {
+	ID       string               `json:"id"`
+	Name     string               `json:"name"`
+	Status   model.TemplateStatus `json:"status"`
+	Error    *string              `json:"error,omitempty"`
+	Attempts int                  `json:"attempts"`
+	Revision int                  `json:"revision"`
+
+	Endpoints []*model.EndpointConfig `json:"endpoints"`
+	Database  *model.DatabaseConfig   `json:"database,omitempty"`
+	Docker    *model.DockerConfig     `json:"docker,omitempty"`
+	Advanced  *model.AdvancedConfig   `json:"advanced,omitempty"`
+
+	CreatedAt string  `json:"createdAt"`
+	UpdatedAt *string `json:"updatedAt,omitempty"`
+	// Путь скачивания архива через REST API; пусто, пока архив не готов
+	DownloadURL *string `json:"downloadUrl,omitempty"`
+}
+
+// TemplateList is one page of templates
+type TemplateList struct {
+	Templates  []Template `json:"templates"`
+	TotalCount int        `json:"totalCount"`
+	// Cursor of the next page, absent on the last page
+	NextCursor *string `json:"nextCursor,omitempty"`
+}
+
+func newTemplate(template *model.ServiceTemplate) Template {
+	result := Template{
+		ID:        template.ID,
+		Name:      template.Name,
+		Status:    model.TemplateStatusPending,
+		Error:     template.Error,
+		Attempts:  template.Attempts,
+		Revision:  template.Revision,
+		Endpoints: template.Endpoints,
+		Database:  template.Database,
+		Docker:    template.Docker,
+		Advanced:  template.Advanced,
+		CreatedAt: template.CreatedAt,
+		UpdatedAt: template.UpdatedAt,
+	}
+	if template.Status != nil {
+		result.Status = *template.Status
+	}
+	if result.Endpoints == nil {
+		result.Endpoints = []*model.EndpointConfig{}
+	}
+	if template.ArchiveObject != "" || template.ZipURL != nil {
+		download := fmt.Sprintf("%s/templates/%s/download", BasePath, template.ID)
+		result.DownloadURL = &download
+	}
+	return result
+}
+
+func newTemplateList(connection *model.TemplateConnection) TemplateList {
+	result := TemplateList{
+		Templates:  make([]Template, 0, len(connection.Edges)),
+		TotalCount: connection.TotalCount,
+	}
+	for _, edge := range connection.Edges {
+		result.Templates = append(result.Templates, newTemplate(edge.Node))
+	}
+	if connection.PageInfo != nil && connection.PageInfo.HasNextPage {
+		result.NextCursor = connection.PageInfo.EndCursor
+	}
+	return result
+}
+
+// listQuery holds the ListTemplates arguments parsed from the query string
+type listQuery struct {
+	first   *int
+	after   *string
+	filter  *model.TemplateFilter
+	orderBy *model.TemplateOrder
+}
+
+// parseListQuery reads GET /templates parameters; enum values are checked here because,
+// unlike GraphQL, nothing has validated them before the service is called
+func parseListQuery(values url.Values) (listQuery, error) {
+	var (
+		query listQuery
+		err   error
+	)
+	if query.first, err = intParam("limit", values.Get("limit")); err != nil {
+		return query, err
+	}
+	if cursor := values.Get("cursor"); cursor != "" {
+		query.after = &cursor
+	}
+
+	filter := &model.TemplateFilter{}
+	if filter.Status, err = enumParam("status", values.Get("status"), model.AllTemplateStatus); err != nil {
+		return query, err
+	}
+	if filter.Protocol, err = enumParam("protocol", values.Get("protocol"), model.AllServiceProtocol); err != nil {
+		return query, err
+	}
+	if filter.DatabaseType, err = enumParam("databaseType", values.Get("databaseType"), model.AllDatabaseType); err != nil {
+		return query, err
+	}
+	filter.NameContains = stringParam(values.Get("nameContains"))
+	filter.CreatedAfter = stringParam(values.Get("createdAfter"))
+	filter.CreatedBefore = stringParam(values.Get("createdBefore"))
+	query.filter = filter
+
+	field, err := enumParam("orderBy", values.Get("orderBy"), model.AllTemplateOrderField)
+	if err != nil {
+		return query, err
+	}
+	direction, err := enumParam("direction", values.Get("direction"), model.AllOrderDirection)
+	if err != nil {
+		return query, err
+	}
+	if field != nil || direction != nil {
+		// Как и в GraphQL, по умолчанию новые шаблоны идут первыми
+		query.orderBy = &model.TemplateOrder{Field: model.TemplateOrderFieldCreatedAt, Direction: model.OrderDirectionDesc}
+		if field != nil {
+			query.orderBy.Field = *field
+		}
+		if direction != nil {
+			query.orderBy.Direction = *direction
+		}
+	}
+	return query, nil
+}
+
+// enumParam parses an optional query parameter restricted to the values of a GraphQL enum
+func enumParam[T ~string](name, value string, allowed []T) (*T, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed := T(value)
+	if !slices.Contains(allowed, parsed) {
+		return nil, fmt.Errorf("%s must be one of %v", name, allowed)
+	}
+	return &parsed, nil
+}
+
+func stringParam(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
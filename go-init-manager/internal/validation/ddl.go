@@ -0,0 +1,529 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Dialect - диалект SQL, в котором написан DDL
+type Dialect int
+
+const (
+	DialectPostgres Dialect = iota
+	DialectMySQL
+)
+
+type tokenKind int
+
+const (
+	tokenWord        tokenKind = iota // ключевое слово или идентификатор без кавычек
+	tokenQuotedIdent                  // "ident" в PostgreSQL, `ident` в MySQL
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+type sqlToken struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+func (t sqlToken) isWord(words ...string) bool {
+	if t.kind != tokenWord {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t sqlToken) isPunct(p string) bool {
+	return t.kind == tokenPunct && t.text == p
+}
+
+// ParseDDL проверяет, что DDL разбирается в выбранном диалекте: лексемы корректны,
+// скобки сбалансированы, каждый оператор начинается с известного ключевого слова, а CREATE TABLE содержит
+// имя таблицы и непустой список колонок, у каждой из которых указан тип. Принимаются операторы схем
+// и выгрузок pg_dump и mysqldump (SET, транзакции, INSERT, COPY, GRANT); семантика (типы, ссылки) не проверяется.
+func ParseDDL(ddl string, dialect Dialect) error {
+	tokens, err := tokenize(ddl, dialect)
+	if err != nil {
+		return err
+	}
+
+	statements, err := splitStatements(tokens)
+	if err != nil {
+		return err
+	}
+	if len(statements) == 0 {
+		return fmt.Errorf("DDL contains no statements")
+	}
+
+	for _, stmt := range statements {
+		if err := checkStatement(stmt, dialect); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements делит лексемы на операторы по ';' вне скобок
+func splitStatements(tokens []sqlToken) ([][]sqlToken, error) {
+	var (
+		statements [][]sqlToken
+		current    []sqlToken
+		open       []sqlToken
+	)
+	for _, tok := range tokens {
+		switch {
+		case tok.isPunct("("):
+			open = append(open, tok)
+		case tok.isPunct(")"):
+			if len(open) == 0 {
+				return nil, fmt.Errorf("line %d: unexpected ')'", tok.line)
+			}
+			open = open[:len(open)-1]
+		case tok.isPunct(";") && len(open) == 0:
+			if len(current) > 0 {
+				statements = append(statements, current)
+			}
+			current = nil
+			continue
+		}
+		current = append(current, tok)
+	}
+	if len(open) > 0 {
+		return nil, fmt.Errorf("line %d: '(' is never closed", open[len(open)-1].line)
+	}
+	if len(current) > 0 {
+		statements = append(statements, current)
+	}
+	return statements, nil
+}
+
+// statementKeywords - ключевые слова, с которых начинаются операторы схем и выгрузок pg_dump и mysqldump
+var statementKeywords = []string{
+	"ALTER", "ANALYZE", "BEGIN", "CALL", "CLUSTER", "COMMENT", "COMMIT", "COPY", "CREATE", "DELETE", "DO",
+	"DROP", "END", "FLUSH", "GRANT", "INSERT", "LOCK", "REFRESH", "REINDEX", "RENAME", "REPLACE", "RESET",
+	"REVOKE", "ROLLBACK", "SAVEPOINT", "SECURITY", "SELECT", "SET", "START", "TRUNCATE", "UNLOCK", "UPDATE",
+	"USE", "VACUUM", "WITH",
+}
+
+// tableConstraintKeywords начинают в списке колонок ограничение таблицы, а не колонку
+var tableConstraintKeywords = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE", "LIKE"}
+
+// mysqlIndexKeywords начинают в списке колонок MySQL определение индекса; в PostgreSQL это обычные имена колонок
+var mysqlIndexKeywords = []string{"KEY", "INDEX", "FULLTEXT", "SPATIAL"}
+
+// typeModifiers продолжают имя типа колонки: double precision, timestamp with time zone, int unsigned
+var typeModifiers = []string{
+	"PRECISION", "VARYING", "CHARACTER", "CHAR", "VARCHAR", "WITH", "WITHOUT", "TIME", "ZONE", "LOCAL",
+	"YEAR", "MONTH", "DAY", "HOUR", "MINUTE", "SECOND", "TO", "ARRAY", "UNSIGNED", "SIGNED", "ZEROFILL",
+	"BINARY", "SERIAL",
+}
+
+// columnConstraintKeywords начинают ограничения и атрибуты колонки, после них тип уже не проверяется
+var columnConstraintKeywords = []string{
+	"NOT", "NULL", "DEFAULT", "PRIMARY", "UNIQUE", "KEY", "REFERENCES", "CHECK", "CONSTRAINT", "COLLATE",
+	"GENERATED", "AS", "AUTO_INCREMENT", "COMMENT", "ON", "CHARSET", "STORAGE", "COMPRESSION", "VISIBLE",
+	"INVISIBLE", "COLUMN_FORMAT", "ENGINE_ATTRIBUTE", "SECONDARY_ENGINE_ATTRIBUTE", "SRID",
+}
+
+// checkStatement проверяет, что оператор начинается с известного ключевого слова; структура CREATE TABLE проверяется подробнее
+func checkStatement(stmt []sqlToken, dialect Dialect) error {
+	first := stmt[0]
+	if first.kind != tokenWord {
+		return fmt.Errorf("line %d: unexpected %q, a statement must start with a keyword", first.line, first.text)
+	}
+	if !first.isWord(statementKeywords...) {
+		return fmt.Errorf("line %d: unknown statement %q", first.line, first.text)
+	}
+
+	if first.isWord("CREATE") {
+		return checkCreate(stmt, dialect)
+	}
+	return nil
+}
+
+// checkCreate проверяет структуру CREATE TABLE; остальные CREATE принимаются как есть
+func checkCreate(stmt []sqlToken, dialect Dialect) error {
+	i := 1
+	for i < len(stmt) && stmt[i].isWord("TEMP", "TEMPORARY", "UNLOGGED", "GLOBAL", "LOCAL") {
+		i++
+	}
+	if i >= len(stmt) || !stmt[i].isWord("TABLE") {
+		return nil
+	}
+	table := stmt[i]
+	i++
+
+	if i+2 < len(stmt) && stmt[i].isWord("IF") && stmt[i+1].isWord("NOT") && stmt[i+2].isWord("EXISTS") {
+		i += 3
+	}
+
+	// Имя таблицы, возможно со схемой: schema.table
+	for {
+		if i >= len(stmt) || !isIdentifier(stmt[i]) {
+			return fmt.Errorf("line %d: CREATE TABLE requires a table name", table.line)
+		}
+		i++
+		if i < len(stmt) && stmt[i].isPunct(".") {
+			i++
+			continue
+		}
+		break
+	}
+
+	if i >= len(stmt) {
+		return fmt.Errorf("line %d: CREATE TABLE requires a column list", table.line)
+	}
+	// CREATE TABLE ... AS SELECT / LIKE / PARTITION OF / OF type не содержат списка колонок
+	if stmt[i].isWord("AS", "LIKE", "PARTITION", "OF") {
+		return nil
+	}
+	if !stmt[i].isPunct("(") {
+		return fmt.Errorf("line %d: expected '(' after table name, got %q", stmt[i].line, stmt[i].text)
+	}
+	return checkColumnList(stmt[i:], dialect)
+}
+
+// checkColumnList проверяет определения колонок и ограничений в скобках
+func checkColumnList(tokens []sqlToken, dialect Dialect) error {
+	open := tokens[0]
+	depth := 0
+	var element []sqlToken
+	for _, tok := range tokens {
+		switch {
+		case tok.isPunct("("):
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case tok.isPunct(")"):
+			depth--
+			if depth == 0 {
+				return checkTableElement(element, tok, dialect)
+			}
+		case tok.isPunct(",") && depth == 1:
+			if err := checkTableElement(element, tok, dialect); err != nil {
+				return err
+			}
+			element = nil
+			continue
+		}
+		element = append(element, tok)
+	}
+	return fmt.Errorf("line %d: '(' is never closed", open.line)
+}
+
+// checkTableElement проверяет определение колонки или ограничения таблицы; end - запятая или скобка после него
+func checkTableElement(element []sqlToken, end sqlToken, dialect Dialect) error {
+	if len(element) == 0 {
+		return fmt.Errorf("line %d: empty column definition in CREATE TABLE", end.line)
+	}
+
+	name := element[0]
+	if name.isWord(tableConstraintKeywords...) || (dialect == DialectMySQL && name.isWord(mysqlIndexKeywords...)) {
+		return nil
+	}
+	if !isIdentifier(name) {
+		return fmt.Errorf("line %d: unexpected %q, expected a column name", name.line, name.text)
+	}
+	if len(element) == 1 || !isIdentifier(element[1]) || element[1].isWord(columnConstraintKeywords...) {
+		return fmt.Errorf("line %d: column %s has no type", name.line, name.text)
+	}
+	return checkColumnType(element[1:], name)
+}
+
+// checkColumnType проверяет, что за именем типа колонки следуют только его параметры, продолжение
+// многословного типа и ограничения: "id int int" отклоняется
+func checkColumnType(tokens []sqlToken, column sqlToken) error {
+	depth := 0
+	for i := 1; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch {
+		case tok.isPunct("("), tok.isPunct("["):
+			depth++
+		case tok.isPunct(")"), tok.isPunct("]"):
+			depth--
+		case depth > 0:
+		case tok.isWord(columnConstraintKeywords...):
+			return nil
+		case tok.isWord("CHARACTER") && i+1 < len(tokens) && tokens[i+1].isWord("SET"):
+			// CHARACTER SET utf8mb4 в MySQL
+			return nil
+		case tok.isPunct("."):
+			// Тип со схемой: public.status
+		case tokens[i-1].isPunct(".") && isIdentifier(tok):
+		case tok.isWord(typeModifiers...):
+		default:
+			return fmt.Errorf("line %d: unexpected %q in the definition of column %s", tok.line, tok.text, column.text)
+		}
+	}
+	return nil
+}
+
+func isIdentifier(tok sqlToken) bool {
+	return tok.kind == tokenWord || tok.kind == tokenQuotedIdent
+}
+
+// tokenize разбивает DDL на лексемы с учетом правил кавычек и комментариев диалекта
+func tokenize(src string, dialect Dialect) ([]sqlToken, error) {
+	var tokens []sqlToken
+	line := 1
+	pos := 0
+
+	for pos < len(src) {
+		r, size := utf8.DecodeRuneInString(src[pos:])
+		next := byte(0)
+		if pos+1 < len(src) {
+			next = src[pos+1]
+		}
+
+		switch {
+		case r == '\n':
+			line++
+			pos++
+
+		case unicode.IsSpace(r):
+			pos += size
+
+		case r == '-' && next == '-', r == '#' && dialect == DialectMySQL,
+			// Метакоманды psql (\connect, \restrict) в выгрузках pg_dump занимают строку целиком
+			r == '\\' && dialect == DialectPostgres && atStatementStart(tokens):
+			end := strings.IndexByte(src[pos:], '\n')
+			if end < 0 {
+				end = len(src) - pos
+			}
+			pos += end
+
+		case r == '/' && next == '*':
+			end, lines, ok := skipBlockComment(src, pos, dialect)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += lines
+			pos = end
+
+		case r == '\'':
+			backslash := dialect == DialectMySQL || isEscapeStringPrefix(tokens, src, pos)
+			end, ok := scanQuoted(src, pos, '\'', backslash)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated string literal", line)
+			}
+			tokens = append(tokens, sqlToken{kind: tokenString, text: src[pos:end], line: line})
+			line += strings.Count(src[pos:end], "\n")
+			pos = end
+
+		case r == '"':
+			end, ok := scanQuoted(src, pos, '"', dialect == DialectMySQL)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated quoted identifier", line)
+			}
+			kind := tokenQuotedIdent
+			if dialect == DialectMySQL {
+				// Без ANSI_QUOTES двойные кавычки в MySQL означают строку
+				kind = tokenString
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: src[pos:end], line: line})
+			line += strings.Count(src[pos:end], "\n")
+			pos = end
+
+		case r == '`':
+			if dialect != DialectMySQL {
+				return nil, fmt.Errorf("line %d: backtick-quoted identifiers are MySQL syntax, use double quotes in PostgreSQL", line)
+			}
+			end, ok := scanQuoted(src, pos, '`', false)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated quoted identifier", line)
+			}
+			tokens = append(tokens, sqlToken{kind: tokenQuotedIdent, text: src[pos:end], line: line})
+			line += strings.Count(src[pos:end], "\n")
+			pos = end
+
+		case r == ';' && dialect == DialectPostgres && isCopyFromStdin(tokens):
+			tokens = append(tokens, sqlToken{kind: tokenPunct, text: ";", line: line})
+			end, lines, ok := skipCopyData(src, pos+1)
+			if !ok {
+				return nil, fmt.Errorf("line %d: COPY FROM stdin data is not terminated by \\.", line)
+			}
+			line += lines
+			pos = end
+
+		case r == '$' && dialect == DialectPostgres && !isDigit(next):
+			end, ok := scanDollarQuoted(src, pos)
+			if !ok {
+				return nil, fmt.Errorf("line %d: unterminated dollar-quoted string", line)
+			}
+			tokens = append(tokens, sqlToken{kind: tokenString, text: src[pos:end], line: line})
+			line += strings.Count(src[pos:end], "\n")
+			pos = end
+
+		case r < utf8.RuneSelf && isDigit(byte(r)):
+			end := pos
+			for end < len(src) && (isDigit(src[end]) || src[end] == '.' || src[end] == 'e' || src[end] == 'E') {
+				end++
+			}
+			tokens = append(tokens, sqlToken{kind: tokenNumber, text: src[pos:end], line: line})
+			pos = end
+
+		case isWordStart(r, dialect):
+			end := pos + size
+			for end < len(src) {
+				wr, wsize := utf8.DecodeRuneInString(src[end:])
+				if !isWordPart(wr) {
+					break
+				}
+				end += wsize
+			}
+			tokens = append(tokens, sqlToken{kind: tokenWord, text: src[pos:end], line: line})
+			pos = end
+
+		case unicode.IsPrint(r):
+			tokens = append(tokens, sqlToken{kind: tokenPunct, text: string(r), line: line})
+			pos += size
+
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, r)
+		}
+	}
+	return tokens, nil
+}
+
+// scanQuoted возвращает позицию за закрывающей кавычкой; удвоенная кавычка - экранирование
+func scanQuoted(src string, start int, quote byte, backslash bool) (int, bool) {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(src) && src[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// scanDollarQuoted разбирает строку PostgreSQL вида $tag$...$tag$
+func scanDollarQuoted(src string, start int) (int, bool) {
+	end := start + 1
+	for end < len(src) && (isASCIILetter(src[end]) || isDigit(src[end]) || src[end] == '_') {
+		end++
+	}
+	if end >= len(src) || src[end] != '$' {
+		return 0, false
+	}
+	delimiter := src[start : end+1]
+
+	closing := strings.Index(src[end+1:], delimiter)
+	if closing < 0 {
+		return 0, false
+	}
+	return end + 1 + closing + len(delimiter), true
+}
+
+// skipBlockComment пропускает /* ... */; в PostgreSQL такие комментарии могут быть вложенными
+func skipBlockComment(src string, start int, dialect Dialect) (int, int, bool) {
+	depth := 0
+	lines := 0
+	for i := start; i+1 < len(src); i++ {
+		switch {
+		case src[i] == '\n':
+			lines++
+		case src[i] == '/' && src[i+1] == '*':
+			if depth == 0 || dialect == DialectPostgres {
+				depth++
+			}
+			i++
+		case src[i] == '*' && src[i+1] == '/':
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1, lines, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// atStatementStart сообщает, что следующая лексема начинает новый оператор
+func atStatementStart(tokens []sqlToken) bool {
+	return len(tokens) == 0 || tokens[len(tokens)-1].isPunct(";")
+}
+
+// isCopyFromStdin сообщает, что текущий оператор - COPY ... FROM stdin, за которым следуют строки данных
+func isCopyFromStdin(tokens []sqlToken) bool {
+	start := len(tokens)
+	for start > 0 && !tokens[start-1].isPunct(";") {
+		start--
+	}
+	stmt := tokens[start:]
+	if len(stmt) == 0 || !stmt[0].isWord("COPY") {
+		return false
+	}
+	for i := 1; i+1 < len(stmt); i++ {
+		if stmt[i].isWord("FROM") && stmt[i+1].isWord("STDIN") {
+			return true
+		}
+	}
+	return false
+}
+
+// skipCopyData пропускает строки данных COPY FROM stdin до строки "\." включительно;
+// start указывает за ';' оператора COPY, данные начинаются со следующей строки
+func skipCopyData(src string, start int) (int, int, bool) {
+	lines := 0
+	pos := start
+	for {
+		newline := strings.IndexByte(src[pos:], '\n')
+		if newline < 0 {
+			return 0, 0, false
+		}
+		pos += newline + 1
+		lines++
+
+		end := strings.IndexByte(src[pos:], '\n')
+		if end < 0 {
+			end = len(src) - pos
+		}
+		if strings.TrimSuffix(src[pos:pos+end], "\r") == `\.` {
+			return pos + end, lines, true
+		}
+	}
+}
+
+// isEscapeStringPrefix сообщает, что строка PostgreSQL записана как E'...' и допускает \-экранирование
+func isEscapeStringPrefix(tokens []sqlToken, src string, pos int) bool {
+	if len(tokens) == 0 || pos == 0 {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	return last.isWord("E") && (src[pos-1] == 'E' || src[pos-1] == 'e')
+}
+
+func isWordStart(r rune, dialect Dialect) bool {
+	return r == '_' || unicode.IsLetter(r) || (r == '$' && dialect == DialectMySQL)
+}
+
+func isWordPart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,323 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDDL(t *testing.T) {
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		ddl     string
+		// wantErr is a substring of the expected error, empty when the DDL is valid
+		wantErr string
+	}{
+		{
+			name:    "postgres table",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE example (id SERIAL PRIMARY KEY, name VARCHAR(100));",
+		},
+		{
+			name:    "schema qualified table without trailing semicolon",
+			dialect: DialectPostgres,
+			ddl:     `CREATE TABLE IF NOT EXISTS public."Users" (id BIGINT, CONSTRAINT users_pk PRIMARY KEY (id))`,
+		},
+		{
+			name:    "dollar quoted function body",
+			dialect: DialectPostgres,
+			ddl: `CREATE FUNCTION touch() RETURNS trigger AS $body$
+BEGIN
+	NEW.updated_at := now(); -- ';' and ')' inside the body are not SQL of the script
+	RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql;`,
+		},
+		{
+			name:    "anonymous dollar quotes",
+			dialect: DialectPostgres,
+			ddl:     "COMMENT ON TABLE t IS $$it's (not) closed;$$; CREATE TABLE t (id INT);",
+		},
+		{
+			name:    "unterminated dollar quote",
+			dialect: DialectPostgres,
+			ddl:     "CREATE FUNCTION f() RETURNS int AS $fn$ SELECT 1; $other$ LANGUAGE sql;",
+			wantErr: "unterminated dollar-quoted string",
+		},
+		{
+			name:    "positional parameter is not a dollar quote",
+			dialect: DialectPostgres,
+			ddl:     "CREATE FUNCTION f(int) RETURNS int AS 'SELECT $1' LANGUAGE sql;",
+		},
+		{
+			name:    "escape string with backslash quote",
+			dialect: DialectPostgres,
+			ddl:     `COMMENT ON COLUMN t.name IS E'it\'s (a name'; CREATE TABLE t (name TEXT);`,
+		},
+		{
+			name:    "backslash does not escape in standard strings",
+			dialect: DialectPostgres,
+			ddl:     `COMMENT ON TABLE t IS 'C:\'; CREATE TABLE t (id INT);`,
+		},
+		{
+			name:    "doubled quote in standard string",
+			dialect: DialectPostgres,
+			ddl:     `COMMENT ON TABLE t IS 'it''s'; CREATE TABLE t (id INT);`,
+		},
+		{
+			name:    "unterminated string",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE t (id INT DEFAULT 'x);",
+			wantErr: "line 1: unterminated string literal",
+		},
+		{
+			name:    "nested block comments",
+			dialect: DialectPostgres,
+			ddl:     "/* outer /* inner ( */ still a comment ; */ CREATE TABLE t (id INT);",
+		},
+		{
+			name:    "unterminated nested block comment",
+			dialect: DialectPostgres,
+			ddl:     "/* outer /* inner */ CREATE TABLE t (id INT);",
+			wantErr: "unterminated comment",
+		},
+		{
+			name:    "mysql block comments do not nest",
+			dialect: DialectMySQL,
+			ddl:     "/* outer /* inner */ CREATE TABLE t (id INT);",
+		},
+		{
+			name:    "mysql backticks and hash comments",
+			dialect: DialectMySQL,
+			ddl: "# users table\n" +
+				"CREATE TABLE `user data` (`id` INT AUTO_INCREMENT, `order` VARCHAR(10), PRIMARY KEY (`id`)) ENGINE=InnoDB; # trailing (\n",
+		},
+		{
+			name:    "mysql backslash escapes in strings",
+			dialect: DialectMySQL,
+			ddl:     `CREATE TABLE t (name VARCHAR(10) DEFAULT 'it\'s', note TEXT COMMENT "say \"hi\"");`,
+		},
+		{
+			name:    "backticks in postgres",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE `t` (id INT);",
+			wantErr: "backtick-quoted identifiers are MySQL syntax",
+		},
+		{
+			name:    "hash is not a comment in postgres",
+			dialect: DialectPostgres,
+			ddl:     "# comment\nCREATE TABLE t (id INT);",
+			wantErr: `unexpected "#"`,
+		},
+		{
+			name:    "unbalanced closing paren",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE t (id INT));",
+			wantErr: "line 1: unexpected ')'",
+		},
+		{
+			name:    "unclosed paren",
+			dialect: DialectMySQL,
+			ddl:     "CREATE TABLE t (\n  id INT,\n  name VARCHAR(10);\n",
+			wantErr: "line 1: '(' is never closed",
+		},
+		{
+			name:    "empty column list",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE t ();",
+			wantErr: "empty column definition",
+		},
+		{
+			name:    "empty column between commas",
+			dialect: DialectMySQL,
+			ddl:     "CREATE TABLE t (\n  id INT,\n  ,\n  name TEXT\n);",
+			wantErr: "line 3: empty column definition",
+		},
+		{
+			name:    "trailing comma in column list",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE t (id INT,);",
+			wantErr: "empty column definition",
+		},
+		{
+			name:    "missing table name",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE (id INT);",
+			wantErr: "CREATE TABLE requires a table name",
+		},
+		{
+			name:    "create table as select",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE archived AS SELECT * FROM users;",
+		},
+		{
+			name:    "statement starting with a literal",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE t (id INT); 'oops';",
+			wantErr: "a statement must start with a keyword",
+		},
+		{
+			name:    "misspelled keyword",
+			dialect: DialectPostgres,
+			ddl:     "SELEC garbage;",
+			wantErr: `line 1: unknown statement "SELEC"`,
+		},
+		{
+			name:    "unknown statement",
+			dialect: DialectMySQL,
+			ddl:     "CREATE TABLE t (id INT);\nFOO BAR;",
+			wantErr: `line 2: unknown statement "FOO"`,
+		},
+		{
+			name:    "column without a type",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE t (id INT, name);",
+			wantErr: "column name has no type",
+		},
+		{
+			name:    "column with only a constraint",
+			dialect: DialectMySQL,
+			ddl:     "CREATE TABLE t (id NOT NULL);",
+			wantErr: "column id has no type",
+		},
+		{
+			name:    "repeated type",
+			dialect: DialectPostgres,
+			ddl:     "CREATE TABLE t (id int int int);",
+			wantErr: `unexpected "int" in the definition of column id`,
+		},
+		{
+			name:    "postgres multi-word and qualified types",
+			dialect: DialectPostgres,
+			ddl: `CREATE TABLE t (
+    key text,
+    price double precision NOT NULL,
+    title character varying(255) COLLATE "C",
+    tags text[] DEFAULT '{}'::text[],
+    created_at timestamp(3) without time zone DEFAULT now() NOT NULL,
+    span interval day to second,
+    status public.status_enum,
+    id bigint GENERATED BY DEFAULT AS IDENTITY,
+    EXCLUDE USING gist (span WITH =)
+);`,
+		},
+		{
+			name:    "mysql column attributes and indexes",
+			dialect: DialectMySQL,
+			ddl: "CREATE TABLE t (\n" +
+				"  id int(11) unsigned zerofill NOT NULL AUTO_INCREMENT,\n" +
+				"  name varchar(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin DEFAULT NULL,\n" +
+				"  updated_at datetime(6) ON UPDATE CURRENT_TIMESTAMP(6),\n" +
+				"  kind enum('a','b') NOT NULL,\n" +
+				"  KEY idx_name (name),\n" +
+				"  FULLTEXT KEY ft_name (name),\n" +
+				"  CONSTRAINT chk_kind CHECK (kind <> 'c')\n" +
+				");",
+		},
+		{
+			name:    "only comments",
+			dialect: DialectPostgres,
+			ddl:     "-- nothing here\n/* at all */",
+			wantErr: "DDL contains no statements",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertParseDDL(t, tc.ddl, tc.dialect, tc.wantErr)
+		})
+	}
+}
+
+func TestParseDDLDumps(t *testing.T) {
+	pgDump := `--
+-- PostgreSQL database dump
+--
+\restrict abc123
+
+SET statement_timeout = 0;
+SET client_encoding = 'UTF8';
+SELECT pg_catalog.set_config('search_path', '', false);
+
+BEGIN;
+
+CREATE TABLE public.users (
+    id integer NOT NULL,
+    name text
+);
+
+ALTER TABLE public.users OWNER TO go_init_usr;
+
+COPY public.users (id, name) FROM stdin;
+1	O'Brien (unbalanced
+2	\N
+\.
+
+INSERT INTO public.users (id, name) VALUES (3, 'seed');
+GRANT SELECT ON TABLE public.users TO readonly;
+
+COMMIT;
+
+\unrestrict abc123
+`
+
+	mysqlDump := "/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;\n" +
+		"SET NAMES utf8mb4;\n" +
+		"DROP TABLE IF EXISTS `users`;\n" +
+		"CREATE TABLE `users` (\n" +
+		"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+		"  `name` varchar(255) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" +
+		"LOCK TABLES `users` WRITE;\n" +
+		"INSERT INTO `users` VALUES (1,'O\\'Brien');\n" +
+		"UNLOCK TABLES;\n" +
+		"START TRANSACTION;\n" +
+		"COMMIT;\n"
+
+	testCases := []struct {
+		name    string
+		dialect Dialect
+		ddl     string
+		wantErr string
+	}{
+		{name: "pg_dump", dialect: DialectPostgres, ddl: pgDump},
+		{name: "mysqldump", dialect: DialectMySQL, ddl: mysqlDump},
+		{
+			name:    "unterminated copy data",
+			dialect: DialectPostgres,
+			ddl:     "COPY users (id) FROM stdin;\n1\n2\n",
+			wantErr: "line 1: COPY FROM stdin data is not terminated",
+		},
+		{
+			name:    "error line after copy data",
+			dialect: DialectPostgres,
+			ddl:     "COPY users (id) FROM stdin;\n1\n\\.\nCREATE TABLE t ();",
+			wantErr: "line 4: empty column definition",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertParseDDL(t, tc.ddl, tc.dialect, tc.wantErr)
+		})
+	}
+}
+
+func assertParseDDL(t *testing.T, ddl string, dialect Dialect, wantErr string) {
+	t.Helper()
+
+	err := ParseDDL(ddl, dialect)
+	if wantErr == "" {
+		if err != nil {
+			t.Fatalf("ParseDDL returned an error for valid DDL: %v", err)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatalf("ParseDDL accepted invalid DDL, want error containing %q", wantErr)
+	}
+	if !strings.Contains(err.Error(), wantErr) {
+		t.Fatalf("ParseDDL error = %q, want it to contain %q", err, wantErr)
+	}
+}
@@ -0,0 +1,57 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Грамматика ссылок на образы Docker (github.com/distribution/reference)
+const (
+	alphanumeric    = `[a-z0-9]+`
+	separator       = `(?:[._]|__|[-]+)`
+	pathComponent   = alphanumeric + `(?:` + separator + alphanumeric + `)*`
+	remoteName      = pathComponent + `(?:/` + pathComponent + `)*`
+	domainComponent = `(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])`
+	domainName      = domainComponent + `(?:\.` + domainComponent + `)*`
+	ipv6Address     = `\[[a-fA-F0-9:]+\]`
+	domainAndPort   = `(?:` + domainName + `|` + ipv6Address + `)(?::[0-9]+)?`
+	tag             = `[\w][\w.-]{0,127}`
+	digest          = `[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}`
+)
+
+// maxImageNameLength - ограничение длины имени репозитория (без тега и дайджеста)
+const maxImageNameLength = 255
+
+var (
+	imageReferenceRegexp = regexp.MustCompile(`^((?:` + domainAndPort + `/)?` + remoteName + `)(?::` + tag + `)?(?:@` + digest + `)?$`)
+	registryRegexp       = regexp.MustCompile(`^` + domainAndPort + `(?:/` + remoteName + `)?$`)
+)
+
+// checkImageName проверяет имя образа: [домен/]путь[:тег][@дайджест]
+func checkImageName(imageName string) error {
+	if imageName == "" {
+		return errors.New("image name must not be empty")
+	}
+
+	match := imageReferenceRegexp.FindStringSubmatch(imageName)
+	if match == nil {
+		if imageReferenceRegexp.MatchString(strings.ToLower(imageName)) {
+			return fmt.Errorf("%q is not a valid image reference: repository name must be lowercase", imageName)
+		}
+		return fmt.Errorf("%q is not a valid image reference, expected [registry/]name[:tag][@digest]", imageName)
+	}
+	if len(match[1]) > maxImageNameLength {
+		return fmt.Errorf("repository name must not be longer than %d characters", maxImageNameLength)
+	}
+	return nil
+}
+
+// checkRegistry проверяет адрес реестра: хост[:порт][/namespace]
+func checkRegistry(registry string) error {
+	if !registryRegexp.MatchString(registry) {
+		return fmt.Errorf("%q is not a valid registry, expected host[:port][/namespace]", registry)
+	}
+	return nil
+}
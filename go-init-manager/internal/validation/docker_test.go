@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckImageName(t *testing.T) {
+	testCases := []struct {
+		name  string
+		image string
+		// wantErr is a substring of the expected error, empty when the image name is valid
+		wantErr string
+	}{
+		{name: "plain name", image: "orders"},
+		{name: "namespace and tag", image: "acme/orders:1.2.3"},
+		{name: "registry with port", image: "registry.internal:5000/acme/orders:latest"},
+		{name: "ipv6 registry", image: "[::1]:5000/orders"},
+		{name: "digest", image: "orders@sha256:" + strings.Repeat("a", 64)},
+		{name: "separators", image: "acme/order__service-v2.api"},
+		{name: "empty", image: "", wantErr: "image name must not be empty"},
+		{name: "uppercase", image: "acme/Orders", wantErr: "repository name must be lowercase"},
+		{name: "trailing separator", image: "orders-", wantErr: "expected [registry/]name[:tag][@digest]"},
+		{name: "empty tag", image: "orders:", wantErr: "not a valid image reference"},
+		{name: "short digest", image: "orders@sha256:abc", wantErr: "not a valid image reference"},
+		{name: "too long", image: strings.Repeat("a", maxImageNameLength+1), wantErr: "must not be longer than 255 characters"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkImageName(tc.image)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkImageName(%q) returned an error: %v", tc.image, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("checkImageName(%q) error = %v, want it to contain %q", tc.image, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRegistry(t *testing.T) {
+	testCases := []struct {
+		name     string
+		registry string
+		wantErr  bool
+	}{
+		{name: "host", registry: "registry.local"},
+		{name: "host and port", registry: "registry.internal:5000"},
+		{name: "namespace", registry: "ghcr.io/acme"},
+		{name: "ipv6", registry: "[2001:db8::1]:443"},
+		{name: "scheme", registry: "https://registry.local", wantErr: true},
+		{name: "trailing slash", registry: "registry.local/", wantErr: true},
+		{name: "tag", registry: "registry.local/acme:1", wantErr: true},
+		{name: "leading dash", registry: "-registry.local", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkRegistry(tc.registry)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("checkRegistry(%q) error = %v, want error %v", tc.registry, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Коды ошибок валидации, возвращаются клиенту в extensions.code
+const (
+	CodeRequired            = "REQUIRED"
+	CodeInvalidValue        = "INVALID_VALUE"
+	CodeInvalidModulePath   = "INVALID_MODULE_PATH"
+	CodeInvalidPackageName  = "INVALID_PACKAGE_NAME"
+	CodeInvalidImageName    = "INVALID_IMAGE_NAME"
+	CodeInvalidRegistry     = "INVALID_REGISTRY"
+	CodeDuplicateEndpoint   = "DUPLICATE_ENDPOINT"
+	CodeUnsupportedEndpoint = "UNSUPPORTED_ENDPOINT"
+	CodeDuplicateConfigKey  = "DUPLICATE_CONFIG_KEY"
+	CodeInvalidDDL          = "INVALID_DDL"
+	CodeUnexpectedDDL       = "UNEXPECTED_DDL"
+)
+
+// FieldError describes an invalid value of one input field.
+// Path points to the field inside the mutation arguments, e.g. ["input", "endpoints", 1, "role"].
+type FieldError struct {
+	Path    []any
+	Code    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field(), e.Message)
+}
+
+// Field returns the dotted path of the field, e.g. input.endpoints[1].role
+func (e FieldError) Field() string {
+	var b strings.Builder
+	for _, elem := range e.Path {
+		switch v := elem.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", v)
+		default:
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			fmt.Fprint(&b, v)
+		}
+	}
+	return b.String()
+}
+
+// Errors is a list of field errors found in one input
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Error())
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// collector gathers field errors of one input; paths are relative to root
+type collector struct {
+	root []any
+	errs Errors
+}
+
+func (c *collector) add(code, message string, path ...any) {
+	fullPath := make([]any, 0, len(c.root)+len(path))
+	fullPath = append(append(fullPath, c.root...), path...)
+	c.errs = append(c.errs, FieldError{Path: fullPath, Code: code, Message: message})
+}
+
+// merge adds errors of a nested input that were collected with their own full paths
+func (c *collector) merge(err error) {
+	var fieldErrs Errors
+	if errors.As(err, &fieldErrs) {
+		c.errs = append(c.errs, fieldErrs...)
+	}
+}
+
+// result returns the gathered errors or nil
+func (c *collector) result() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
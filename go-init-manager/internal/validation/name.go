@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"regexp"
+
+	"golang.org/x/mod/module"
+)
+
+// majorVersionSuffix - суффикс /vN пути модуля, не являющийся именем пакета
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// checkModulePath проверяет, что имя можно записать в директиву module и использовать в импортах
+func checkModulePath(name string) error {
+	if err := module.CheckImportPath(name); err != nil {
+		return fmt.Errorf("not a valid Go module path: %w", err)
+	}
+	return nil
+}
+
+// checkPackageName проверяет, что последний элемент пути (без суффикса /vN) - допустимое имя пакета Go
+func checkPackageName(name string) error {
+	pkg := path.Base(name)
+	if dir := path.Dir(name); dir != "." && majorVersionSuffix.MatchString(pkg) {
+		pkg = path.Base(dir)
+	}
+
+	if !token.IsIdentifier(pkg) {
+		return fmt.Errorf("%q is not a valid Go package name: use letters, digits and underscores only", pkg)
+	}
+	if pkg == "_" {
+		return fmt.Errorf("%q is not a valid Go package name", pkg)
+	}
+	return nil
+}
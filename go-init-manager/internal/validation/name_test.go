@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckModulePath(t *testing.T) {
+	testCases := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "single element", path: "orders"},
+		{name: "hosted module", path: "github.com/acme/orders"},
+		{name: "major version suffix", path: "github.com/acme/orders/v2"},
+		{name: "space", path: "my service", wantErr: true},
+		{name: "leading slash", path: "/orders", wantErr: true},
+		{name: "trailing slash", path: "github.com/acme/", wantErr: true},
+		{name: "dot element", path: "github.com/./orders", wantErr: true},
+		{name: "backslash", path: `acme\orders`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkModulePath(tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("checkModulePath(%q) error = %v, want error %v", tc.path, err, tc.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "not a valid Go module path") {
+				t.Fatalf("checkModulePath(%q) error = %q, want it to name the module path", tc.path, err)
+			}
+		})
+	}
+}
+
+func TestCheckPackageName(t *testing.T) {
+	testCases := []struct {
+		name string
+		path string
+		// wantErr is a substring of the expected error, empty when the name is valid
+		wantErr string
+	}{
+		{name: "plain name", path: "orders"},
+		{name: "last element of a path", path: "github.com/acme/order_service"},
+		{name: "major version suffix is skipped", path: "github.com/acme/orders/v2"},
+		{name: "version-like single element", path: "v2"},
+		{name: "dash", path: "github.com/acme/order-service", wantErr: `"order-service" is not a valid Go package name`},
+		{name: "dash before version suffix", path: "github.com/acme/order-service/v3", wantErr: `"order-service"`},
+		{name: "leading digit", path: "acme/1orders", wantErr: `"1orders"`},
+		{name: "dotted element", path: "acme/orders.v1", wantErr: `"orders.v1"`},
+		{name: "blank identifier", path: "acme/_", wantErr: `"_" is not a valid Go package name`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkPackageName(tc.path)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkPackageName(%q) returned an error: %v", tc.path, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("checkPackageName(%q) error = %v, want it to contain %q", tc.path, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+// maxPresetNameLength соответствует размеру колонки preset_name
+const maxPresetNameLength = 255
+
+// CreatePresetInput проверяет новый пресет вместе с параметрами его шаблона
+func CreatePresetInput(input model.CreatePresetInput) error {
+	c := &collector{root: []any{"input"}}
+
+	validatePresetName(c, input.Name)
+	visibility := model.PresetVisibilityPrivate
+	if input.Visibility != nil {
+		visibility = *input.Visibility
+	}
+	validatePresetSharing(c, visibility, input.Team)
+
+	if input.Template == nil {
+		c.add(CodeRequired, "preset template must not be null", "template")
+	} else {
+		c.merge(CreateTemplateInput(*input.Template, "input", "template"))
+	}
+	return c.result()
+}
+
+// UpdatePresetInput проверяет изменение пресета.
+// visibility и team - значения пресета после применения изменения.
+func UpdatePresetInput(input model.UpdatePresetInput, visibility model.PresetVisibility, team *string) error {
+	c := &collector{root: []any{"input"}}
+
+	if input.Name != nil {
+		validatePresetName(c, *input.Name)
+	}
+	validatePresetSharing(c, visibility, team)
+	if input.Template != nil {
+		c.merge(CreateTemplateInput(*input.Template, "input", "template"))
+	}
+	return c.result()
+}
+
+func validatePresetName(c *collector, name string) {
+	switch {
+	case strings.TrimSpace(name) == "":
+		c.add(CodeRequired, "preset name must not be empty", "name")
+	case len(name) > maxPresetNameLength:
+		c.add(CodeInvalidValue, fmt.Sprintf("preset name must not be longer than %d bytes", maxPresetNameLength), "name")
+	}
+}
+
+// validatePresetSharing: пресет с видимостью TEAM должен указывать команду
+func validatePresetSharing(c *collector, visibility model.PresetVisibility, team *string) {
+	if !visibility.IsValid() {
+		c.add(CodeInvalidValue, fmt.Sprintf("unknown visibility %q", visibility), "visibility")
+		return
+	}
+	if visibility == model.PresetVisibilityTeam && (team == nil || strings.TrimSpace(*team) == "") {
+		c.add(CodeRequired, "team is required for presets shared with a team", "team")
+	}
+}
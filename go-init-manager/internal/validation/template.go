@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+// CreateTemplateInput проверяет входные данные шаблона до сохранения.
+// Все ошибки собираются за один проход, чтобы клиент мог исправить форму целиком.
+// root - путь к входным данным в аргументах, по умолчанию ["input"].
+func CreateTemplateInput(input model.CreateTemplateInput, root ...any) error {
+	if len(root) == 0 {
+		root = []any{"input"}
+	}
+	c := &collector{root: root}
+
+	validateName(c, input.Name)
+	validateEndpoints(c, input.Endpoints)
+	if input.Database != nil {
+		validateDatabase(c, input.Database)
+	}
+	if input.Docker != nil {
+		validateDocker(c, input.Docker)
+	}
+	if input.Advanced != nil {
+		validateAdvanced(c, input.Advanced)
+	}
+	return c.result()
+}
+
+// validateName проверяет имя сервиса: генератор использует его как путь модуля в go.mod
+// и как префикс импортов, а последний элемент пути - как имя пакета
+func validateName(c *collector, name string) {
+	if strings.TrimSpace(name) == "" {
+		c.add(CodeRequired, "service name must not be empty", "name")
+		return
+	}
+	if err := checkModulePath(name); err != nil {
+		c.add(CodeInvalidModulePath, err.Error(), "name")
+		return
+	}
+	if err := checkPackageName(name); err != nil {
+		c.add(CodeInvalidPackageName, err.Error(), "name")
+	}
+}
+
+// validateEndpoints проверяет комбинацию endpoint'ов: генератор создает только серверы
+// и не умеет собирать два сервера одного протокола
+func validateEndpoints(c *collector, endpoints []*model.EndpointInput) {
+	seen := make(map[model.ServiceProtocol]int, len(endpoints))
+	for i, endpoint := range endpoints {
+		if endpoint == nil {
+			c.add(CodeRequired, "endpoint must not be null", "endpoints", i)
+			continue
+		}
+		validateEndpointConfig(c, i, endpoint.Config)
+		// Перечисления проверяет GraphQL, но параметры пресетов собираются из JSON
+		if !endpoint.Protocol.IsValid() {
+			c.add(CodeInvalidValue, fmt.Sprintf("unknown protocol %q", endpoint.Protocol), "endpoints", i, "protocol")
+			continue
+		}
+		if !endpoint.Role.IsValid() {
+			c.add(CodeInvalidValue, fmt.Sprintf("unknown role %q", endpoint.Role), "endpoints", i, "role")
+			continue
+		}
+		if endpoint.Role != model.ServiceRoleServer {
+			c.add(CodeUnsupportedEndpoint,
+				fmt.Sprintf("%s %s endpoints are not supported, only SERVER endpoints can be generated", endpoint.Protocol, endpoint.Role),
+				"endpoints", i, "role")
+			continue
+		}
+		if first, ok := seen[endpoint.Protocol]; ok {
+			c.add(CodeDuplicateEndpoint,
+				fmt.Sprintf("%s server is already declared by endpoints[%d]", endpoint.Protocol, first),
+				"endpoints", i, "protocol")
+			continue
+		}
+		seen[endpoint.Protocol] = i
+	}
+}
+
+// validateEndpointConfig проверяет параметры endpoint'а: генератор получает их как объект,
+// поэтому пустые и повторяющиеся ключи потерялись бы молча
+func validateEndpointConfig(c *collector, endpointIndex int, entries []*model.ConfigEntryInput) {
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		if strings.TrimSpace(entry.Key) == "" {
+			c.add(CodeRequired, "config key must not be empty", "endpoints", endpointIndex, "config", i, "key")
+			continue
+		}
+		if seen[entry.Key] {
+			c.add(CodeDuplicateConfigKey, fmt.Sprintf("config key %q is set more than once", entry.Key),
+				"endpoints", endpointIndex, "config", i, "key")
+			continue
+		}
+		seen[entry.Key] = true
+	}
+}
+
+func validateDatabase(c *collector, database *model.DatabaseInput) {
+	if !database.Type.IsValid() {
+		c.add(CodeInvalidValue, fmt.Sprintf("unknown database type %q", database.Type), "database", "type")
+		return
+	}
+	if database.Ddl == nil || strings.TrimSpace(*database.Ddl) == "" {
+		return
+	}
+
+	var dialect Dialect
+	switch database.Type {
+	case model.DatabaseTypePostgresql:
+		dialect = DialectPostgres
+	case model.DatabaseTypeMysql:
+		dialect = DialectMySQL
+	default:
+		c.add(CodeUnexpectedDDL, fmt.Sprintf("DDL requires a database, but type is %s", database.Type),
+			"database", "ddl")
+		return
+	}
+
+	if err := ParseDDL(*database.Ddl, dialect); err != nil {
+		c.add(CodeInvalidDDL, err.Error(), "database", "ddl")
+	}
+}
+
+func validateDocker(c *collector, docker *model.DockerInput) {
+	if err := checkImageName(docker.ImageName); err != nil {
+		c.add(CodeInvalidImageName, err.Error(), "docker", "imageName")
+	}
+	if docker.Registry != nil && *docker.Registry != "" {
+		if err := checkRegistry(*docker.Registry); err != nil {
+			c.add(CodeInvalidRegistry, err.Error(), "docker", "registry")
+		}
+	}
+}
+
+func validateAdvanced(c *collector, advanced *model.AdvancedInput) {
+	if advanced.ModulePath != nil && *advanced.ModulePath != "" {
+		if err := checkModulePath(*advanced.ModulePath); err != nil {
+			c.add(CodeInvalidModulePath, err.Error(), "advanced", "modulePath")
+		}
+	}
+}
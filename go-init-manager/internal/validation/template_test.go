@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go-init/pkg/api/graphql/model"
+)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// fieldCode is the part of a FieldError the tests compare: the dotted path and the code
+type fieldCode struct {
+	Field string
+	Code  string
+}
+
+func server(protocol model.ServiceProtocol, config ...*model.ConfigEntryInput) *model.EndpointInput {
+	return &model.EndpointInput{Protocol: protocol, Role: model.ServiceRoleServer, Config: config}
+}
+
+func TestCreateTemplateInput(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input model.CreateTemplateInput
+		root  []any
+		want  []fieldCode
+	}{
+		{
+			name: "valid input",
+			input: model.CreateTemplateInput{
+				Name:      "github.com/acme/orders",
+				Endpoints: []*model.EndpointInput{server(model.ServiceProtocolGrpc), server(model.ServiceProtocolRest)},
+				Database:  &model.DatabaseInput{Type: model.DatabaseTypePostgresql, Ddl: ptr("CREATE TABLE orders (id BIGINT);")},
+				Docker:    &model.DockerInput{Registry: ptr("registry.local"), ImageName: "orders"},
+				Advanced:  &model.AdvancedInput{ModulePath: ptr("github.com/acme/orders")},
+			},
+		},
+		{
+			name:  "empty name",
+			input: model.CreateTemplateInput{Name: "  "},
+			want:  []fieldCode{{"input.name", CodeRequired}},
+		},
+		{
+			name:  "module path is checked before the package name",
+			input: model.CreateTemplateInput{Name: "acme orders"},
+			want:  []fieldCode{{"input.name", CodeInvalidModulePath}},
+		},
+		{
+			name:  "package name",
+			input: model.CreateTemplateInput{Name: "github.com/acme/order-service"},
+			want:  []fieldCode{{"input.name", CodeInvalidPackageName}},
+		},
+		{
+			name: "endpoint rules",
+			input: model.CreateTemplateInput{
+				Name: "orders",
+				Endpoints: []*model.EndpointInput{
+					server(model.ServiceProtocolGrpc),
+					nil,
+					{Protocol: model.ServiceProtocolRest, Role: model.ServiceRoleClient},
+					server(model.ServiceProtocolGrpc),
+					{Protocol: "SOAP", Role: model.ServiceRoleServer},
+					{Protocol: model.ServiceProtocolGraphql, Role: "PEER"},
+				},
+			},
+			want: []fieldCode{
+				{"input.endpoints[1]", CodeRequired},
+				{"input.endpoints[2].role", CodeUnsupportedEndpoint},
+				{"input.endpoints[3].protocol", CodeDuplicateEndpoint},
+				{"input.endpoints[4].protocol", CodeInvalidValue},
+				{"input.endpoints[5].role", CodeInvalidValue},
+			},
+		},
+		{
+			name: "endpoint config keys",
+			input: model.CreateTemplateInput{
+				Name: "orders",
+				Endpoints: []*model.EndpointInput{
+					server(model.ServiceProtocolRest,
+						&model.ConfigEntryInput{Key: "port", Value: "8080"},
+						&model.ConfigEntryInput{Key: " ", Value: "x"},
+						nil,
+						&model.ConfigEntryInput{Key: "port", Value: "8081"},
+					),
+				},
+			},
+			want: []fieldCode{
+				{"input.endpoints[0].config[1].key", CodeRequired},
+				{"input.endpoints[0].config[3].key", CodeDuplicateConfigKey},
+			},
+		},
+		{
+			name: "invalid DDL",
+			input: model.CreateTemplateInput{
+				Name:     "orders",
+				Database: &model.DatabaseInput{Type: model.DatabaseTypeMysql, Ddl: ptr("CREATE TABLE orders ();")},
+			},
+			want: []fieldCode{{"input.database.ddl", CodeInvalidDDL}},
+		},
+		{
+			name: "DDL without a database",
+			input: model.CreateTemplateInput{
+				Name:     "orders",
+				Database: &model.DatabaseInput{Type: model.DatabaseTypeNone, Ddl: ptr("CREATE TABLE orders (id INT);")},
+			},
+			want: []fieldCode{{"input.database.ddl", CodeUnexpectedDDL}},
+		},
+		{
+			name: "docker and advanced",
+			input: model.CreateTemplateInput{
+				Name:     "orders",
+				Docker:   &model.DockerInput{Registry: ptr("https://registry.local"), ImageName: "Orders"},
+				Advanced: &model.AdvancedInput{ModulePath: ptr("/orders")},
+			},
+			want: []fieldCode{
+				{"input.docker.imageName", CodeInvalidImageName},
+				{"input.docker.registry", CodeInvalidRegistry},
+				{"input.advanced.modulePath", CodeInvalidModulePath},
+			},
+		},
+		{
+			name: "custom root",
+			input: model.CreateTemplateInput{
+				Name:      "orders",
+				Endpoints: []*model.EndpointInput{server(model.ServiceProtocolGrpc), server(model.ServiceProtocolGrpc)},
+			},
+			root: []any{"preset", "template"},
+			want: []fieldCode{{"preset.template.endpoints[1].protocol", CodeDuplicateEndpoint}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CreateTemplateInput(tc.input, tc.root...)
+			if len(tc.want) == 0 {
+				if err != nil {
+					t.Fatalf("CreateTemplateInput returned an error for valid input: %v", err)
+				}
+				return
+			}
+
+			var fieldErrs Errors
+			if !errors.As(err, &fieldErrs) {
+				t.Fatalf("CreateTemplateInput error = %v, want validation.Errors", err)
+			}
+			got := make([]fieldCode, 0, len(fieldErrs))
+			for _, fieldErr := range fieldErrs {
+				got = append(got, fieldCode{fieldErr.Field(), fieldErr.Code})
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("Field errors = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
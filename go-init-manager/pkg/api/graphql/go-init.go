@@ -28,6 +28,46 @@ func (r *mutationResolver) CreateTemplate(ctx context.Context, input model.Creat
 	return response, nil
 }
 
+// RetryTemplate is the resolver for the retryTemplate field.
+func (r *mutationResolver) RetryTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
+	return r.Service.RetryTemplate(ctx, id)
+}
+
+// CancelTemplate is the resolver for the cancelTemplate field.
+func (r *mutationResolver) CancelTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
+	return r.Service.CancelTemplate(ctx, id)
+}
+
+// UpdateTemplate is the resolver for the updateTemplate field.
+func (r *mutationResolver) UpdateTemplate(ctx context.Context, id string, patch model.TemplatePatch) (*model.TemplateResponse, error) {
+	return r.Service.UpdateTemplate(ctx, id, patch)
+}
+
+// DeleteTemplate is the resolver for the deleteTemplate field.
+func (r *mutationResolver) DeleteTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
+	return r.Service.DeleteTemplate(ctx, id)
+}
+
+// CreateTemplateFromPreset is the resolver for the createTemplateFromPreset field.
+func (r *mutationResolver) CreateTemplateFromPreset(ctx context.Context, presetID string, version *int, overrides map[string]any) (*model.TemplateResponse, error) {
+	return r.Service.CreateTemplateFromPreset(ctx, presetID, version, overrides)
+}
+
+// CreatePreset is the resolver for the createPreset field.
+func (r *mutationResolver) CreatePreset(ctx context.Context, input model.CreatePresetInput) (*model.PresetResponse, error) {
+	return r.Service.CreatePreset(ctx, input)
+}
+
+// UpdatePreset is the resolver for the updatePreset field.
+func (r *mutationResolver) UpdatePreset(ctx context.Context, id string, input model.UpdatePresetInput) (*model.PresetResponse, error) {
+	return r.Service.UpdatePreset(ctx, id, input)
+}
+
+// DeletePreset is the resolver for the deletePreset field.
+func (r *mutationResolver) DeletePreset(ctx context.Context, id string) (*model.PresetResponse, error) {
+	return r.Service.DeletePreset(ctx, id)
+}
+
 // GetTemplate is the resolver for the getTemplate field.
 func (r *queryResolver) GetTemplate(ctx context.Context, id string) (*model.TemplateResponse, error) {
 	return r.Service.GetTemplate(ctx, id)
@@ -38,13 +78,65 @@ func (r *queryResolver) GetRecentTemplates(ctx context.Context, limit *int) (*mo
 	return r.Service.GetRecentTemplates(ctx, limit)
 }
 
+// Templates is the resolver for the templates field.
+func (r *queryResolver) Templates(ctx context.Context, first *int, after *string, filter *model.TemplateFilter, orderBy *model.TemplateOrder) (*model.TemplateConnection, error) {
+	return r.Service.ListTemplates(ctx, first, after, filter, orderBy)
+}
+
+// TemplateStats is the resolver for the templateStats field.
+func (r *queryResolver) TemplateStats(ctx context.Context, from *string, to *string) (*model.TemplateStats, error) {
+	return r.Service.GetTemplateStats(ctx, from, to)
+}
+
+// Preset is the resolver for the preset field.
+func (r *queryResolver) Preset(ctx context.Context, id string, version *int) (*model.PresetResponse, error) {
+	return r.Service.GetPreset(ctx, id, version)
+}
+
+// Presets is the resolver for the presets field.
+func (r *queryResolver) Presets(ctx context.Context, filter *model.PresetFilter, first *int) (*model.PresetsResponse, error) {
+	return r.Service.ListPresets(ctx, filter, first)
+}
+
+// ZipURL is the resolver for the zipUrl field.
+func (r *serviceTemplateResolver) ZipURL(ctx context.Context, obj *model.ServiceTemplate) (*string, error) {
+	return r.Service.ResolveZipURL(ctx, obj)
+}
+
+// Revisions is the resolver for the revisions field.
+func (r *serviceTemplateResolver) Revisions(ctx context.Context, obj *model.ServiceTemplate) ([]*model.TemplateRevision, error) {
+	return r.Service.ResolveRevisions(ctx, obj)
+}
+
+// TemplateStatusChanged is the resolver for the templateStatusChanged field.
+func (r *subscriptionResolver) TemplateStatusChanged(ctx context.Context, id string) (<-chan *model.ServiceTemplate, error) {
+	return r.Service.SubscribeTemplateStatus(ctx, id)
+}
+
+// ZipURL is the resolver for the zipUrl field.
+func (r *templateRevisionResolver) ZipURL(ctx context.Context, obj *model.TemplateRevision) (*string, error) {
+	return r.Service.ResolveRevisionZipURL(ctx, obj)
+}
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// ServiceTemplate returns ServiceTemplateResolver implementation.
+func (r *Resolver) ServiceTemplate() ServiceTemplateResolver { return &serviceTemplateResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// TemplateRevision returns TemplateRevisionResolver implementation.
+func (r *Resolver) TemplateRevision() TemplateRevisionResolver { return &templateRevisionResolver{r} }
+
 type (
-	mutationResolver struct{ *Resolver }
-	queryResolver    struct{ *Resolver }
+	mutationResolver         struct{ *Resolver }
+	queryResolver            struct{ *Resolver }
+	serviceTemplateResolver  struct{ *Resolver }
+	subscriptionResolver     struct{ *Resolver }
+	templateRevisionResolver struct{ *Resolver }
 )
@@ -9,13 +9,39 @@ import (
 )
 
 type AdvancedConfig struct {
-	EnableAuthentication *bool `json:"enableAuthentication,omitempty"`
-	GenerateSwaggerDocs  *bool `json:"generateSwaggerDocs,omitempty"`
+	EnableAuthentication *bool   `json:"enableAuthentication,omitempty"`
+	GenerateSwaggerDocs  *bool   `json:"generateSwaggerDocs,omitempty"`
+	ModulePath           *string `json:"modulePath,omitempty"`
+	ServiceDescription   *string `json:"serviceDescription,omitempty"`
+	EnableGraphQL        *bool   `json:"enableGraphQL,omitempty"`
+	EnableGRPC           *bool   `json:"enableGRPC,omitempty"`
 }
 
 type AdvancedInput struct {
-	EnableAuthentication *bool `json:"enableAuthentication,omitempty"`
-	GenerateSwaggerDocs  *bool `json:"generateSwaggerDocs,omitempty"`
+	EnableAuthentication *bool   `json:"enableAuthentication,omitempty"`
+	GenerateSwaggerDocs  *bool   `json:"generateSwaggerDocs,omitempty"`
+	ModulePath           *string `json:"modulePath,omitempty"`
+	ServiceDescription   *string `json:"serviceDescription,omitempty"`
+	EnableGraphQL        *bool   `json:"enableGraphQL,omitempty"`
+	EnableGRPC           *bool   `json:"enableGRPC,omitempty"`
+}
+
+type ConfigEntry struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type ConfigEntryInput struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type CreatePresetInput struct {
+	Name        string               `json:"name"`
+	Description *string              `json:"description,omitempty"`
+	Team        *string              `json:"team,omitempty"`
+	Visibility  *PresetVisibility    `json:"visibility,omitempty"`
+	Template    *CreateTemplateInput `json:"template"`
 }
 
 type CreateTemplateInput struct {
@@ -27,13 +53,17 @@ type CreateTemplateInput struct {
 }
 
 type DatabaseConfig struct {
-	Type DatabaseType `json:"type"`
-	Ddl  *string      `json:"ddl,omitempty"`
+	Type       DatabaseType `json:"type"`
+	Ddl        *string      `json:"ddl,omitempty"`
+	Migrations *bool        `json:"migrations,omitempty"`
+	Models     *bool        `json:"models,omitempty"`
 }
 
 type DatabaseInput struct {
-	Type DatabaseType `json:"type"`
-	Ddl  *string      `json:"ddl,omitempty"`
+	Type       DatabaseType `json:"type"`
+	Ddl        *string      `json:"ddl,omitempty"`
+	Migrations *bool        `json:"migrations,omitempty"`
+	Models     *bool        `json:"models,omitempty"`
 }
 
 type DockerConfig struct {
@@ -46,41 +76,181 @@ type DockerInput struct {
 	ImageName string  `json:"imageName"`
 }
 
+type DockerRegistryUsage struct {
+	Registry *string `json:"registry,omitempty"`
+	Count    int     `json:"count"`
+}
+
 type EndpointConfig struct {
 	Protocol ServiceProtocol `json:"protocol"`
 	Role     ServiceRole     `json:"role"`
+	Config   []*ConfigEntry  `json:"config,omitempty"`
+}
+
+type EndpointDatabaseUsage struct {
+	Protocol     ServiceProtocol `json:"protocol"`
+	Role         ServiceRole     `json:"role"`
+	DatabaseType DatabaseType    `json:"databaseType"`
+	Count        int             `json:"count"`
 }
 
 type EndpointInput struct {
-	Protocol ServiceProtocol `json:"protocol"`
-	Role     ServiceRole     `json:"role"`
+	Protocol ServiceProtocol     `json:"protocol"`
+	Role     ServiceRole         `json:"role"`
+	Config   []*ConfigEntryInput `json:"config,omitempty"`
 }
 
 type Mutation struct {
 }
 
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
+}
+
+type Preset struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Description   *string          `json:"description,omitempty"`
+	OwnerID       string           `json:"ownerId"`
+	Team          *string          `json:"team,omitempty"`
+	Visibility    PresetVisibility `json:"visibility"`
+	Version       int              `json:"version"`
+	LatestVersion int              `json:"latestVersion"`
+	Template      map[string]any   `json:"template"`
+	CreatedAt     string           `json:"createdAt"`
+	UpdatedAt     *string          `json:"updatedAt,omitempty"`
+}
+
+type PresetFilter struct {
+	Mine         *bool             `json:"mine,omitempty"`
+	Team         *string           `json:"team,omitempty"`
+	Visibility   *PresetVisibility `json:"visibility,omitempty"`
+	NameContains *string           `json:"nameContains,omitempty"`
+}
+
+type PresetResponse struct {
+	Success bool    `json:"success"`
+	Message *string `json:"message,omitempty"`
+	Preset  *Preset `json:"preset,omitempty"`
+}
+
+type PresetsResponse struct {
+	Success bool      `json:"success"`
+	Message *string   `json:"message,omitempty"`
+	Presets []*Preset `json:"presets,omitempty"`
+}
+
 type Query struct {
 }
 
 type ServiceTemplate struct {
-	ID        string            `json:"id"`
-	Name      string            `json:"name"`
-	Endpoints []*EndpointConfig `json:"endpoints,omitempty"`
-	Database  *DatabaseConfig   `json:"database,omitempty"`
-	Docker    *DockerConfig     `json:"docker,omitempty"`
-	Advanced  *AdvancedConfig   `json:"advanced,omitempty"`
-	CreatedAt string            `json:"createdAt"`
-	UpdatedAt *string           `json:"updatedAt,omitempty"`
-	ZipURL    *string           `json:"zipUrl,omitempty"`
-	Version   *string           `json:"version,omitempty"`
-	Status    *TemplateStatus   `json:"status,omitempty"`
-	Error     *string           `json:"error,omitempty"`
+	ID            string                  `json:"id"`
+	Name          string                  `json:"name"`
+	Endpoints     []*EndpointConfig       `json:"endpoints,omitempty"`
+	Database      *DatabaseConfig         `json:"database,omitempty"`
+	Docker        *DockerConfig           `json:"docker,omitempty"`
+	Advanced      *AdvancedConfig         `json:"advanced,omitempty"`
+	CreatedAt     string                  `json:"createdAt"`
+	UpdatedAt     *string                 `json:"updatedAt,omitempty"`
+	ZipURL        *string                 `json:"zipUrl,omitempty"`
+	Version       *string                 `json:"version,omitempty"`
+	Revision      int                     `json:"revision"`
+	Status        *TemplateStatus         `json:"status,omitempty"`
+	Error         *string                 `json:"error,omitempty"`
+	Attempts      int                     `json:"attempts"`
+	StatusHistory []*TemplateStatusChange `json:"statusHistory,omitempty"`
+	Revisions     []*TemplateRevision     `json:"revisions"`
+	// Бакет архива в хранилище publisher
+	ArchiveBucket string `json:"-"`
+	// Имя объекта архива в хранилище publisher
+	ArchiveObject string `json:"-"`
+}
+
+type Subscription struct {
+}
+
+type TemplateConnection struct {
+	Edges      []*TemplateEdge `json:"edges"`
+	PageInfo   *PageInfo       `json:"pageInfo"`
+	TotalCount int             `json:"totalCount"`
+}
+
+type TemplateEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *ServiceTemplate `json:"node"`
+}
+
+type TemplateErrorCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+type TemplateFilter struct {
+	Status        *TemplateStatus  `json:"status,omitempty"`
+	NameContains  *string          `json:"nameContains,omitempty"`
+	Protocol      *ServiceProtocol `json:"protocol,omitempty"`
+	DatabaseType  *DatabaseType    `json:"databaseType,omitempty"`
+	CreatedAfter  *string          `json:"createdAfter,omitempty"`
+	CreatedBefore *string          `json:"createdBefore,omitempty"`
+}
+
+type TemplateOrder struct {
+	Field     TemplateOrderField `json:"field"`
+	Direction OrderDirection     `json:"direction"`
+}
+
+type TemplatePatch struct {
+	Name      *string          `json:"name,omitempty"`
+	Endpoints []*EndpointInput `json:"endpoints,omitempty"`
+	Database  *DatabaseInput   `json:"database,omitempty"`
+	Docker    *DockerInput     `json:"docker,omitempty"`
+	Advanced  *AdvancedInput   `json:"advanced,omitempty"`
 }
 
 type TemplateResponse struct {
 	Success  bool             `json:"success"`
 	Message  *string          `json:"message,omitempty"`
 	Template *ServiceTemplate `json:"template,omitempty"`
+	// Причина неуспешного ответа (graphql.Failure*), пусто при успехе
+	Reason string `json:"-"`
+}
+
+type TemplateRevision struct {
+	Revision  int            `json:"revision"`
+	Config    map[string]any `json:"config"`
+	CreatedBy *string        `json:"createdBy,omitempty"`
+	CreatedAt string         `json:"createdAt"`
+	ZipURL    *string        `json:"zipUrl,omitempty"`
+	// Бакет архива ревизии в хранилище publisher
+	ArchiveBucket string `json:"-"`
+	// Имя объекта архива ревизии в хранилище publisher
+	ArchiveObject string `json:"-"`
+}
+
+type TemplateStats struct {
+	Total                int                      `json:"total"`
+	StatusCounts         []*TemplateStatusCount   `json:"statusCounts"`
+	TopEndpointDatabases []*EndpointDatabaseUsage `json:"topEndpointDatabases"`
+	DockerRegistries     []*DockerRegistryUsage   `json:"dockerRegistries"`
+	AvgCompletionSeconds *float64                 `json:"avgCompletionSeconds,omitempty"`
+	P95CompletionSeconds *float64                 `json:"p95CompletionSeconds,omitempty"`
+	FailureRate          float64                  `json:"failureRate"`
+	TopErrors            []*TemplateErrorCount    `json:"topErrors"`
+}
+
+type TemplateStatusChange struct {
+	FromStatus *TemplateStatus `json:"fromStatus,omitempty"`
+	ToStatus   TemplateStatus  `json:"toStatus"`
+	Source     string          `json:"source"`
+	ChangedAt  string          `json:"changedAt"`
+}
+
+type TemplateStatusCount struct {
+	Status TemplateStatus `json:"status"`
+	Count  int            `json:"count"`
 }
 
 type TemplatesResponse struct {
@@ -89,6 +259,14 @@ type TemplatesResponse struct {
 	Templates []*ServiceTemplate `json:"templates,omitempty"`
 }
 
+type UpdatePresetInput struct {
+	Name        *string              `json:"name,omitempty"`
+	Description *string              `json:"description,omitempty"`
+	Team        *string              `json:"team,omitempty"`
+	Visibility  *PresetVisibility    `json:"visibility,omitempty"`
+	Template    *CreateTemplateInput `json:"template,omitempty"`
+}
+
 type DatabaseType string
 
 const (
@@ -132,6 +310,90 @@ func (e DatabaseType) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type PresetVisibility string
+
+const (
+	PresetVisibilityPrivate PresetVisibility = "PRIVATE"
+	PresetVisibilityTeam    PresetVisibility = "TEAM"
+	PresetVisibilityPublic  PresetVisibility = "PUBLIC"
+)
+
+var AllPresetVisibility = []PresetVisibility{
+	PresetVisibilityPrivate,
+	PresetVisibilityTeam,
+	PresetVisibilityPublic,
+}
+
+func (e PresetVisibility) IsValid() bool {
+	switch e {
+	case PresetVisibilityPrivate, PresetVisibilityTeam, PresetVisibilityPublic:
+		return true
+	}
+	return false
+}
+
+func (e PresetVisibility) String() string {
+	return string(e)
+}
+
+func (e *PresetVisibility) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PresetVisibility(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PresetVisibility", str)
+	}
+	return nil
+}
+
+func (e PresetVisibility) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type ServiceProtocol string
 
 const (
@@ -216,6 +478,47 @@ func (e ServiceRole) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type TemplateOrderField string
+
+const (
+	TemplateOrderFieldCreatedAt TemplateOrderField = "CREATED_AT"
+	TemplateOrderFieldName      TemplateOrderField = "NAME"
+)
+
+var AllTemplateOrderField = []TemplateOrderField{
+	TemplateOrderFieldCreatedAt,
+	TemplateOrderFieldName,
+}
+
+func (e TemplateOrderField) IsValid() bool {
+	switch e {
+	case TemplateOrderFieldCreatedAt, TemplateOrderFieldName:
+		return true
+	}
+	return false
+}
+
+func (e TemplateOrderField) String() string {
+	return string(e)
+}
+
+func (e *TemplateOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TemplateOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TemplateOrderField", str)
+	}
+	return nil
+}
+
+func (e TemplateOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type TemplateStatus string
 
 const (
@@ -223,6 +526,7 @@ const (
 	TemplateStatusProcessing TemplateStatus = "PROCESSING"
 	TemplateStatusCompleted  TemplateStatus = "COMPLETED"
 	TemplateStatusFailed     TemplateStatus = "FAILED"
+	TemplateStatusCancelled  TemplateStatus = "CANCELLED"
 )
 
 var AllTemplateStatus = []TemplateStatus{
@@ -230,11 +534,12 @@ var AllTemplateStatus = []TemplateStatus{
 	TemplateStatusProcessing,
 	TemplateStatusCompleted,
 	TemplateStatusFailed,
+	TemplateStatusCancelled,
 }
 
 func (e TemplateStatus) IsValid() bool {
 	switch e {
-	case TemplateStatusPending, TemplateStatusProcessing, TemplateStatusCompleted, TemplateStatusFailed:
+	case TemplateStatusPending, TemplateStatusProcessing, TemplateStatusCompleted, TemplateStatusFailed, TemplateStatusCancelled:
 		return true
 	}
 	return false
@@ -0,0 +1,218 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v5.28.2
+// source: archive_publisher.proto
+
+package grpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ArchivePublisherClient is the client API for ArchivePublisher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ArchivePublisherClient interface {
+	// Метод для стриминга архива чанками
+	StreamArchive(ctx context.Context, opts ...grpc.CallOption) (ArchivePublisher_StreamArchiveClient, error)
+	// Удаление сохраненного архива. Идемпотентно: отсутствующий архив считается удаленным
+	DeleteArchive(ctx context.Context, in *DeleteArchiveRequest, opts ...grpc.CallOption) (*DeleteArchiveResponse, error)
+	// Выдача пресигнированной ссылки на скачивание архива
+	GetDownloadURL(ctx context.Context, in *GetDownloadURLRequest, opts ...grpc.CallOption) (*GetDownloadURLResponse, error)
+}
+
+type archivePublisherClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewArchivePublisherClient(cc grpc.ClientConnInterface) ArchivePublisherClient {
+	return &archivePublisherClient{cc}
+}
+
+func (c *archivePublisherClient) StreamArchive(ctx context.Context, opts ...grpc.CallOption) (ArchivePublisher_StreamArchiveClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ArchivePublisher_ServiceDesc.Streams[0], "/archive.ArchivePublisher/StreamArchive", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &archivePublisherStreamArchiveClient{stream}
+	return x, nil
+}
+
+type ArchivePublisher_StreamArchiveClient interface {
+	Send(*ArchiveChunk) error
+	CloseAndRecv() (*StreamResponse, error)
+	grpc.ClientStream
+}
+
+type archivePublisherStreamArchiveClient struct {
+	grpc.ClientStream
+}
+
+func (x *archivePublisherStreamArchiveClient) Send(m *ArchiveChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *archivePublisherStreamArchiveClient) CloseAndRecv() (*StreamResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *archivePublisherClient) DeleteArchive(ctx context.Context, in *DeleteArchiveRequest, opts ...grpc.CallOption) (*DeleteArchiveResponse, error) {
+	out := new(DeleteArchiveResponse)
+	err := c.cc.Invoke(ctx, "/archive.ArchivePublisher/DeleteArchive", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *archivePublisherClient) GetDownloadURL(ctx context.Context, in *GetDownloadURLRequest, opts ...grpc.CallOption) (*GetDownloadURLResponse, error) {
+	out := new(GetDownloadURLResponse)
+	err := c.cc.Invoke(ctx, "/archive.ArchivePublisher/GetDownloadURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ArchivePublisherServer is the server API for ArchivePublisher service.
+// All implementations must embed UnimplementedArchivePublisherServer
+// for forward compatibility
+type ArchivePublisherServer interface {
+	// Метод для стриминга архива чанками
+	StreamArchive(ArchivePublisher_StreamArchiveServer) error
+	// Удаление сохраненного архива. Идемпотентно: отсутствующий архив считается удаленным
+	DeleteArchive(context.Context, *DeleteArchiveRequest) (*DeleteArchiveResponse, error)
+	// Выдача пресигнированной ссылки на скачивание архива
+	GetDownloadURL(context.Context, *GetDownloadURLRequest) (*GetDownloadURLResponse, error)
+	mustEmbedUnimplementedArchivePublisherServer()
+}
+
+// UnimplementedArchivePublisherServer must be embedded to have forward compatible implementations.
+type UnimplementedArchivePublisherServer struct {
+}
+
+func (UnimplementedArchivePublisherServer) StreamArchive(ArchivePublisher_StreamArchiveServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamArchive not implemented")
+}
+func (UnimplementedArchivePublisherServer) DeleteArchive(context.Context, *DeleteArchiveRequest) (*DeleteArchiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteArchive not implemented")
+}
+func (UnimplementedArchivePublisherServer) GetDownloadURL(context.Context, *GetDownloadURLRequest) (*GetDownloadURLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDownloadURL not implemented")
+}
+func (UnimplementedArchivePublisherServer) mustEmbedUnimplementedArchivePublisherServer() {}
+
+// UnsafeArchivePublisherServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ArchivePublisherServer will
+// result in compilation errors.
+type UnsafeArchivePublisherServer interface {
+	mustEmbedUnimplementedArchivePublisherServer()
+}
+
+func RegisterArchivePublisherServer(s grpc.ServiceRegistrar, srv ArchivePublisherServer) {
+	s.RegisterService(&ArchivePublisher_ServiceDesc, srv)
+}
+
+func _ArchivePublisher_StreamArchive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ArchivePublisherServer).StreamArchive(&archivePublisherStreamArchiveServer{stream})
+}
+
+type ArchivePublisher_StreamArchiveServer interface {
+	SendAndClose(*StreamResponse) error
+	Recv() (*ArchiveChunk, error)
+	grpc.ServerStream
+}
+
+type archivePublisherStreamArchiveServer struct {
+	grpc.ServerStream
+}
+
+func (x *archivePublisherStreamArchiveServer) SendAndClose(m *StreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *archivePublisherStreamArchiveServer) Recv() (*ArchiveChunk, error) {
+	m := new(ArchiveChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _ArchivePublisher_DeleteArchive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteArchiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArchivePublisherServer).DeleteArchive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/archive.ArchivePublisher/DeleteArchive",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArchivePublisherServer).DeleteArchive(ctx, req.(*DeleteArchiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArchivePublisher_GetDownloadURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDownloadURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArchivePublisherServer).GetDownloadURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/archive.ArchivePublisher/GetDownloadURL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArchivePublisherServer).GetDownloadURL(ctx, req.(*GetDownloadURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ArchivePublisher_ServiceDesc is the grpc.ServiceDesc for ArchivePublisher service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ArchivePublisher_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "archive.ArchivePublisher",
+	HandlerType: (*ArchivePublisherServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DeleteArchive",
+			Handler:    _ArchivePublisher_DeleteArchive_Handler,
+		},
+		{
+			MethodName: "GetDownloadURL",
+			Handler:    _ArchivePublisher_GetDownloadURL_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamArchive",
+			Handler:       _ArchivePublisher_StreamArchive_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "archive_publisher.proto",
+}
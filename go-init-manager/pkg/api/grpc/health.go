@@ -0,0 +1,11 @@
+package go_init_generate
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// RegisterHealthService регистрирует сервис здоровья, статусы которого задает healthServer
+func RegisterHealthService(server *grpc.Server, healthServer grpc_health_v1.HealthServer) {
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+}
@@ -0,0 +1,179 @@
+// Package health serves the liveness and readiness endpoints and the gRPC health service of the go-init services.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check reports an error if a dependency of the service is not available
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// CheckResult is the outcome of one check
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Статусы в ответах /healthz и /readyz
+const (
+	StatusUp   = "UP"
+	StatusDown = "DOWN"
+)
+
+// readiness is the outcome of one round of checks
+type readiness struct {
+	ready   bool
+	results map[string]CheckResult
+}
+
+// Checker runs the readiness checks of the service for /readyz and the gRPC health service.
+// Liveness only shows that the process serves requests: an outage of a dependency makes the service
+// unready, but must not get it restarted.
+type Checker struct {
+	cfg    *Config
+	log    *logger.Logger
+	grpc   *grpchealth.Server
+	checks []namedCheck
+
+	dependencyUp *prometheus.GaugeVec
+
+	// running is set while Run is active; last holds the result of its latest round of checks
+	running atomic.Bool
+	last    atomic.Pointer[readiness]
+
+	shuttingDown atomic.Bool
+	shutdownOnce sync.Once
+	drainedAt    time.Time
+}
+
+// NewChecker creates a checker; the gRPC health status is NOT_SERVING until Run evaluates the checks.
+// Check results are exported as the <metricNamespace>_dependency_up gauge, e.g. go_init_manager_dependency_up
+func NewChecker(cfg *Config, log *logger.Logger, metricNamespace string) *Checker {
+	grpcServer := grpchealth.NewServer()
+	grpcServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	return &Checker{cfg: cfg, log: log, grpc: grpcServer, dependencyUp: newDependencyUp(metricNamespace)}
+}
+
+// Add registers a readiness check; checks are added before Run and the servers start
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// GRPCServer returns the gRPC health service reporting the readiness of the service
+func (c *Checker) GRPCServer() healthpb.HealthServer {
+	return c.grpc
+}
+
+// Ready runs every check with Config.CheckTimeout. The service is ready when all checks pass
+// and it is not shutting down.
+func (c *Checker) Ready(ctx context.Context) (bool, map[string]CheckResult) {
+	results := make(map[string]CheckResult, len(c.checks))
+	ready := !c.shuttingDown.Load()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.cfg.CheckTimeout)
+			defer cancel()
+			err := nc.check(checkCtx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				ready = false
+				results[nc.name] = CheckResult{Status: StatusDown, Error: err.Error()}
+				c.dependencyUp.WithLabelValues(nc.name).Set(0)
+				return
+			}
+			results[nc.name] = CheckResult{Status: StatusUp}
+			c.dependencyUp.WithLabelValues(nc.name).Set(1)
+		}()
+	}
+	wg.Wait()
+	return ready, results
+}
+
+// Run evaluates the checks every Config.Interval until ctx is cancelled, updates the gRPC health status
+// and keeps the result for /readyz, so probes do not hit the dependencies on every request.
+// Services without a gRPC server need not run it: without Run /readyz runs the checks on every request
+func (c *Checker) Run(ctx context.Context) {
+	c.log.Info(fmt.Sprintf("Health checker started, checking %d dependencies every %s", len(c.checks), c.cfg.Interval))
+	c.running.Store(true)
+	defer func() {
+		c.running.Store(false)
+		c.last.Store(nil)
+	}()
+
+	poll := time.NewTicker(c.cfg.Interval)
+	defer poll.Stop()
+
+	for {
+		c.update(ctx)
+		select {
+		case <-ctx.Done():
+			c.log.Info("Health checker stopped")
+			return
+		case <-poll.C:
+		}
+	}
+}
+
+// update sets the gRPC health status from the checks, keeps the result for /readyz and logs the failed checks
+func (c *Checker) update(ctx context.Context) {
+	ready, results := c.Ready(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	c.last.Store(&readiness{ready: ready, results: results})
+	for name, result := range results {
+		if result.Status == StatusDown {
+			c.log.Warn(fmt.Sprintf("Readiness check %s failed: %s", name, result.Error))
+		}
+	}
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if !ready {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	// После Shutdown статус gRPC health больше не меняется
+	c.grpc.SetServingStatus("", status)
+}
+
+// lastReadiness returns the result of the latest round of Run, or runs the checks when Run is not active.
+// Shutdown makes the service unready at once, without waiting for the next round.
+func (c *Checker) lastReadiness(ctx context.Context) (bool, map[string]CheckResult) {
+	if last := c.last.Load(); last != nil && c.running.Load() {
+		return last.ready && !c.shuttingDown.Load(), last.results
+	}
+	return c.Ready(ctx)
+}
+
+// Shutdown makes the service unready for good and waits Config.ShutdownDelay, so that load balancers
+// stop sending requests before the servers stop. Every server calls it before stopping;
+// the delay is counted from the first call.
+func (c *Checker) Shutdown() {
+	c.shutdownOnce.Do(func() {
+		c.shuttingDown.Store(true)
+		c.grpc.Shutdown()
+		c.drainedAt = time.Now().Add(c.cfg.ShutdownDelay)
+		c.log.Info(fmt.Sprintf("Service is shutting down, reporting not ready for %s", c.cfg.ShutdownDelay))
+	})
+	time.Sleep(time.Until(c.drainedAt))
+}
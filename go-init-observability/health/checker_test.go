@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gitlab.com/go-init/go-init-common/default/logger"
+)
+
+func newTestChecker() *Checker {
+	return newTestCheckerWithConfig(&Config{})
+}
+
+func newTestCheckerWithConfig(cfg *Config) *Checker {
+	log := &logger.Logger{Log: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	return NewChecker(cfg, log, "go_init_test")
+}
+
+func TestCheckerReady(t *testing.T) {
+	checker := newTestChecker()
+	checker.Add("postgres", func(context.Context) error { return nil })
+	checker.Add("kafka", func(context.Context) error { return errors.New("no brokers") })
+
+	ready, results := checker.Ready(context.Background())
+	if ready {
+		t.Fatal("Checker is ready with a failed check")
+	}
+	if results["postgres"].Status != StatusUp || results["kafka"].Status != StatusDown {
+		t.Fatalf("Check results = %+v, want postgres UP and kafka DOWN", results)
+	}
+	if results["kafka"].Error != "no brokers" {
+		t.Fatalf("Kafka error = %q, want the check error", results["kafka"].Error)
+	}
+
+	if up := testutil.ToFloat64(checker.dependencyUp.WithLabelValues("kafka")); up != 0 {
+		t.Fatalf("go_init_test_dependency_up{check=kafka} = %v, want 0", up)
+	}
+	if up := testutil.ToFloat64(checker.dependencyUp.WithLabelValues("postgres")); up != 1 {
+		t.Fatalf("go_init_test_dependency_up{check=postgres} = %v, want 1", up)
+	}
+}
+
+func TestCheckerShutdown(t *testing.T) {
+	checker := newTestChecker()
+	checker.Add("postgres", func(context.Context) error { return nil })
+
+	if ready, _ := checker.Ready(context.Background()); !ready {
+		t.Fatal("Checker is not ready with passing checks")
+	}
+	checker.Shutdown()
+	if ready, _ := checker.Ready(context.Background()); ready {
+		t.Fatal("Checker is still ready after Shutdown")
+	}
+}
+
+func TestNewCheckerReusesMetric(t *testing.T) {
+	// A second checker of the same service must not fail on the already registered gauge
+	first := newTestChecker()
+	second := newTestChecker()
+	if first.dependencyUp != second.dependencyUp {
+		t.Fatal("Checkers of the same namespace export different gauges")
+	}
+}
+
+func TestReadinessServesLastRun(t *testing.T) {
+	checker := newTestCheckerWithConfig(&Config{Interval: time.Hour})
+	var calls atomic.Int32
+	checker.Add("postgres", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	probe := func() int {
+		rec := httptest.NewRecorder()
+		checker.serveReadiness(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+		return rec.Code
+	}
+
+	t.Run("checks run on every request without Run", func(t *testing.T) {
+		probe()
+		probe()
+		if got := calls.Load(); got != 2 {
+			t.Fatalf("Checks ran %d times for two requests, want 2", got)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		checker.Run(ctx)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for checker.last.Load() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("Run did not complete its first round of checks")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Run("requests get the result of Run", func(t *testing.T) {
+		before := calls.Load()
+		for range 3 {
+			if code := probe(); code != http.StatusOK {
+				t.Fatalf("Readiness status = %d, want %d", code, http.StatusOK)
+			}
+		}
+		if got := calls.Load(); got != before {
+			t.Fatalf("Checks ran %d times while Run is active, want none", got-before)
+		}
+	})
+
+	t.Run("shutdown is reported without waiting for Run", func(t *testing.T) {
+		checker.Shutdown()
+		if code := probe(); code != http.StatusServiceUnavailable {
+			t.Fatalf("Readiness status after Shutdown = %d, want %d", code, http.StatusServiceUnavailable)
+		}
+	})
+
+	cancel()
+	<-stopped
+
+	t.Run("checks run again after Run stopped", func(t *testing.T) {
+		before := calls.Load()
+		probe()
+		if got := calls.Load(); got != before+1 {
+			t.Fatalf("Checks ran %d times for one request after Run stopped, want 1", got-before)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+package health
+
+import "time"
+
+// Config настройки проверок готовности сервиса
+type Config struct {
+	// CheckTimeout ограничение времени одной проверки зависимости
+	CheckTimeout time.Duration `yaml:"check_timeout" default:"2s"`
+	// Interval период проверок, по результатам которых обновляются статус gRPC health и ответ /readyz
+	Interval time.Duration `yaml:"interval" default:"10s"`
+	// ShutdownDelay сколько сервис сообщает о неготовности перед остановкой серверов,
+	// чтобы балансировщик успел перестать направлять в него запросы
+	ShutdownDelay time.Duration `yaml:"shutdown_delay" default:"5s"`
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// LivenessPath отвечает 200, пока процесс обслуживает запросы
+	LivenessPath = "/healthz"
+	// ReadinessPath отвечает 200, если все зависимости доступны, иначе 503
+	ReadinessPath = "/readyz"
+)
+
+// Response is the body of /healthz and /readyz
+type Response struct {
+	Status       string                 `json:"status"`
+	ShuttingDown bool                   `json:"shuttingDown,omitempty"`
+	Checks       map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Routes serves /healthz and /readyz and passes other requests to next.
+// The endpoints are served before next, so they need no authentication.
+func (c *Checker) Routes(next http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivenessPath, c.serveLiveness)
+	mux.HandleFunc(ReadinessPath, c.serveReadiness)
+	mux.Handle("/", next)
+	return mux
+}
+
+func (c *Checker) serveLiveness(w http.ResponseWriter, _ *http.Request) {
+	writeResponse(w, http.StatusOK, &Response{Status: StatusUp})
+}
+
+func (c *Checker) serveReadiness(w http.ResponseWriter, r *http.Request) {
+	ready, results := c.lastReadiness(r.Context())
+	response := &Response{
+		Status:       StatusUp,
+		ShuttingDown: c.shuttingDown.Load(),
+		Checks:       results,
+	}
+	code := http.StatusOK
+	if !ready {
+		response.Status = StatusDown
+		code = http.StatusServiceUnavailable
+	}
+	writeResponse(w, code, response)
+}
+
+func writeResponse(w http.ResponseWriter, code int, response *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,26 @@
+package health
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newDependencyUp registers the <namespace>_dependency_up gauge of the service.
+// A gauge registered by an earlier checker of the same service is reused.
+func newDependencyUp(namespace string) *prometheus.GaugeVec {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "dependency_up",
+		Help:      "Result of the last readiness check of a dependency: 1 if available, 0 if not.",
+	}, []string{"check"})
+
+	if err := prometheus.Register(gauge); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			return registered.ExistingCollector.(*prometheus.GaugeVec)
+		}
+		panic(err)
+	}
+	return gauge
+}
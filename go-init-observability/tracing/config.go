@@ -0,0 +1,21 @@
+package tracing
+
+// Экспортеры спанов
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Config настройки трассировки OpenTelemetry.
+// Контекст трассировки передается дальше и при выключенном экспорте, поэтому трассировка не рвется на сервисе без экспортера
+type Config struct {
+	// Exporter куда отправляются спаны: otlp (OTLP/gRPC коллектор, например Jaeger), stdout или none
+	Exporter string `yaml:"exporter" default:"none"`
+	// Endpoint адрес OTLP/gRPC коллектора
+	Endpoint string `yaml:"endpoint" default:"localhost:4317"`
+	// Insecure подключение к коллектору без TLS
+	Insecure bool `yaml:"insecure"`
+	// SampleRatio доля записываемых трассировок, начатых в сервисе; продолжения чужих трассировок следуют решению вызывающего
+	SampleRatio float64 `yaml:"sample_ratio" default:"1"`
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"context"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// InjectEvent adds the trace context of ctx to the event as the traceparent and tracestate extension attributes
+// of the CloudEvents distributed tracing extension
+func InjectEvent(ctx context.Context, event *ce.Event) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	for key, value := range carrier {
+		event.SetExtension(key, value)
+	}
+}
+
+// EventContext holds the trace context extension attributes of a CloudEvent; it is embedded into
+// the structs the consumers decode CloudEvents into
+type EventContext struct {
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+}
+
+// Extract returns ctx with the remote span of the event, so that spans started from it continue the producer's trace
+func (e EventContext) Extract(ctx context.Context) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier{
+		"traceparent": e.TraceParent,
+		"tracestate":  e.TraceState,
+	})
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataCarrier adapts gRPC metadata to the propagation.TextMapCarrier interface
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// InjectMetadata returns ctx with the trace context added to the outgoing gRPC metadata
+func InjectMetadata(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// ExtractMetadata returns ctx with the remote span of the incoming gRPC metadata,
+// so that spans started from it continue the caller's trace
+func ExtractMetadata(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"go-init-observability/health"
+	"go-init-observability/tracing"
+
 	"gitlab.com/go-init/go-init-common/default/grpcpkg"
 	"gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/logger"
@@ -21,11 +24,15 @@ type AppConfig struct {
 	GrpcServ grpcpkg.ServerConfig `yaml:"grpc_server"`
 	Kafka    kafka.Config         `yaml:"kafka"`
 	MinIO    minio.Config         `yaml:"minio"`
+	Tracing  tracing.Config       `yaml:"tracing"`
+	Health   health.Config        `yaml:"health"`
 }
 
 func GetConfig() *AppConfig {
 	config := &AppConfig{}
 	c.OpenConfig(&config)
 	defaults.SetDefaults(&config.Logger)
+	defaults.SetDefaults(&config.Tracing)
+	defaults.SetDefaults(&config.Health)
 	return config
 }
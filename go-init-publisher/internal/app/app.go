@@ -3,19 +3,25 @@ package app
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"sync"
 	"time"
 
+	"go-init-observability/health"
+	"go-init-observability/tracing"
 	"go-init-publisher/config"
 	"go-init-publisher/internal/grpc"
 	"go-init-publisher/internal/storage"
 	pb "go-init-publisher/pkg/api/grpc"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/s3/minio"
 
 	"gitlab.com/go-init/go-init-common/default/closer"
+	myhttp "gitlab.com/go-init/go-init-common/default/http"
+	myserver "gitlab.com/go-init/go-init-common/default/http/server"
 	"gitlab.com/go-init/go-init-common/default/logger"
 )
 
@@ -24,15 +30,18 @@ type App struct {
 	log           *logger.Logger
 	KafkaProducer *kafka.ClientConfig
 	grpcServer    *pb.Server
+	srv           *http.Server
 	fileStorage   *storage.FileStorage
 	minioClient   *minio.Client
 	minioStorage  *storage.MinIOStorage
+	health        *health.Checker
 }
 
 const (
 	serviceName        = "go-init-publisher"
 	shutDownTimeOut    = time.Second * 5
-	defaultStoragePath = "./storage" // Путь по умолчанию для хранения файлов
+	metricNamespace    = "go_init_publisher" // префикс метрик сервиса
+	defaultStoragePath = "./storage"         // Путь по умолчанию для хранения файлов
 )
 
 func New(ctx context.Context) (*App, error) {
@@ -49,11 +58,14 @@ func (a *App) initDeps(ctx context.Context) error {
 		a.initConfig,
 		a.initLogger,
 		a.initCloser,
+		a.initTracing,
 		a.initKafka,
 		a.initFileStorage,
 		a.initMinioClient,
 		a.initMinioStorage,
 		a.initGrpcServer,
+		a.initHealth,
+		a.initHttpServer,
 		a.initServices,
 	}
 	for _, f := range inits {
@@ -89,6 +101,22 @@ func (a *App) initCloser(_ context.Context) error {
 	return nil
 }
 
+// initTracing настраивает экспорт спанов; оставшиеся спаны отправляются при остановке
+func (a *App) initTracing(ctx context.Context) error {
+	shutdown, err := tracing.Init(ctx, &a.cfg.Tracing, serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	closer.Add(func() error {
+		cancelCtx, cancel := context.WithTimeout(context.Background(), shutDownTimeOut)
+		defer cancel()
+		return shutdown(cancelCtx)
+	})
+	a.log.InfoContext(ctx, "Трассировка инициализирована",
+		logger.String("exporter", a.cfg.Tracing.Exporter))
+	return nil
+}
+
 func (a *App) initFileStorage(ctx context.Context) error {
 	if a.log == nil || a.cfg == nil {
 		return fmt.Errorf("logger or config not initialized")
@@ -162,7 +190,7 @@ func (a *App) initMinioStorage(ctx context.Context) error {
 	return nil
 }
 
-// getKafkaTopic возвращает ID топика Kafka для публикации событий о готовности архива
+// getKafkaTopic возвращает имя топика Kafka для публикации событий о готовности архива
 func (a *App) getKafkaTopic() string {
 	const defaultTopic = "go-init-done"
 	const archiveDoneTopicID = "go-init-done"
@@ -172,8 +200,8 @@ func (a *App) getKafkaTopic() string {
 		for _, t := range a.cfg.Kafka.ProducerConfig.Topic {
 			if t.Id == archiveDoneTopicID && t.IsEnabled {
 				a.log.Info("Using Kafka topic from config",
-					logger.String("topic", t.Id))
-				return t.Id
+					logger.String("topic", t.Name))
+				return t.Name
 			}
 		}
 	}
@@ -211,6 +239,13 @@ func (a *App) initGrpcServer(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize gRPC server: %w", err)
 	}
 
+	closer.Add(func() error {
+		a.health.Shutdown()
+		grpcServer.Stop()
+		a.log.Info("gRPC сервер остановлен")
+		return nil
+	})
+
 	a.grpcServer = grpcServer
 	a.log.InfoContext(ctx, "gRPC сервер инициализирован",
 		logger.String("port", a.cfg.GrpcServ.Port))
@@ -229,6 +264,59 @@ func (a *App) runGrpcServer() error {
 	return nil
 }
 
+// initHealth регистрирует проверки зависимостей для /readyz и gRPC health:
+// доступ к бакету MinIO и доступность брокеров Kafka
+func (a *App) initHealth(ctx context.Context) error {
+	a.health = health.NewChecker(&a.cfg.Health, a.log, metricNamespace)
+	a.health.Add("minio", a.minioStorage.CheckBucket)
+	if a.KafkaProducer.ProducerIsEnabled() {
+		a.health.Add("kafka", a.KafkaProducer.Client.Ping)
+	}
+	pb.RegisterHealthService(a.grpcServer.GetGRPCServer(), a.health.GRPCServer())
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	go a.health.Run(healthCtx)
+	closer.Add(func() error {
+		cancel()
+		return nil
+	})
+	a.log.InfoContext(ctx, "Проверки готовности зарегистрированы")
+	return nil
+}
+
+// initHttpServer создает HTTP сервер с Prometheus метриками (/metrics) и проверками /health, /healthz и /readyz
+func (a *App) initHttpServer(ctx context.Context) error {
+	s := myserver.NewServer(&a.cfg.HttpServ, nil, promhttp.Handler(), myhttp.CollectHandlers())
+	s.Handler = a.health.Routes(s.Handler)
+	closer.Add(func() error {
+		a.health.Shutdown()
+		cancelCtx, cancel := context.WithTimeout(ctx, shutDownTimeOut)
+		defer cancel()
+		if err := s.Shutdown(cancelCtx); err != nil {
+			return fmt.Errorf("failed to stop http server: %w", err)
+		}
+		a.log.Info("HTTP сервер остановлен")
+		return nil
+	})
+
+	a.srv = &s.Server
+	a.log.InfoContext(ctx, "HTTP сервер инициализирован",
+		logger.String("addr", a.srv.Addr))
+	return nil
+}
+
+func (a *App) runHttpServer() error {
+	a.log.Info("Запуск HTTP сервера",
+		logger.String("addr", a.srv.Addr))
+
+	if err := a.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		a.log.Error("Ошибка HTTP сервера",
+			logger.Error(err))
+		return err
+	}
+	return nil
+}
+
 func (a *App) initServices(ctx context.Context) error {
 	if a.log == nil || a.cfg == nil || a.minioClient == nil || a.KafkaProducer == nil || a.fileStorage == nil || a.minioStorage == nil {
 		a.log.ErrorContext(ctx, "Ошибка инициализации сервисов: зависимости не инициализированы")
@@ -270,6 +358,13 @@ func (a *App) Run() error {
 		}
 	}()
 
+	// HTTP сервер отдает только метрики, его ошибка не останавливает прием архивов
+	go func() {
+		if err := a.runHttpServer(); err != nil {
+			a.log.Error(fmt.Sprintf("Failed to start http server: %v", err))
+		}
+	}()
+
 	wg.Wait()
 	return nil
 }
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+
+	"go-init-publisher/internal/storage"
+	pb "go-init-publisher/pkg/api/grpc"
+
+	"github.com/google/uuid"
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DeleteArchive удаляет архив шаблона из MinIO.
+// Удаление отсутствующего объекта не считается ошибкой, поэтому менеджер может безопасно повторять запрос.
+func (s *ArchiveStreamService) DeleteArchive(ctx context.Context, req *pb.DeleteArchiveRequest) (*pb.DeleteArchiveResponse, error) {
+	// ID архива - это UUID шаблона; проверка не дает удалить произвольный объект бакета
+	archiveID, err := uuid.Parse(req.GetArchiveId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid archive_id: %v", err)
+	}
+
+	objectName := archiveID.String() + storage.FileExtensionZip
+	if err := s.minioStorage.DeleteArchive(ctx, objectName); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to delete archive %s: %v", objectName, err)
+	}
+
+	s.log.InfoContext(ctx, "Архив удален из MinIO",
+		logger.String("archive_id", archiveID.String()),
+		logger.String("object_name", objectName))
+
+	return &pb.DeleteArchiveResponse{ObjectName: objectName}, nil
+}
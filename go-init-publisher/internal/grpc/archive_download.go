@@ -0,0 +1,48 @@
+package grpc
+
+import (
+	"context"
+	"path"
+	"strings"
+	"time"
+
+	"go-init-publisher/internal/storage"
+	pb "go-init-publisher/pkg/api/grpc"
+
+	"gitlab.com/go-init/go-init-common/default/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetDownloadURL выдает свежую пресигнированную ссылку на архив.
+// Менеджер хранит только бакет и имя объекта и запрашивает ссылку при чтении шаблона.
+func (s *ArchiveStreamService) GetDownloadURL(ctx context.Context, req *pb.GetDownloadURLRequest) (*pb.GetDownloadURLResponse, error) {
+	bucket := req.GetBucketName()
+	if bucket == "" {
+		bucket = s.minioStorage.DefaultBucket()
+	}
+	// Ссылки подписываются только для бакета архивов, а не для любого бакета MinIO
+	if bucket != s.minioStorage.DefaultBucket() {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown bucket %q", bucket)
+	}
+
+	objectName := req.GetObjectName()
+	if objectName == "" || path.Clean(objectName) != objectName || strings.HasPrefix(objectName, "..") {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid object_name %q", objectName)
+	}
+
+	expiresAt := time.Now().Add(storage.PresignedURLExpiry)
+	url, err := s.minioStorage.GeneratePresignedURL(ctx, objectName, storage.PresignedURLExpiry)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to generate download URL for %s: %v", objectName, err)
+	}
+
+	s.log.InfoContext(ctx, "Выдана ссылка на скачивание архива",
+		logger.String("object_name", objectName),
+		logger.String("expires_at", expiresAt.UTC().Format(time.RFC3339)))
+
+	return &pb.GetDownloadURLResponse{
+		Url:       url,
+		ExpiresAt: expiresAt.UTC().Format(time.RFC3339),
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"go-init-observability/tracing"
 	"go-init-publisher/internal/storage"
 	"io"
 	"os"
@@ -15,6 +16,8 @@ import (
 
 	"github.com/google/uuid"
 	"gitlab.com/go-init/go-init-common/default/logger"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -49,9 +52,12 @@ func NewArchiveStreamService(
 	}
 }
 
-// StreamArchive handles streaming archive chunks from the client
-func (s *ArchiveStreamService) StreamArchive(stream pb.ArchivePublisher_StreamArchiveServer) error {
-	ctx := stream.Context()
+// StreamArchive handles streaming archive chunks from the client.
+// The span of the call continues the generator's trace from the gRPC metadata.
+func (s *ArchiveStreamService) StreamArchive(stream pb.ArchivePublisher_StreamArchiveServer) (err error) {
+	ctx, span := tracing.Start(tracing.ExtractMetadata(stream.Context()), "publisher.stream_archive",
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer func() { tracing.End(span, err) }()
 	s.log.InfoContext(ctx, "Получен новый запрос на стриминг архива")
 
 	// Проверка включена ли функция локального сохранения
@@ -61,6 +67,10 @@ func (s *ArchiveStreamService) StreamArchive(stream pb.ArchivePublisher_StreamAr
 	var chunks [][]byte
 	var totalBytes int64
 	startTime := time.Now()
+	result := streamResultError
+	defer func() {
+		streamDuration.WithLabelValues(result).Observe(time.Since(startTime).Seconds())
+	}()
 	var archiveID string
 	var expectedHash string
 
@@ -80,6 +90,7 @@ func (s *ArchiveStreamService) StreamArchive(stream pb.ArchivePublisher_StreamAr
 		// Сохраняем ID архива из первого чанка
 		if archiveID == "" && chunk.ArchiveId != "" {
 			archiveID = chunk.ArchiveId
+			span.SetAttributes(tracing.TemplateID(archiveID))
 			s.log.InfoContext(ctx, "Получен ID архива из запроса",
 				logger.String("archive_id", archiveID))
 		}
@@ -101,6 +112,8 @@ func (s *ArchiveStreamService) StreamArchive(stream pb.ArchivePublisher_StreamAr
 		}
 	}
 
+	streamBytes.Observe(float64(totalBytes))
+
 	// Объединяем все чанки в один массив байтов
 	archiveData := make([]byte, 0, totalBytes)
 	for _, chunk := range chunks {
@@ -120,7 +133,10 @@ func (s *ArchiveStreamService) StreamArchive(stream pb.ArchivePublisher_StreamAr
 		actualHashHex := hex.EncodeToString(actualHash[:])
 
 		if actualHashHex != expectedHash {
+			result = streamResultHashMismatch
+			hashMismatches.Inc()
 			errMsg := "Ошибка проверки целостности: хеш полученного архива не совпадает с ожидаемым"
+			span.SetStatus(codes.Error, "archive hash mismatch")
 			s.log.ErrorContext(ctx, errMsg,
 				logger.String("archive_id", archiveID),
 				logger.String("expected_hash", expectedHash),
@@ -160,6 +176,7 @@ func (s *ArchiveStreamService) StreamArchive(stream pb.ArchivePublisher_StreamAr
 	}
 
 	// Отправляем ответ
+	result = streamResultStored
 	duration := time.Since(startTime)
 	s.log.InfoContext(ctx, "Стриминг архива завершен",
 		logger.String("archive_id", archiveID),
@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Результаты приема архива, значения метки result
+const (
+	streamResultStored       = "stored"
+	streamResultHashMismatch = "hash_mismatch"
+	streamResultError        = "error"
+)
+
+var (
+	streamBytes = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "go_init_publisher_stream_bytes",
+		Help:    "Size of archives received from the generator.",
+		Buckets: prometheus.ExponentialBuckets(16*1024, 4, 8),
+	})
+	streamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "go_init_publisher_stream_duration_seconds",
+		Help:    "Time from the start of an archive stream to the response, by result (stored, hash_mismatch, error).",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"result"})
+	hashMismatches = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "go_init_publisher_hash_mismatches_total",
+		Help: "Received archives whose SHA-256 does not match the hash sent by the generator.",
+	})
+)
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-init-observability/tracing"
+
+	ce "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+)
+
+// newCloudEvent сериализует данные в CloudEvent так же, как kafka.ClientConfig.Produce,
+// добавляя контекст трассировки из ctx в расширения traceparent и tracestate
+func newCloudEvent(ctx context.Context, eventType, schema string, data any) ([]byte, error) {
+	event := ce.NewEvent()
+	event.SetType(eventType)
+	event.SetExtension("correlation-id", uuid.New().String())
+	event.SetSource(EventSourcePublisher)
+	event.SetDataSchema(schema)
+	event.SetTime(time.Now())
+	event.SetID(uuid.New().String())
+	tracing.InjectEvent(ctx, &event)
+
+	if err := event.SetData(ce.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("failed to set event data: %w", err)
+	}
+	return event.MarshalJSON()
+}
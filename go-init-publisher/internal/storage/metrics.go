@@ -0,0 +1,11 @@
+package storage
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var produceErrors = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "go_init_publisher_kafka_produce_errors_total",
+	Help: "archive-ready events that could not be published to Kafka.",
+})
@@ -7,11 +7,17 @@ import (
 	"net/url"
 	"time"
 
+	"go-init-observability/tracing"
+
 	"github.com/google/uuid"
 	"github.com/minio/minio-go/v7"
+	"github.com/twmb/franz-go/pkg/kgo"
 	common "gitlab.com/go-init/go-init-common/default/kafka"
 	"gitlab.com/go-init/go-init-common/default/logger"
 	minio_client "gitlab.com/go-init/go-init-common/default/s3/minio"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -54,6 +60,11 @@ func NewMinIOStorage(minioClient *minio_client.Client, kafkaProducer *common.Cli
 	}
 }
 
+// DefaultBucket возвращает бакет, в который сохраняются архивы
+func (s *MinIOStorage) DefaultBucket() string {
+	return s.config.DefaultBucket
+}
+
 // SaveArchive сохраняет архив в MinIO и публикует метаданные в Kafka
 func (s *MinIOStorage) SaveArchive(ctx context.Context, archiveID string, data []byte) (string, error) {
 	// Проверка идентификатора архива
@@ -71,7 +82,9 @@ func (s *MinIOStorage) SaveArchive(ctx context.Context, archiveID string, data [
 		logger.String("object_name", objectName),
 		logger.String("bucket", s.config.DefaultBucket))
 
-	info, err := s.minioClient.UploadObject(ctx, s.config.DefaultBucket, objectName, data, ContentTypeZip)
+	uploadCtx, span := s.startUpload(ctx, objectName)
+	info, err := s.minioClient.UploadObject(uploadCtx, s.config.DefaultBucket, objectName, data, ContentTypeZip)
+	tracing.End(span, err)
 	if err != nil {
 		s.log.ErrorContext(ctx, "Ошибка загрузки архива в MinIO",
 			logger.String("object_name", objectName),
@@ -100,7 +113,9 @@ func (s *MinIOStorage) SaveArchiveFromReader(ctx context.Context, reader io.Read
 		logger.String("bucket", s.config.DefaultBucket),
 		logger.Int64("size", size))
 
-	info, err := s.minioClient.UploadObjectFromReader(ctx, s.config.DefaultBucket, objectName, reader, size, ContentTypeZip)
+	uploadCtx, span := s.startUpload(ctx, objectName)
+	info, err := s.minioClient.UploadObjectFromReader(uploadCtx, s.config.DefaultBucket, objectName, reader, size, ContentTypeZip)
+	tracing.End(span, err)
 	if err != nil {
 		s.log.ErrorContext(ctx, "Ошибка загрузки архива в MinIO из потока",
 			logger.String("object_name", objectName),
@@ -117,6 +132,26 @@ func (s *MinIOStorage) SaveArchiveFromReader(ctx context.Context, reader io.Read
 	return objectName, nil
 }
 
+// healthProbeObject объект, наличие которого запрашивает CheckBucket; его может и не быть
+const healthProbeObject = ".healthz"
+
+// CheckBucket проверяет доступ к бакету: отсутствие пробного объекта не ошибка, а недоступный MinIO,
+// отсутствующий бакет или неверные ключи - ошибка
+func (s *MinIOStorage) CheckBucket(ctx context.Context) error {
+	_, err := s.minioClient.ObjectExists(ctx, s.config.DefaultBucket, healthProbeObject)
+	return err
+}
+
+// startUpload начинает спан загрузки объекта в MinIO
+func (s *MinIOStorage) startUpload(ctx context.Context, objectName string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "minio.upload",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("minio.bucket", s.config.DefaultBucket),
+			attribute.String("minio.object", objectName),
+		))
+}
+
 // createArchiveMetadata создает и настраивает метаданные архива
 func (s *MinIOStorage) createArchiveMetadata(ctx context.Context, info minio.UploadInfo, archiveID string, objectName string) *minio_client.ArchiveMetadata {
 	// Создание метаданных объекта
@@ -145,30 +180,48 @@ func (s *MinIOStorage) createArchiveMetadata(ctx context.Context, info minio.Upl
 	return &archiveMetadata
 }
 
-// publishMetadataToKafka публикует метаданные архива в Kafka
+// publishMetadataToKafka публикует метаданные архива в Kafka.
+// Событие отправляется напрямую через клиент продюсера, чтобы ошибки доставки попадали в метрики.
 func (s *MinIOStorage) publishMetadataToKafka(ctx context.Context, metadata *minio_client.ArchiveMetadata, objectName string) {
 	// Публикация метаданных в Kafka, если продюсер включен
-	if s.kafkaProducer.IsEnabled() {
-		s.log.InfoContext(ctx, "Публикация метаданных архива в Kafka",
-			logger.String("topic", s.topic),
-			logger.String("archive_id", metadata.ID))
-
-		event := &common.ProduceEvent{
-			Type:    EventTypeArchiveReady,
-			Schema:  EventSchemaArchive,
-			Source:  EventSourcePublisher,
-			TopicID: s.topic,
-		}
+	if !s.kafkaProducer.IsEnabled() || !s.kafkaProducer.ProducerIsEnabled() {
+		s.log.InfoContext(ctx, "Kafka продюсер отключен, пропуск публикации метаданных")
+		return
+	}
 
-		event.SetCorrelationID(uuid.New().String())
-		event.SetData(metadata)
+	s.log.InfoContext(ctx, "Публикация метаданных архива в Kafka",
+		logger.String("topic", s.topic),
+		logger.String("archive_id", metadata.ID))
 
-		s.kafkaProducer.Produce(ctx, event)
-		s.log.InfoContext(ctx, "Метаданные архива опубликованы в Kafka",
-			logger.String("object_name", objectName))
-	} else {
-		s.log.InfoContext(ctx, "Kafka продюсер отключен, пропуск публикации метаданных")
+	// Спан отправки становится родителем обработки события в менеджере
+	ctx, span := tracing.Start(ctx, "kafka.publish "+EventTypeArchiveReady,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.String("messaging.destination.name", s.topic)))
+	defer span.End()
+
+	payload, err := newCloudEvent(ctx, EventTypeArchiveReady, EventSchemaArchive, metadata)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		produceErrors.Inc()
+		s.log.ErrorContext(ctx, "Ошибка сериализации события о готовности архива",
+			logger.String("archive_id", metadata.ID),
+			logger.Error(err))
+		return
 	}
+
+	record := &kgo.Record{Topic: s.topic, Value: payload}
+	// Отправка асинхронная, как в kafka.ClientConfig.Produce: ответ клиенту не ждет подтверждения брокера
+	s.kafkaProducer.Client.Produce(context.Background(), record, func(_ *kgo.Record, err error) {
+		if err != nil {
+			produceErrors.Inc()
+			s.log.Error("Ошибка публикации события о готовности архива",
+				logger.String("archive_id", metadata.ID),
+				logger.Error(err))
+		}
+	})
+	s.log.InfoContext(ctx, "Метаданные архива опубликованы в Kafka",
+		logger.String("object_name", objectName))
 }
 
 // DownloadArchive загружает архив из MinIO
@@ -2,12 +2,10 @@ package grpc
 
 import (
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
-// RegisterHealthService регистрирует сервис здоровья
-func RegisterHealthService(server *grpc.Server) {
-	healthServer := health.NewServer()
+// RegisterHealthService регистрирует сервис здоровья, статусы которого задает healthServer
+func RegisterHealthService(server *grpc.Server, healthServer grpc_health_v1.HealthServer) {
 	grpc_health_v1.RegisterHealthServer(server, healthServer)
 }
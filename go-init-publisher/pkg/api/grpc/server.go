@@ -21,11 +21,9 @@ type ServerConfig struct {
 
 // NewServer создает новый экземпляр сервера
 func NewServer(config ServerConfig) (*Server, error) {
+	// Сервис здоровья регистрирует приложение: его статус зависит от проверок зависимостей
 	server := grpc.NewServer()
 
-	// Регистрируем сервис здоровья
-	RegisterHealthService(server)
-
 	// Регистрируем reflection сервис для удобства отладки
 	reflection.Register(server)
 